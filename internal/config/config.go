@@ -36,16 +36,37 @@ type HookConfig struct {
 	PostCreate []CommandTemplate `yaml:"post_create" json:"post_create"`
 }
 
+// CaptureMatcher selects the captures a rule applies to. Every non-empty
+// field must match. Title and Content are regular expressions, Clipboard is
+// `url`, `text`, `any`, or a MIME pattern such as `text/*`, Cwd is a glob
+// matched against the working directory's path or base name, Repo is the
+// name of the enclosing git repository, Time is a `HH:MM-HH:MM` range that
+// may wrap past midnight, and Weekdays lists days such as `mon` or `friday`.
 type CaptureMatcher struct {
-	Template       string `yaml:"template"        json:"template"`
-	UpstreamPrefix string `yaml:"upstream_prefix" json:"upstream_prefix"`
-}
-
+	Template       string   `yaml:"template"            json:"template"`
+	UpstreamPrefix string   `yaml:"upstream_prefix"     json:"upstream_prefix"`
+	Title          string   `yaml:"title,omitempty"     json:"title,omitempty"`
+	Content        string   `yaml:"content,omitempty"   json:"content,omitempty"`
+	Clipboard      string   `yaml:"clipboard,omitempty" json:"clipboard,omitempty"`
+	Cwd            string   `yaml:"cwd,omitempty"       json:"cwd,omitempty"`
+	Repo           string   `yaml:"repo,omitempty"      json:"repo,omitempty"`
+	Time           string   `yaml:"time,omitempty"      json:"time,omitempty"`
+	Weekdays       []string `yaml:"weekdays,omitempty"  json:"weekdays,omitempty"`
+}
+
+// CaptureAction describes what a matching rule adds to a capture. Subdir,
+// Filename, and Pin are taken from the last matching rule that sets them;
+// Filename is a template rendered with the title, date, cwd, and repo, and
+// Pin names a text pin or, prefixed with `task:`, a task pin.
 type CaptureAction struct {
-	Clipboard   bool           `yaml:"clipboard"    json:"clipboard"`
-	Tags        []string       `yaml:"tags"         json:"tags"`
-	FrontMatter map[string]any `yaml:"front_matter" json:"front_matter"`
-	Fields      map[string]any `yaml:"fields"       json:"fields"`
+	Clipboard   bool             `yaml:"clipboard"          json:"clipboard"`
+	Tags        []string         `yaml:"tags"               json:"tags"`
+	FrontMatter map[string]any   `yaml:"front_matter"       json:"front_matter"`
+	Fields      map[string]any   `yaml:"fields"             json:"fields"`
+	Subdir      string           `yaml:"subdir,omitempty"   json:"subdir,omitempty"`
+	Filename    string           `yaml:"filename,omitempty" json:"filename,omitempty"`
+	Pin         string           `yaml:"pin,omitempty"      json:"pin,omitempty"`
+	Hook        *CommandTemplate `yaml:"hook,omitempty"     json:"hook,omitempty"`
 }
 
 type CaptureRule struct {
@@ -57,10 +78,74 @@ type CaptureConfig struct {
 	Rules []CaptureRule `yaml:"rules" json:"rules"`
 }
 
+// InboxConfig describes where unsorted notes collect. Notes directly inside
+// one of the folders, or anywhere in the vault with a filename matching one of
+// the glob patterns, are part of the inbox.
+type InboxConfig struct {
+	Folders  []string `yaml:"folders"  json:"folders"`
+	Patterns []string `yaml:"patterns" json:"patterns"`
+}
+
+// TrashConfig controls how long trashed notes are kept. Retention accepts Go
+// durations as well as day ("30d") and week ("4w") suffixes; notes trashed
+// longer ago are purged when the TUI starts. An empty retention keeps the
+// trash forever.
+type TrashConfig struct {
+	Retention string `yaml:"retention,omitempty" json:"retention,omitempty"`
+}
+
+// HistoryConfig turns on the snapshot store under .an/history, which keeps a
+// copy of a note before an rewrites it. MaxVersions caps the snapshots kept
+// per note and MaxAge drops older ones, in the same format as
+// trash.retention. Zero values keep every snapshot.
+type HistoryConfig struct {
+	Enabled     bool   `yaml:"enabled,omitempty"      json:"enabled,omitempty"`
+	MaxVersions int    `yaml:"max_versions,omitempty" json:"max_versions,omitempty"`
+	MaxAge      string `yaml:"max_age,omitempty"      json:"max_age,omitempty"`
+}
+
+// WithDefaults fills in the default inbox locations: the `inbox/` and
+// `echoes/` folders and scratch captures.
+func (c InboxConfig) WithDefaults() InboxConfig {
+	if len(c.Folders) == 0 {
+		c.Folders = []string{defaultInboxDir, "echoes"}
+	}
+	if len(c.Patterns) == 0 {
+		c.Patterns = []string{"scratch-*.md"}
+	}
+	return c
+}
+
+type ReviewBucket struct {
+	Name  string `yaml:"name"  json:"name"`
+	After string `yaml:"after" json:"after"`
+}
+
+type ReviewQueue struct {
+	Description string              `yaml:"description" json:"description"`
+	Buckets     []ReviewBucket      `yaml:"buckets"     json:"buckets"`
+	Tags        []string            `yaml:"tags"        json:"tags"`
+	Metadata    map[string][]string `yaml:"metadata"    json:"metadata"`
+	MinAge      string              `yaml:"min_age"     json:"min_age"`
+	Limit       int                 `yaml:"limit"       json:"limit"`
+	Template    string              `yaml:"template"    json:"template"`
+}
+
 type ReviewConfig struct {
-	Enable     bool   `yaml:"enable"    json:"enable"`
-	Directory  string `yaml:"directory" json:"directory"`
-	enabledSet bool   `yaml:"-"         json:"-"`
+	Enable     bool                   `yaml:"enable"    json:"enable"`
+	Directory  string                 `yaml:"directory" json:"directory"`
+	Queues     map[string]ReviewQueue `yaml:"queues"    json:"queues"`
+	enabledSet bool                   `yaml:"-"         json:"-"`
+}
+
+// QueueNames returns the configured review queue preset names in sorted order.
+func (cfg ReviewConfig) QueueNames() []string {
+	names := make([]string, 0, len(cfg.Queues))
+	for name := range cfg.Queues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func (cfg *ReviewConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -99,6 +184,9 @@ type Workspace struct {
 	Hooks          HookConfig                `yaml:"hooks"           json:"hooks"`
 	Review         ReviewConfig              `yaml:"review"          json:"review"`
 	Capture        CaptureConfig             `yaml:"capture"         json:"capture"`
+	Inbox          InboxConfig               `yaml:"inbox"           json:"inbox"`
+	Trash          TrashConfig               `yaml:"trash,omitempty" json:"trash,omitempty"`
+	History        HistoryConfig             `yaml:"history,omitempty" json:"history,omitempty"`
 }
 
 type Config struct {
@@ -123,6 +211,7 @@ type ViewDefinition struct {
 const (
 	defaultWorkspaceName = "default"
 	defaultReviewDir     = "reviews"
+	defaultInboxDir      = "inbox"
 )
 
 var ValidModes = map[string]bool{
@@ -392,6 +481,7 @@ func syncWorkspaceWithViper(ws *Workspace) {
 	viper.Set("workspace_hooks", ws.Hooks)
 	viper.Set("review", ws.Review)
 	viper.Set("capture", ws.Capture)
+	viper.Set("history", ws.History)
 	if ws.Capture.Rules == nil {
 		viper.Set("capture_rules", []CaptureRule{})
 	} else {
@@ -808,6 +898,36 @@ func (cfg *Config) HandleSubdir(subdirName string) {
 	}
 }
 
+// EnsureSubdir makes sure subdirName is a configured subdirectory before
+// notes are written into it, following the workspace filesystem mode
+// without exiting: strict mode refuses unknown subdirectories, free mode
+// adds them, and confirm mode adds them only when confirm agrees.
+func (cfg *Config) EnsureSubdir(subdirName string, confirm func() bool) error {
+	ws, err := cfg.ActiveWorkspace()
+	if err != nil {
+		return err
+	}
+	if ws.HasSubdir(subdirName) {
+		return nil
+	}
+
+	switch ws.FileSystemMode {
+	case "strict":
+		return fmt.Errorf(
+			"subdirectory %q does not exist; in strict mode add it with `an add-subdir %s`",
+			subdirName,
+			subdirName,
+		)
+	case "free":
+		return cfg.AddSubdir(subdirName)
+	default:
+		if confirm == nil || !confirm() {
+			return fmt.Errorf("subdirectory %q does not exist", subdirName)
+		}
+		return cfg.AddSubdir(subdirName)
+	}
+}
+
 func (cfg *Config) getConfirmation(subdirName string) {
 	var response string
 	for {
@@ -210,6 +210,62 @@ func TestLoadReviewOverrides(t *testing.T) {
 	}
 }
 
+func TestLoadReviewQueues(t *testing.T) {
+	home := t.TempDir()
+	cfgData := map[string]any{
+		"current_workspace": "main",
+		"workspaces": map[string]any{
+			"main": map[string]any{
+				"vaultdir": filepath.Join(home, "vault"),
+				"fsmode":   "strict",
+				"review": map[string]any{
+					"queues": map[string]any{
+						"reading": map[string]any{
+							"description": "Literature notes",
+							"tags":        []string{"reading"},
+							"metadata":    map[string]any{"status": []string{"draft"}},
+							"min_age":     "2d",
+							"limit":       5,
+							"template":    "review-weekly",
+							"buckets": []map[string]any{
+								{"name": "fresh", "after": "3d"},
+								{"name": "old", "after": "2w"},
+							},
+						},
+						"atoms": map[string]any{"tags": []string{"atom"}},
+					},
+				},
+			},
+		},
+	}
+
+	writeConfigFile(t, home, cfgData)
+
+	cfg, err := config.Load(home)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	ws := cfg.MustWorkspace()
+	if !ws.Review.Enable {
+		t.Fatalf("expected review to stay enabled when only queues are set")
+	}
+	if names := ws.Review.QueueNames(); len(names) != 2 || names[0] != "atoms" || names[1] != "reading" {
+		t.Fatalf("unexpected queue names: %v", names)
+	}
+
+	reading := ws.Review.Queues["reading"]
+	if reading.Limit != 5 || reading.MinAge != "2d" || reading.Template != "review-weekly" {
+		t.Fatalf("unexpected reading queue: %+v", reading)
+	}
+	if len(reading.Buckets) != 2 || reading.Buckets[1].Name != "old" || reading.Buckets[1].After != "2w" {
+		t.Fatalf("unexpected reading buckets: %+v", reading.Buckets)
+	}
+	if got := reading.Metadata["status"]; len(got) != 1 || got[0] != "draft" {
+		t.Fatalf("unexpected reading metadata: %+v", reading.Metadata)
+	}
+}
+
 func TestLoadCaptureRules(t *testing.T) {
 	home := t.TempDir()
 	cfgData := map[string]any{
@@ -734,3 +790,50 @@ func TestWorkspaceSwitchPersists(t *testing.T) {
 		t.Fatalf("expected persisted current workspace to be secondary, got %q", reloaded.CurrentWorkspace)
 	}
 }
+
+func TestConfigEnsureSubdirFollowsFileSystemMode(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgData := map[string]any{
+		"current_workspace": "main",
+		"workspaces": map[string]any{
+			"main": map[string]any{
+				"vaultdir": filepath.Join(home, "vault"),
+				"editor":   "nvim",
+				"fsmode":   "strict",
+				"subdirs":  []string{"existing"},
+			},
+		},
+	}
+	writeConfigFile(t, home, cfgData)
+
+	cfg, err := config.Load(home)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	ws := cfg.MustWorkspace()
+
+	if err := cfg.EnsureSubdir("existing", nil); err != nil {
+		t.Fatalf("expected a known subdirectory to pass, got %v", err)
+	}
+	if err := cfg.EnsureSubdir("fresh", func() bool { return true }); err == nil {
+		t.Fatal("expected strict mode to refuse an unknown subdirectory")
+	}
+
+	ws.FileSystemMode = "confirm"
+	if err := cfg.EnsureSubdir("fresh", func() bool { return false }); err == nil {
+		t.Fatal("expected a declined confirmation to refuse the subdirectory")
+	}
+	if err := cfg.EnsureSubdir("fresh", func() bool { return true }); err != nil {
+		t.Fatalf("expected a confirmed subdirectory to be added, got %v", err)
+	}
+
+	ws.FileSystemMode = "free"
+	if err := cfg.EnsureSubdir("loose", nil); err != nil {
+		t.Fatalf("expected free mode to add the subdirectory, got %v", err)
+	}
+	if !slices.Contains(ws.SubDirs, "fresh") || !slices.Contains(ws.SubDirs, "loose") {
+		t.Fatalf("expected subdirectories to be added: %#v", ws.SubDirs)
+	}
+}
@@ -0,0 +1,153 @@
+package dedupe
+
+import "sort"
+
+// Note is one note to compare.
+type Note struct {
+	Path string
+	Body string
+}
+
+// Options tune duplicate detection.
+type Options struct {
+	// Threshold is the estimated similarity, from 0 to 1, a pair needs to be
+	// reported.
+	Threshold float64
+	// ShingleSize is the number of consecutive words per shingle.
+	ShingleSize int
+	// MinShingles skips notes too short to compare meaningfully, such as
+	// notes holding nothing but a template heading.
+	MinShingles int
+	// Distinct reports pairs that were marked as not duplicates. Those pairs
+	// are never reported, though both notes may still join a cluster through
+	// other notes.
+	Distinct func(a, b string) bool
+}
+
+// DefaultOptions returns the options used by 'an dedupe'.
+func DefaultOptions() Options {
+	return Options{Threshold: 0.6, ShingleSize: 3, MinShingles: 5}
+}
+
+// Pair is two notes whose similarity reached the threshold.
+type Pair struct {
+	A, B       string
+	Similarity float64
+}
+
+// Cluster is a group of likely duplicates, connected by pairs above the
+// threshold.
+type Cluster struct {
+	Notes      []string
+	Pairs      []Pair
+	Similarity float64
+}
+
+// Find fingerprints the notes and returns clusters of likely duplicates,
+// most similar first. Pairs within a cluster are sorted the same way.
+func Find(notes []Note, opts Options) []Cluster {
+	if opts.ShingleSize < 1 {
+		opts.ShingleSize = DefaultOptions().ShingleSize
+	}
+
+	var paths []string
+	var sigs []Signature
+	for _, note := range notes {
+		shingles := Shingles(note.Body, opts.ShingleSize)
+		if len(shingles) == 0 || len(shingles) < opts.MinShingles {
+			continue
+		}
+		paths = append(paths, note.Path)
+		sigs = append(sigs, Sign(shingles))
+	}
+
+	// Notes sharing any band bucket are candidates.
+	candidates := make(map[[2]int]struct{})
+	for b := 0; b < bands; b++ {
+		buckets := make(map[uint64][]int)
+		for i, sig := range sigs {
+			key := sig.band(b)
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, members := range buckets {
+			for x := 0; x < len(members); x++ {
+				for y := x + 1; y < len(members); y++ {
+					candidates[[2]int{members[x], members[y]}] = struct{}{}
+				}
+			}
+		}
+	}
+
+	parent := make([]int, len(paths))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	var pairs []Pair
+	for candidate := range candidates {
+		i, j := candidate[0], candidate[1]
+		similarity := Similarity(sigs[i], sigs[j])
+		if similarity < opts.Threshold {
+			continue
+		}
+		a, b := paths[i], paths[j]
+		if a > b {
+			a, b = b, a
+		}
+		if opts.Distinct != nil && opts.Distinct(a, b) {
+			continue
+		}
+		pairs = append(pairs, Pair{A: a, B: b, Similarity: similarity})
+		parent[root(i)] = root(j)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+
+	index := make(map[string]int, len(paths))
+	for i, path := range paths {
+		index[path] = i
+	}
+	byRoot := make(map[int]*Cluster)
+	var clusters []*Cluster
+	for _, pair := range pairs {
+		r := root(index[pair.A])
+		cluster, ok := byRoot[r]
+		if !ok {
+			cluster = &Cluster{Similarity: pair.Similarity}
+			byRoot[r] = cluster
+			clusters = append(clusters, cluster)
+		}
+		cluster.Pairs = append(cluster.Pairs, pair)
+	}
+
+	out := make([]Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		seen := make(map[string]bool)
+		for _, pair := range cluster.Pairs {
+			for _, path := range []string{pair.A, pair.B} {
+				if !seen[path] {
+					seen[path] = true
+					cluster.Notes = append(cluster.Notes, path)
+				}
+			}
+		}
+		sort.Strings(cluster.Notes)
+		out = append(out, *cluster)
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+package dedupe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const decisionsFile = "dedupe.json"
+
+// DecisionsPath returns where the "not duplicate" marks of a vault are kept.
+func DecisionsPath(vault string) string {
+	return filepath.Join(vault, ".an", decisionsFile)
+}
+
+// Decisions remembers pairs of notes marked as not duplicates, so later runs
+// stop reporting them. Pairs are stored by vault-relative path.
+type Decisions struct {
+	vault    string
+	path     string
+	distinct map[[2]string]struct{}
+}
+
+// LoadDecisions reads the decisions of the vault. A missing file yields no
+// decisions.
+func LoadDecisions(vault string) (*Decisions, error) {
+	vault = strings.TrimSpace(vault)
+	if vault == "" {
+		return nil, fmt.Errorf("vault directory is not configured")
+	}
+
+	d := &Decisions{
+		vault:    filepath.Clean(vault),
+		path:     DecisionsPath(vault),
+		distinct: make(map[[2]string]struct{}),
+	}
+
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return d, nil
+		}
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return d, nil
+	}
+
+	var raw struct {
+		NotDuplicate [][2]string `json:"not_duplicate"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse dedupe decisions %s: %w", d.path, err)
+	}
+	for _, pair := range raw.NotDuplicate {
+		d.distinct[d.key(pair[0], pair[1])] = struct{}{}
+	}
+	return d, nil
+}
+
+// MarkDistinct records that a and b are not duplicates.
+func (d *Decisions) MarkDistinct(a, b string) {
+	d.distinct[d.key(a, b)] = struct{}{}
+}
+
+// Distinct reports whether a and b were marked as not duplicates.
+func (d *Decisions) Distinct(a, b string) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.distinct[d.key(a, b)]
+	return ok
+}
+
+// Save writes the decisions, replacing the previous file atomically.
+func (d *Decisions) Save() error {
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return err
+	}
+
+	pairs := make([][2]string, 0, len(d.distinct))
+	for pair := range d.distinct {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	payload := struct {
+		NotDuplicate [][2]string `json:"not_duplicate"`
+	}{NotDuplicate: pairs}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := d.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.path)
+}
+
+// key orders the pair and makes both paths vault-relative.
+func (d *Decisions) key(a, b string) [2]string {
+	a, b = d.rel(a), d.rel(b)
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+func (d *Decisions) rel(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(d.vault, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
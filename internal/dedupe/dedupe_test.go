@@ -0,0 +1,100 @@
+package dedupe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	original = "Spaced repetition works because each review interrupts forgetting " +
+		"just before the memory fades, and every successful recall makes the next " +
+		"interval longer. Schedule cards by difficulty and keep sessions short."
+	reworded = "Spaced repetition works because each review interrupts forgetting " +
+		"just before the memory fades, and every successful recall makes the next " +
+		"interval longer. Schedule cards by difficulty and keep daily sessions brief."
+	unrelated = "The build pipeline caches modules between runs, so a clean checkout " +
+		"only downloads dependencies once and tests start within seconds of a push."
+)
+
+func TestSimilarityTracksShingleOverlap(t *testing.T) {
+	a := Sign(Shingles(original, 3))
+	if got := Similarity(a, a); got != 1 {
+		t.Fatalf("expected identical texts to match fully, got %v", got)
+	}
+	if got := Similarity(a, Sign(Shingles(reworded, 3))); got < 0.6 {
+		t.Fatalf("expected a light rewording to stay similar, got %v", got)
+	}
+	if got := Similarity(a, Sign(Shingles(unrelated, 3))); got > 0.1 {
+		t.Fatalf("expected unrelated texts to differ, got %v", got)
+	}
+
+	shingles := Shingles("Hello, **World**! hello world", 2)
+	if _, ok := shingles["hello world"]; !ok || len(shingles) != 2 {
+		t.Fatalf("expected markup to be dropped and shingles deduplicated, got %v", shingles)
+	}
+}
+
+func TestFindClustersDuplicates(t *testing.T) {
+	notes := []Note{
+		{Path: "a.md", Body: original},
+		{Path: "b.md", Body: reworded},
+		{Path: "c.md", Body: original + "\n"},
+		{Path: "d.md", Body: unrelated},
+		{Path: "e.md", Body: "## Relations"},
+		{Path: "f.md", Body: "## Relations"},
+	}
+
+	clusters := Find(notes, DefaultOptions())
+	if len(clusters) != 1 {
+		t.Fatalf("expected one cluster, got %#v", clusters)
+	}
+	cluster := clusters[0]
+	if got := strings.Join(cluster.Notes, ","); got != "a.md,b.md,c.md" {
+		t.Fatalf("unexpected cluster members %s", got)
+	}
+	if first := cluster.Pairs[0]; first.A != "a.md" || first.B != "c.md" || first.Similarity != 1 {
+		t.Fatalf("expected the exact copy first, got %#v", first)
+	}
+	if cluster.Similarity != 1 {
+		t.Fatalf("expected the cluster to report its closest pair, got %v", cluster.Similarity)
+	}
+
+	opts := DefaultOptions()
+	opts.Distinct = func(a, b string) bool { return a == "a.md" && b == "c.md" }
+	clusters = Find(notes, opts)
+	for _, pair := range clusters[0].Pairs {
+		if pair.A == "a.md" && pair.B == "c.md" {
+			t.Fatalf("expected distinct pairs to be skipped")
+		}
+	}
+}
+
+func TestDecisionsPersist(t *testing.T) {
+	vault := t.TempDir()
+	d, err := LoadDecisions(vault)
+	if err != nil {
+		t.Fatalf("LoadDecisions returned error: %v", err)
+	}
+	d.MarkDistinct(filepath.Join(vault, "notes", "b.md"), "notes/a.md")
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(DecisionsPath(vault))
+	if err != nil || !strings.Contains(string(data), `"notes/a.md",`) {
+		t.Fatalf("expected relative paths in the decisions file, got %s (%v)", data, err)
+	}
+
+	reloaded, err := LoadDecisions(vault)
+	if err != nil {
+		t.Fatalf("LoadDecisions returned error: %v", err)
+	}
+	if !reloaded.Distinct(filepath.Join(vault, "notes", "a.md"), filepath.Join(vault, "notes", "b.md")) {
+		t.Fatalf("expected the mark to survive a reload")
+	}
+	if reloaded.Distinct("notes/a.md", "notes/c.md") {
+		t.Fatalf("expected unmarked pairs to be reported")
+	}
+}
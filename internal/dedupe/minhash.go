@@ -0,0 +1,108 @@
+// Package dedupe finds notes that say the same thing. Note bodies are
+// fingerprinted with MinHash over word shingles, candidate pairs are found
+// with locality-sensitive hashing, and pairs above a similarity threshold are
+// grouped into clusters.
+package dedupe
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// numHashes is the signature length. The similarity estimate has a
+	// standard error of about 1/sqrt(numHashes).
+	numHashes = 128
+	// bands and rows split the signature for locality-sensitive hashing.
+	// Pairs sharing one band are compared, which catches pairs above a
+	// similarity of roughly (1/bands)^(1/rows), about 0.42.
+	bands = 32
+	rows  = numHashes / bands
+)
+
+// Signature is the MinHash fingerprint of a text.
+type Signature [numHashes]uint64
+
+// seeds perturbs the shingle hash once per signature slot.
+var seeds = func() [numHashes]uint64 {
+	var out [numHashes]uint64
+	state := uint64(0x9e3779b97f4a7c15)
+	for i := range out {
+		state = splitmix(state)
+		out[i] = state
+	}
+	return out
+}()
+
+// Shingles returns the distinct runs of size consecutive words in text.
+// Words are lowercased letters and digits; punctuation and markup are
+// dropped. Texts shorter than size yield a single shingle of every word.
+func Shingles(text string, size int) map[string]struct{} {
+	if size < 1 {
+		size = 1
+	}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	out := make(map[string]struct{})
+	if len(words) == 0 {
+		return out
+	}
+	if len(words) < size {
+		out[strings.Join(words, " ")] = struct{}{}
+		return out
+	}
+	for i := 0; i+size <= len(words); i++ {
+		out[strings.Join(words[i:i+size], " ")] = struct{}{}
+	}
+	return out
+}
+
+// Sign computes the MinHash signature of a shingle set.
+func Sign(shingles map[string]struct{}) Signature {
+	var sig Signature
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for shingle := range shingles {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		base := h.Sum64()
+		for i, seed := range seeds {
+			if v := splitmix(base ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind two
+// signatures.
+func Similarity(a, b Signature) float64 {
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / numHashes
+}
+
+// band hashes one band of the signature into a bucket key.
+func (s Signature) band(i int) uint64 {
+	h := uint64(i) + 1
+	for _, v := range s[i*rows : (i+1)*rows] {
+		h = splitmix(h ^ v)
+	}
+	return h
+}
+
+func splitmix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
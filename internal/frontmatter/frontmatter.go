@@ -0,0 +1,424 @@
+// Package frontmatter edits the YAML front matter of Markdown notes while
+// keeping the order, comments, and styling of untouched keys.
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+// Document is a note split into its front matter mapping and body.
+type Document struct {
+	mapping *yaml.Node
+	body    string
+
+	// lines holds the original front matter and entries the line range of
+	// each original key, so Bytes copies keys that were not edited verbatim
+	// and only re-encodes the ones that were. trailer holds the comments and
+	// blank lines after the last key.
+	lines   []string
+	entries map[*yaml.Node]entry
+	trailer []string
+}
+
+// entry is the original text of one front matter key: lines[start:line] are
+// the comments and blank lines above it and lines[line:end] the key and its
+// value. encoded is the key as first parsed, to tell whether it changed.
+type entry struct {
+	start, line, end int
+	encoded          string
+	compact          bool
+}
+
+// Parse splits content into front matter and body. A note without front
+// matter yields an empty mapping that is written out once a key is set.
+func Parse(content []byte) (*Document, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	if !strings.HasPrefix(text, delimiter+"\n") {
+		return &Document{mapping: mapping, body: text}, nil
+	}
+
+	rest := text[len(delimiter)+1:]
+	var raw, body string
+	switch {
+	case strings.HasPrefix(rest, delimiter+"\n"):
+		body = rest[len(delimiter)+1:]
+	case strings.HasSuffix(rest, "\n"+delimiter) || rest == delimiter:
+		raw = strings.TrimSuffix(strings.TrimSuffix(rest, delimiter), "\n")
+	default:
+		end := strings.Index(rest, "\n"+delimiter+"\n")
+		if end == -1 {
+			return nil, errors.New("front matter is not terminated")
+		}
+		raw = rest[:end]
+		body = rest[end+len(delimiter)+2:]
+	}
+
+	if strings.TrimSpace(raw) != "" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+			return nil, fmt.Errorf("parse front matter: %w", err)
+		}
+		if len(doc.Content) > 0 {
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return nil, errors.New("front matter is not a mapping")
+			}
+			mapping = doc.Content[0]
+		}
+	}
+
+	d := &Document{mapping: mapping, body: body}
+	d.indexLines(raw)
+	return d, nil
+}
+
+// indexLines records the line range of every top-level key in raw. Front
+// matter that cannot be mapped to lines, such as a flow mapping, is left
+// unindexed and re-encoded whole by Bytes.
+func (d *Document) indexLines(raw string) {
+	if len(d.mapping.Content) == 0 || d.mapping.Style&yaml.FlowStyle != 0 {
+		return
+	}
+	lines := strings.Split(raw, "\n")
+	entries := make(map[*yaml.Node]entry, len(d.mapping.Content)/2)
+	start := 0
+	for i := 0; i+1 < len(d.mapping.Content); i += 2 {
+		key, value := d.mapping.Content[i], d.mapping.Content[i+1]
+		line := key.Line - 1
+		next := len(lines)
+		if i+2 < len(d.mapping.Content) {
+			next = d.mapping.Content[i+2].Line - 1
+		}
+		if line < start || next <= line || next > len(lines) {
+			return
+		}
+		end := next
+		for end > line+1 && isGapLine(lines[end-1]) {
+			end--
+		}
+		encoded, err := encodePair(key, value)
+		if err != nil {
+			return
+		}
+		entries[key] = entry{
+			start:   start,
+			line:    line,
+			end:     end,
+			encoded: encoded,
+			compact: compactList(lines, key, value),
+		}
+		start = end
+	}
+	d.lines, d.entries, d.trailer = lines, entries, lines[start:]
+}
+
+// compactList reports whether value is a block list whose dashes line up
+// with its key, as in "tags:\n- a".
+func compactList(lines []string, key, value *yaml.Node) bool {
+	if value.Kind != yaml.SequenceNode || value.Style&yaml.FlowStyle != 0 || len(value.Content) == 0 {
+		return false
+	}
+	line := value.Content[0].Line - 1
+	if line < 0 || line >= len(lines) || len(lines[line]) < key.Column {
+		return false
+	}
+	return lines[line][key.Column-1] == '-'
+}
+
+func isGapLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+// Body returns the note content after the front matter.
+func (d *Document) Body() string {
+	return d.body
+}
+
+// SetBody replaces the note content after the front matter.
+func (d *Document) SetBody(body string) {
+	d.body = body
+}
+
+// Keys returns the front matter keys in document order.
+func (d *Document) Keys() []string {
+	keys := make([]string, 0, len(d.mapping.Content)/2)
+	for i := 0; i+1 < len(d.mapping.Content); i += 2 {
+		keys = append(keys, d.mapping.Content[i].Value)
+	}
+	return keys
+}
+
+// Get returns the value node for key.
+func (d *Document) Get(key string) (*yaml.Node, bool) {
+	if idx := d.index(key); idx >= 0 {
+		return d.mapping.Content[idx+1], true
+	}
+	return nil, false
+}
+
+// Strings returns the value of key as a list of strings. Scalars yield a
+// single entry and missing or empty keys yield nil.
+func (d *Document) Strings(key string) []string {
+	node, ok := d.Get(key)
+	if !ok {
+		return nil
+	}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			values = append(values, item.Value)
+		}
+		return values
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			return nil
+		}
+		return []string{node.Value}
+	default:
+		return nil
+	}
+}
+
+// Set assigns value to key, replacing an existing value in place or
+// appending the key to the end of the mapping. A replaced scalar keeps its
+// quoting style.
+func (d *Document) Set(key string, value any) error {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+	if old, ok := d.Get(key); ok && old.Kind == yaml.ScalarNode && node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style = old.Style
+	}
+	d.SetNode(key, &node)
+	return nil
+}
+
+// SetYAML parses raw as a YAML value, so numbers, booleans, and flow lists
+// keep their type, and assigns it to key like Set.
+func (d *Document) SetYAML(key, raw string) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("parse value for %s: %w", key, err)
+	}
+	node := stringNode(raw)
+	if len(doc.Content) > 0 {
+		node = doc.Content[0]
+		node.Line, node.Column = 0, 0
+	}
+	if old, ok := d.Get(key); ok && old.Kind == yaml.ScalarNode && node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style = old.Style
+	}
+	d.SetNode(key, node)
+	return nil
+}
+
+// SetNode assigns a prepared value node to key. Comments attached to a
+// replaced value are kept.
+func (d *Document) SetNode(key string, node *yaml.Node) {
+	if idx := d.index(key); idx >= 0 {
+		old := d.mapping.Content[idx+1]
+		if node.HeadComment == "" && node.LineComment == "" && node.FootComment == "" {
+			node.HeadComment, node.LineComment, node.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		}
+		d.mapping.Content[idx+1] = node
+		return
+	}
+	d.mapping.Content = append(d.mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		node,
+	)
+}
+
+// AddValues appends values to the list stored under key, skipping values
+// that are already present. A scalar value is converted into a list.
+func (d *Document) AddValues(key string, values ...string) bool {
+	node, ok := d.Get(key)
+	if !ok || node.Kind != yaml.SequenceNode {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, existing := range d.Strings(key) {
+			seq.Content = append(seq.Content, stringNode(existing))
+		}
+		node = seq
+	}
+
+	present := make(map[string]bool, len(node.Content))
+	for _, item := range node.Content {
+		present[item.Value] = true
+	}
+
+	changed := !ok
+	for _, value := range values {
+		if value == "" || present[value] {
+			continue
+		}
+		present[value] = true
+		node.Content = append(node.Content, stringNode(value))
+		changed = true
+	}
+	d.SetNode(key, node)
+	return changed
+}
+
+// RemoveValues drops values from the list stored under key and reports
+// whether anything was removed. A scalar equal to one of the values removes
+// the key.
+func (d *Document) RemoveValues(key string, values ...string) bool {
+	node, ok := d.Get(key)
+	if !ok {
+		return false
+	}
+
+	drop := make(map[string]bool, len(values))
+	for _, value := range values {
+		drop[value] = true
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		if node.Kind == yaml.ScalarNode && drop[node.Value] {
+			return d.Delete(key)
+		}
+		return false
+	}
+
+	kept := node.Content[:0]
+	for _, item := range node.Content {
+		if !drop[item.Value] {
+			kept = append(kept, item)
+		}
+	}
+	changed := len(kept) != len(node.Content)
+	node.Content = kept
+	return changed
+}
+
+// Delete removes key and reports whether it was present.
+func (d *Document) Delete(key string) bool {
+	idx := d.index(key)
+	if idx < 0 {
+		return false
+	}
+	d.mapping.Content = append(d.mapping.Content[:idx], d.mapping.Content[idx+2:]...)
+	return true
+}
+
+// Bytes renders the note with its updated front matter. Keys that were not
+// edited keep their original lines byte for byte; edited and new keys are
+// encoded on their own and spliced in.
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.mapping.Content) == 0 {
+		return []byte(d.body), nil
+	}
+
+	var front string
+	if d.entries == nil {
+		encoded, err := encodeNode(d.mapping)
+		if err != nil {
+			return nil, err
+		}
+		front = encoded
+	} else {
+		var lines []string
+		for i := 0; i+1 < len(d.mapping.Content); i += 2 {
+			key, value := d.mapping.Content[i], d.mapping.Content[i+1]
+			original, ok := d.entries[key]
+			encoded, err := encodePair(key, value)
+			if err != nil {
+				return nil, err
+			}
+			if ok && encoded == original.encoded {
+				lines = append(lines, d.lines[original.start:original.end]...)
+				continue
+			}
+			if ok {
+				lines = append(lines, d.lines[original.start:original.line]...)
+				if encoded, err = encodeEdited(key, value, original.compact); err != nil {
+					return nil, err
+				}
+			}
+			lines = append(lines, strings.Split(strings.TrimSuffix(encoded, "\n"), "\n")...)
+		}
+		lines = append(lines, d.trailer...)
+		front = strings.Join(lines, "\n") + "\n"
+	}
+
+	var out bytes.Buffer
+	out.WriteString(delimiter + "\n")
+	out.WriteString(front)
+	out.WriteString(delimiter + "\n")
+	out.WriteString(d.body)
+	return out.Bytes(), nil
+}
+
+// encodePair encodes one key and its value as a single-key mapping.
+func encodePair(key, value *yaml.Node) (string, error) {
+	return encodeNode(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, value}})
+}
+
+// encodeEdited encodes an edited key for splicing into the original lines.
+// The comments above and below it are already kept as original lines, so
+// they are left out here. A block list written without indentation under
+// its key keeps that style.
+func encodeEdited(key, value *yaml.Node, compact bool) (string, error) {
+	k, v := *key, *value
+	k.HeadComment, k.FootComment, v.FootComment = "", "", ""
+	encoded, err := encodePair(&k, &v)
+	if err != nil || !compact || v.Kind != yaml.SequenceNode || v.Style&yaml.FlowStyle != 0 {
+		return encoded, err
+	}
+	lines := strings.Split(encoded, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimPrefix(lines[i], "  ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func encodeNode(node *yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", fmt.Errorf("encode front matter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("encode front matter: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenameKey renames oldKey to newKey in place, keeping its value, position,
+// and comments. It fails when newKey is already present.
+func (d *Document) RenameKey(oldKey, newKey string) (bool, error) {
+	idx := d.index(oldKey)
+	if idx < 0 || oldKey == newKey {
+		return false, nil
+	}
+	if d.index(newKey) >= 0 {
+		return false, fmt.Errorf("key %s already exists", newKey)
+	}
+	d.mapping.Content[idx].Value = newKey
+	return true, nil
+}
+
+func (d *Document) index(key string) int {
+	for i := 0; i+1 < len(d.mapping.Content); i += 2 {
+		if d.mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,202 @@
+package frontmatter
+
+import (
+	"strings"
+	"testing"
+)
+
+const sample = "---\ntitle: apollo\n# kept comment\ncreated: 20240301\nmodified:\nup: \"[[projects]]\"\ntags:\n  - one\n---\n# apollo\n\nBody\n"
+
+func TestEditsPreserveUntouchedKeys(t *testing.T) {
+	doc, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if changed := doc.AddValues("tags", "one", "two"); !changed {
+		t.Fatalf("expected tags to change")
+	}
+	if err := doc.Set("up", "[[roadmap]]"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if err := doc.Set("status", "open"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	want := "---\ntitle: apollo\n# kept comment\ncreated: 20240301\nmodified:\nup: \"[[roadmap]]\"\ntags:\n  - one\n  - two\nstatus: open\n---\n# apollo\n\nBody\n"
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	doc, err := Parse([]byte("just text\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if out, _ := doc.Bytes(); string(out) != "just text\n" {
+		t.Fatalf("expected body to round-trip, got %q", out)
+	}
+
+	doc.AddValues("tags", "inbox")
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "---\ntags:\n  - inbox\n---\njust text") {
+		t.Fatalf("expected new front matter, got:\n%s", out)
+	}
+
+	if _, err := Parse([]byte("---\ntitle: x\nno end\n")); err == nil {
+		t.Fatalf("expected error for unterminated front matter")
+	}
+}
+
+func TestStringsAndDelete(t *testing.T) {
+	doc, err := Parse([]byte("---\ntags: solo\nempty:\n---\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := doc.Strings("tags"); len(got) != 1 || got[0] != "solo" {
+		t.Fatalf("expected scalar tag as list, got %v", got)
+	}
+	if got := doc.Strings("empty"); got != nil {
+		t.Fatalf("expected nil for empty key, got %v", got)
+	}
+	if !doc.Delete("empty") || doc.Delete("missing") {
+		t.Fatalf("unexpected Delete results")
+	}
+	if got := strings.Join(doc.Keys(), ","); got != "tags" {
+		t.Fatalf("unexpected keys %q", got)
+	}
+}
+
+func TestRemoveValues(t *testing.T) {
+	doc, err := Parse([]byte("---\ntags:\n  - one\n  - two\ncategory: draft\n---\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if !doc.RemoveValues("tags", "one", "missing") {
+		t.Fatalf("expected tags to change")
+	}
+	if doc.RemoveValues("tags", "missing") {
+		t.Fatalf("expected no change for absent values")
+	}
+	if !doc.RemoveValues("category", "draft") {
+		t.Fatalf("expected matching scalar to be removed")
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if string(out) != "---\ntags:\n  - two\n---\n" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestSetYAMLAndRenameKey(t *testing.T) {
+	doc, err := Parse([]byte("---\n# lifecycle\nstatus: 'building' # current\npriority: 1\n---\nbody\n"))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if err := doc.SetYAML("status", "shipped"); err != nil {
+		t.Fatalf("SetYAML returned error: %v", err)
+	}
+	if err := doc.SetYAML("priority", "2"); err != nil {
+		t.Fatalf("SetYAML returned error: %v", err)
+	}
+	if err := doc.SetYAML("aliases", "[a, b]"); err != nil {
+		t.Fatalf("SetYAML returned error: %v", err)
+	}
+	if changed, err := doc.RenameKey("priority", "rank"); err != nil || !changed {
+		t.Fatalf("expected priority to be renamed, got %v, %v", changed, err)
+	}
+	if _, err := doc.RenameKey("rank", "status"); err == nil {
+		t.Fatalf("expected renaming onto an existing key to fail")
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	want := "---\n# lifecycle\nstatus: 'shipped' # current\nrank: 2\naliases: [a, b]\n---\nbody\n"
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestEditsLeaveUntouchedLinesByteIdentical(t *testing.T) {
+	lines := []string{
+		"---",
+		"# lifecycle",
+		"status: draft   # keep me",
+		"aliases:",
+		"- first",
+		"-   second",
+		"tags: [x,y]",
+		"related: ['a',  \"b\"]",
+		"owners:",
+		"- ann",
+		"",
+		"# trailing note",
+		"---",
+		"Body",
+		"",
+	}
+	content := strings.Join(lines, "\n")
+	doc, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if out, _ := doc.Bytes(); string(out) != content {
+		t.Fatalf("expected an unedited note to round-trip, got:\n%s", out)
+	}
+
+	if err := doc.Set("status", "shipped"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	doc.AddValues("owners", "bo")
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"---",
+		"# lifecycle",
+		"status: shipped # keep me",
+		"aliases:",
+		"- first",
+		"-   second",
+		"tags: [x,y]",
+		"related: ['a',  \"b\"]",
+		"owners:",
+		"- ann",
+		"- bo",
+		"",
+		"# trailing note",
+		"---",
+		"Body",
+		"",
+	}, "\n")
+	if string(out) != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	doc.RemoveValues("tags", "y")
+	doc.Delete("aliases")
+	out, err = doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if !strings.Contains(string(out), "\nstatus: shipped # keep me\ntags: [x]\nrelated: ['a',  \"b\"]\n") {
+		t.Fatalf("expected only the edited keys to change, got:\n%s", out)
+	}
+}
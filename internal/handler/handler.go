@@ -1,21 +1,59 @@
 package handler
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/Paintersrp/an/internal/oplog"
 	"github.com/Paintersrp/an/internal/parser"
+	"github.com/Paintersrp/an/internal/snapshot"
 )
 
 type FileHandler struct {
 	vaultDir string
+	journal  *oplog.Journal
+	history  *snapshot.Store
 }
 
 func NewFileHandler(vaultDir string) *FileHandler {
-	return &FileHandler{vaultDir: vaultDir}
+	h := &FileHandler{vaultDir: vaultDir}
+	if vaultDir != "" {
+		h.journal = oplog.New(vaultDir)
+		h.journal.UseFiles(undoFiles{h})
+	}
+	return h
+}
+
+// Journal returns the operation journal that records the handler's changes
+// so they can be undone.
+func (h *FileHandler) Journal() *oplog.Journal {
+	if h == nil {
+		return nil
+	}
+	return h.journal
+}
+
+// SetHistory makes the handler snapshot a note before each write that
+// replaces or deletes its content. A nil store turns snapshots off.
+func (h *FileHandler) SetHistory(store *snapshot.Store) {
+	if h != nil {
+		h.history = store
+	}
+}
+
+// History returns the snapshot store of the vault, or nil when history is
+// disabled.
+func (h *FileHandler) History() *snapshot.Store {
+	if h == nil {
+		return nil
+	}
+	return h.history
 }
 
 func (h *FileHandler) VaultDir() string {
@@ -54,78 +92,158 @@ func (h *FileHandler) ReadFile(path string) ([]byte, error) {
 }
 
 func (h *FileHandler) WriteFile(path string, data []byte) error {
+	return h.WriteFileOp("write", path, data)
+}
+
+// WriteFileOp writes data to path and journals the change under op.
+func (h *FileHandler) WriteFileOp(op, path string, data []byte) error {
 	resolved, err := h.resolve(path)
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(resolved), 0o755); err != nil {
+	before, err := os.ReadFile(resolved)
+	existed := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
 
-	return os.WriteFile(resolved, data, 0o644)
-}
+	if existed && !bytes.Equal(before, data) {
+		if err := h.history.Save(op, resolved, before); err != nil {
+			return fmt.Errorf("snapshot %s: %w", path, err)
+		}
+	}
 
-// Archive moves a note file to the archive subdirectory.
-func (h *FileHandler) Archive(path string) error {
-	subDir, err := filepath.Rel(h.vaultDir, filepath.Dir(path))
-	if err != nil {
+	if err := os.MkdirAll(filepath.Dir(resolved), 0o755); err != nil {
 		return err
 	}
 
-	archiveSubDir := filepath.Join(h.vaultDir, "archive", subDir)
-	if err := os.MkdirAll(archiveSubDir, os.ModePerm); err != nil {
+	if err := os.WriteFile(resolved, data, 0o644); err != nil {
 		return err
 	}
 
-	newPath := filepath.Join(archiveSubDir, filepath.Base(path))
-	return os.Rename(path, newPath)
+	if existed && bytes.Equal(before, data) {
+		return nil
+	}
+	return h.journalErr(op, h.journal.RecordWrite(op, resolved, before, existed))
 }
 
-// Unarchive moves a note file from the archive subdirectory to its original location.
-func (h *FileHandler) Unarchive(path string) error {
-	subDir, err := filepath.Rel(filepath.Join(h.vaultDir, "archive"), filepath.Dir(path))
+// Move moves a note to a new path inside the vault.
+func (h *FileHandler) Move(from, to string) error {
+	return h.move("move", from, to)
+}
+
+// Delete permanently removes a note. Its content is kept in the journal so
+// the deletion can be undone.
+func (h *FileHandler) Delete(path string) error {
+	resolved, err := h.resolve(path)
+	if err != nil {
+		return err
+	}
+	before, err := os.ReadFile(resolved)
 	if err != nil {
 		return err
 	}
+	if err := h.history.Save("delete", resolved, before); err != nil {
+		return fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	if err := os.Remove(resolved); err != nil {
+		return err
+	}
+	removeTrashInfo(resolved)
+	return h.journalErr("delete", h.journal.RecordDelete("delete", resolved, before))
+}
 
-	originalDir := filepath.Join(h.vaultDir, subDir)
-	if err := os.MkdirAll(originalDir, os.ModePerm); err != nil {
+func (h *FileHandler) move(op, from, to string) error {
+	if _, err := h.resolve(to); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
 		return err
 	}
-	newPath := filepath.Join(originalDir, filepath.Base(path))
-	return os.Rename(path, newPath)
+	if err := h.journalErr(op, h.journal.RecordMove(op, from, to)); err != nil {
+		return err
+	}
+	if err := h.history.Rename(from, to); err != nil {
+		return fmt.Errorf("%s succeeded but the note history could not follow: %w", op, err)
+	}
+	return nil
 }
 
-// Trash moves a note file to the trash subdirectory.
-func (h *FileHandler) Trash(path string) error {
-	subDir, err := filepath.Rel(h.vaultDir, filepath.Dir(path))
+func (h *FileHandler) journalErr(op string, err error) error {
 	if err != nil {
-		return err
+		return fmt.Errorf("%s succeeded but could not be journaled: %w", op, err)
 	}
+	return nil
+}
+
+// undoFiles carries out the file changes of an undo for the journal. Content
+// a revert replaces or removes is snapshotted first, histories follow notes
+// moved back, and a note moved back out of the trash drops its trash
+// metadata.
+type undoFiles struct {
+	h *FileHandler
+}
 
-	trashDir := filepath.Join(h.vaultDir, "trash", subDir)
-	if err := os.MkdirAll(trashDir, os.ModePerm); err != nil {
+func (u undoFiles) WriteFile(path string, data []byte) error {
+	before, err := os.ReadFile(path)
+	if err == nil && !bytes.Equal(before, data) {
+		if err := u.h.history.Save(oplog.OpUndo, path, before); err != nil {
+			return fmt.Errorf("snapshot %s: %w", path, err)
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (u undoFiles) Remove(path string) error {
+	if err := u.h.history.SaveFile(oplog.OpUndo, path); err != nil {
+		return fmt.Errorf("snapshot %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
 
-	newPath := filepath.Join(trashDir, filepath.Base(path))
-	return os.Rename(path, newPath)
+func (u undoFiles) Rename(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	removeTrashInfo(from)
+	if err := u.h.history.Rename(from, to); err != nil {
+		return fmt.Errorf("moved %s back but the note history could not follow: %w", filepath.Base(to), err)
+	}
+	return nil
 }
 
-// Untrash moves a note file from the trash subdirectory to its original location.
-func (h *FileHandler) Untrash(path string) error {
-	subDir, err := filepath.Rel(filepath.Join(h.vaultDir, "trash"), filepath.Dir(path))
+// Archive moves a note file to the archive subdirectory.
+func (h *FileHandler) Archive(path string) error {
+	subDir, err := filepath.Rel(h.vaultDir, filepath.Dir(path))
 	if err != nil {
 		return err
 	}
 
-	originalDir := filepath.Join(h.vaultDir, subDir)
-	if err := os.MkdirAll(originalDir, os.ModePerm); err != nil {
+	archiveSubDir := filepath.Join(h.vaultDir, "archive", subDir)
+	return h.move("archive", path, filepath.Join(archiveSubDir, filepath.Base(path)))
+}
+
+// Unarchive moves a note file from the archive subdirectory to its original location.
+func (h *FileHandler) Unarchive(path string) error {
+	subDir, err := filepath.Rel(filepath.Join(h.vaultDir, "archive"), filepath.Dir(path))
+	if err != nil {
 		return err
 	}
-	newPath := filepath.Join(originalDir, filepath.Base(path))
-	return os.Rename(path, newPath)
+
+	originalDir := filepath.Join(h.vaultDir, subDir)
+	return h.move("unarchive", path, filepath.Join(originalDir, filepath.Base(path)))
 }
 
 func (h *FileHandler) WalkFiles(
@@ -4,7 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+
+	"github.com/Paintersrp/an/internal/snapshot"
 )
 
 func TestWalkFilesExcludesArchiveAndTrash(t *testing.T) {
@@ -163,3 +166,105 @@ func TestUntrashRecreatesOriginalDir(t *testing.T) {
 		t.Fatalf("restored file content mismatch: got %q, want %q", string(restored), string(content))
 	}
 }
+
+func TestOperationsAreJournaled(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+
+	notePath := filepath.Join(vaultDir, "atoms", "note.md")
+	if err := h.WriteFile(notePath, []byte("first")); err != nil {
+		t.Fatalf("WriteFile returned error: %v", err)
+	}
+	if err := h.WriteFileOp("toggle", notePath, []byte("second")); err != nil {
+		t.Fatalf("WriteFileOp returned error: %v", err)
+	}
+	if err := h.Archive(notePath); err != nil {
+		t.Fatalf("Archive returned error: %v", err)
+	}
+
+	entries, err := h.Journal().Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	var ops []string
+	for _, entry := range entries {
+		ops = append(ops, entry.Op)
+	}
+	if len(ops) != 3 || ops[0] != "write" || ops[1] != "toggle" || ops[2] != "archive" {
+		t.Fatalf("unexpected journaled operations %v", ops)
+	}
+	if entries[2].From != "atoms/note.md" || entries[2].Path != "archive/atoms/note.md" {
+		t.Fatalf("unexpected archive entry %#v", entries[2])
+	}
+
+	if _, err := h.Journal().Undo(2); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("expected note back in place: %v", err)
+	}
+	if string(data) != "first" {
+		t.Fatalf("expected toggle to be reverted, got %q", data)
+	}
+}
+
+func TestDeleteCanBeUndone(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+
+	notePath := filepath.Join(vaultDir, "trash", "note.md")
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		t.Fatalf("failed to create trash directory: %v", err)
+	}
+	if err := os.WriteFile(notePath, []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	if err := h.Delete(notePath); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if data, err := os.ReadFile(notePath); err != nil || string(data) != "content" {
+		t.Fatalf("expected deleted note restored, got %q, %v", data, err)
+	}
+}
+
+func TestWritesSnapshotPreviousContent(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+	h.SetHistory(snapshot.Open(vaultDir, snapshot.Options{}))
+
+	notePath := filepath.Join(vaultDir, "atoms", "note.md")
+	moved := filepath.Join(vaultDir, "atoms", "renamed.md")
+	for _, content := range []string{"one", "two", "two", "three"} {
+		if err := h.WriteFileOp("edit", notePath, []byte(content)); err != nil {
+			t.Fatalf("WriteFileOp returned error: %v", err)
+		}
+	}
+	if err := h.Move(notePath, moved); err != nil {
+		t.Fatalf("Move returned error: %v", err)
+	}
+	if err := h.Delete(moved); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	versions, err := h.History().Versions(moved)
+	if err != nil {
+		t.Fatalf("Versions returned error: %v", err)
+	}
+	var got []string
+	for _, version := range versions {
+		content, _, err := h.History().Read(moved, version.Rev)
+		if err != nil {
+			t.Fatalf("Read returned error: %v", err)
+		}
+		got = append(got, version.Op+":"+string(content))
+	}
+	want := []string{"edit:one", "edit:two", "delete:three"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected versions %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trashInfoSuffix names the metadata file kept next to each trashed note,
+// e.g. `trash/atoms/.idea.md.trash.json` for `trash/atoms/idea.md`. The
+// leading dot keeps it out of WalkFiles and the views.
+const trashInfoSuffix = ".trash.json"
+
+var now = time.Now
+
+// TrashEntry describes a note in the trash. Original is the vault-relative
+// path the note was trashed from. Notes trashed before metadata was recorded
+// fall back to the path implied by their location and their modification
+// time.
+type TrashEntry struct {
+	Path      string    `json:"-"`
+	Original  string    `json:"original"`
+	TrashedAt time.Time `json:"trashed_at"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Trash moves a note file to the trash subdirectory.
+func (h *FileHandler) Trash(path string) error {
+	return h.TrashWithReason(path, "")
+}
+
+// TrashWithReason moves a note file to the trash subdirectory and records
+// where it came from, when, and why next to it.
+func (h *FileHandler) TrashWithReason(path, reason string) error {
+	subDir, err := filepath.Rel(h.vaultDir, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	original, err := filepath.Rel(h.vaultDir, path)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(h.vaultDir, "trash", subDir, filepath.Base(path))
+	if err := h.move("trash", path, target); err != nil {
+		return err
+	}
+
+	info := TrashEntry{
+		Original:  filepath.ToSlash(original),
+		TrashedAt: now().UTC(),
+		Reason:    strings.TrimSpace(reason),
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(trashInfoPath(target), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("trashed %s but could not record trash metadata: %w", info.Original, err)
+	}
+	return nil
+}
+
+// Untrash moves a note file from the trash subdirectory back to the path it
+// was trashed from. It refuses to overwrite a note that has since taken its
+// place.
+func (h *FileHandler) Untrash(path string) error {
+	entry, err := h.trashEntry(path)
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(h.vaultDir, filepath.FromSlash(entry.Original))
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("cannot restore %s: %s already exists", filepath.Base(path), entry.Original)
+	}
+	if err := h.move("untrash", path, target); err != nil {
+		return err
+	}
+	removeTrashInfo(path)
+	return nil
+}
+
+// TrashEntries lists the notes in the trash, oldest first.
+func (h *FileHandler) TrashEntries() ([]TrashEntry, error) {
+	if h == nil {
+		return nil, fmt.Errorf("file handler is not configured")
+	}
+
+	root := filepath.Join(h.vaultDir, "trash")
+	var entries []TrashEntry
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") || filepath.Ext(d.Name()) != ".md" {
+			return nil
+		}
+		entry, err := h.trashEntry(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].TrashedAt.Equal(entries[j].TrashedAt) {
+			return entries[i].TrashedAt.Before(entries[j].TrashedAt)
+		}
+		return entries[i].Original < entries[j].Original
+	})
+	return entries, nil
+}
+
+// PurgeTrash permanently deletes the notes that have been in the trash for
+// longer than olderThan and returns them. With dryRun set nothing is
+// deleted. Deletions are journaled as one step, so a single undo brings the
+// whole purge back.
+func (h *FileHandler) PurgeTrash(olderThan time.Duration, dryRun bool) ([]TrashEntry, error) {
+	entries, err := h.TrashEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := now().Add(-olderThan)
+	var purged []TrashEntry
+	err = h.journal.Group(func() error {
+		for _, entry := range entries {
+			if !entry.TrashedAt.Before(cutoff) {
+				continue
+			}
+			if !dryRun {
+				if err := h.Delete(entry.Path); err != nil {
+					return fmt.Errorf("purge %s: %w", entry.Original, err)
+				}
+			}
+			purged = append(purged, entry)
+		}
+		return nil
+	})
+	return purged, err
+}
+
+// trashEntry reads the metadata for the trashed note at path.
+func (h *FileHandler) trashEntry(path string) (TrashEntry, error) {
+	entry := TrashEntry{Path: path}
+
+	data, err := os.ReadFile(trashInfoPath(path))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return entry, fmt.Errorf("%s: %w", trashInfoPath(path), err)
+		}
+		entry.Path = path
+	case !errors.Is(err, fs.ErrNotExist):
+		return entry, err
+	}
+
+	if entry.Original == "" {
+		rel, err := filepath.Rel(filepath.Join(h.vaultDir, "trash"), path)
+		if err != nil {
+			return entry, err
+		}
+		entry.Original = filepath.ToSlash(rel)
+	}
+	if entry.TrashedAt.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return entry, err
+		}
+		entry.TrashedAt = info.ModTime().UTC()
+	}
+	return entry, nil
+}
+
+func trashInfoPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+trashInfoSuffix)
+}
+
+func removeTrashInfo(path string) {
+	_ = os.Remove(trashInfoPath(path))
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withNow(t *testing.T, at time.Time) {
+	t.Helper()
+	previous := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = previous })
+}
+
+func writeVaultNote(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+}
+
+func TestTrashRecordsMetadata(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+	trashedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	withNow(t, trashedAt)
+
+	notePath := filepath.Join(vaultDir, "atoms", "idea.md")
+	writeVaultNote(t, notePath)
+	if err := h.TrashWithReason(notePath, " superseded "); err != nil {
+		t.Fatalf("TrashWithReason returned error: %v", err)
+	}
+
+	entries, err := h.TrashEntries()
+	if err != nil {
+		t.Fatalf("TrashEntries returned error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one trashed note, got %#v", entries)
+	}
+	entry := entries[0]
+	if entry.Original != "atoms/idea.md" || entry.Reason != "superseded" || !entry.TrashedAt.Equal(trashedAt) {
+		t.Fatalf("unexpected trash entry %#v", entry)
+	}
+	if entry.Path != filepath.Join(vaultDir, "trash", "atoms", "idea.md") {
+		t.Fatalf("unexpected trashed path %q", entry.Path)
+	}
+
+	files, err := h.WalkFiles(nil, nil, "")
+	if err != nil {
+		t.Fatalf("WalkFiles returned error: %v", err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, trashInfoSuffix) {
+			t.Fatalf("expected trash metadata to be hidden from WalkFiles, got %v", files)
+		}
+	}
+
+	if err := h.Untrash(entry.Path); err != nil {
+		t.Fatalf("Untrash returned error: %v", err)
+	}
+	if _, err := os.Stat(notePath); err != nil {
+		t.Fatalf("expected note restored to its original path: %v", err)
+	}
+	if _, err := os.Stat(trashInfoPath(entry.Path)); !os.IsNotExist(err) {
+		t.Fatalf("expected trash metadata removed, got %v", err)
+	}
+}
+
+func TestUntrashRefusesToOverwrite(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+
+	notePath := filepath.Join(vaultDir, "atoms", "idea.md")
+	writeVaultNote(t, notePath)
+	if err := h.Trash(notePath); err != nil {
+		t.Fatalf("Trash returned error: %v", err)
+	}
+	writeVaultNote(t, notePath)
+
+	if err := h.Untrash(filepath.Join(vaultDir, "trash", "atoms", "idea.md")); err == nil {
+		t.Fatalf("expected untrash to refuse overwriting an existing note")
+	}
+}
+
+func TestUndoTrashRemovesMetadata(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+
+	notePath := filepath.Join(vaultDir, "atoms", "idea.md")
+	writeVaultNote(t, notePath)
+	if err := h.TrashWithReason(notePath, "superseded"); err != nil {
+		t.Fatalf("TrashWithReason returned error: %v", err)
+	}
+	trashed := filepath.Join(vaultDir, "trash", "atoms", "idea.md")
+	if _, err := os.Stat(trashInfoPath(trashed)); err != nil {
+		t.Fatalf("expected trash metadata to be written: %v", err)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if _, err := os.Stat(notePath); err != nil {
+		t.Fatalf("expected undo to move the note back: %v", err)
+	}
+	if _, err := os.Stat(trashInfoPath(trashed)); !os.IsNotExist(err) {
+		t.Fatalf("expected undo to remove the trash metadata, got %v", err)
+	}
+	if entries, err := h.TrashEntries(); err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty trash, got %#v, %v", entries, err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	oldPath := filepath.Join(vaultDir, "atoms", "old.md")
+	newPath := filepath.Join(vaultDir, "atoms", "new.md")
+	writeVaultNote(t, oldPath)
+	writeVaultNote(t, newPath)
+
+	withNow(t, start)
+	if err := h.Trash(oldPath); err != nil {
+		t.Fatalf("Trash returned error: %v", err)
+	}
+	withNow(t, start.Add(20*24*time.Hour))
+	if err := h.Trash(newPath); err != nil {
+		t.Fatalf("Trash returned error: %v", err)
+	}
+	withNow(t, start.Add(40*24*time.Hour))
+
+	purged, err := h.PurgeTrash(30*24*time.Hour, true)
+	if err != nil {
+		t.Fatalf("PurgeTrash returned error: %v", err)
+	}
+	if len(purged) != 1 || purged[0].Original != "atoms/old.md" {
+		t.Fatalf("unexpected dry-run purge %#v", purged)
+	}
+	if _, err := os.Stat(purged[0].Path); err != nil {
+		t.Fatalf("expected dry run to keep the note: %v", err)
+	}
+
+	if _, err := h.PurgeTrash(30*24*time.Hour, false); err != nil {
+		t.Fatalf("PurgeTrash returned error: %v", err)
+	}
+	entries, err := h.TrashEntries()
+	if err != nil {
+		t.Fatalf("TrashEntries returned error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Original != "atoms/new.md" {
+		t.Fatalf("expected only the recent note to remain, got %#v", entries)
+	}
+	if _, err := os.Stat(trashInfoPath(purged[0].Path)); !os.IsNotExist(err) {
+		t.Fatalf("expected purged note's metadata removed, got %v", err)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if _, err := os.Stat(purged[0].Path); err != nil {
+		t.Fatalf("expected purge to be undoable: %v", err)
+	}
+}
+
+func TestPurgeTrashUndoesInOneStep(t *testing.T) {
+	vaultDir := t.TempDir()
+	h := NewFileHandler(vaultDir)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	withNow(t, start)
+	var paths []string
+	for _, name := range []string{"a.md", "b.md", "c.md"} {
+		path := filepath.Join(vaultDir, "atoms", name)
+		writeVaultNote(t, path)
+		if err := h.Trash(path); err != nil {
+			t.Fatalf("Trash returned error: %v", err)
+		}
+		paths = append(paths, filepath.Join(vaultDir, "trash", "atoms", name))
+	}
+	withNow(t, start.Add(40*24*time.Hour))
+
+	purged, err := h.PurgeTrash(30*24*time.Hour, false)
+	if err != nil || len(purged) != 3 {
+		t.Fatalf("expected 3 notes purged, got %#v, %v", purged, err)
+	}
+
+	undone, err := h.Journal().Undo(1)
+	if err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if len(undone) != 3 {
+		t.Fatalf("expected one undo to revert all 3 deletions, got %d", len(undone))
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s restored to the trash: %v", path, err)
+		}
+	}
+}
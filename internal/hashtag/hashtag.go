@@ -0,0 +1,69 @@
+// Package hashtag finds and rewrites inline #tags in note bodies. Tags may
+// be nested with "/", as in #project/apollo. Fenced code blocks and inline
+// code spans are never treated as tags, and neither are purely numeric
+// tokens such as issue references (#42).
+package hashtag
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	tagRe   = regexp.MustCompile(`(^|[\s,;])#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+	fenceRe = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// Map replaces every inline #tag in body with fn(tag). The tag is passed
+// without its leading "#".
+func Map(body string, fn func(tag string) string) string {
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.Contains(line, "#") {
+			continue
+		}
+
+		parts := strings.Split(line, "`")
+		for j := 0; j < len(parts); j += 2 {
+			parts[j] = tagRe.ReplaceAllStringFunc(parts[j], func(match string) string {
+				m := tagRe.FindStringSubmatch(match)
+				if numeric(m[2]) {
+					return match
+				}
+				return m[1] + "#" + fn(m[2])
+			})
+		}
+		lines[i] = strings.Join(parts, "`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Find returns the inline tags of body in order of first use, each once
+// regardless of case. Trailing slashes are dropped.
+func Find(body string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	Map(body, func(tag string) string {
+		name := strings.TrimRight(tag, "/")
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			tags = append(tags, name)
+		}
+		return tag
+	})
+	return tags
+}
+
+// numeric reports whether tag has no character besides digits and "/"
+// separators. Like Obsidian, a tag needs at least one non-digit.
+func numeric(tag string) bool {
+	return strings.IndexFunc(tag, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '/'
+	}) < 0
+}
@@ -0,0 +1,40 @@
+package hashtag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindSkipsCodeHeadingsAndAnchors(t *testing.T) {
+	body := "# Heading\n\n" +
+		"Working on #project/apollo, #go; and #Go again.\n" +
+		"See [intro](#intro), [[#Section]], note.md#anchor and `#inline`.\n" +
+		"```\n#fenced\n```\n" +
+		"#trailing/\n"
+
+	got := strings.Join(Find(body), ",")
+	if want := "project/apollo,go,trailing"; got != want {
+		t.Fatalf("Find() = %q, want %q", got, want)
+	}
+}
+
+func TestFindSkipsNumericTokens(t *testing.T) {
+	body := "Fixed in #42 and PR #1337/, see #2024/q1, #y1984 and #v2.\n"
+
+	got := strings.Join(Find(body), ",")
+	if want := "2024/q1,y1984,v2"; got != want {
+		t.Fatalf("Find() = %q, want %q", got, want)
+	}
+	if got := Map("#42 #a\n", strings.ToUpper); got != "#42 #A\n" {
+		t.Fatalf("expected numeric tokens left alone by Map, got %q", got)
+	}
+}
+
+func TestMapRewritesTags(t *testing.T) {
+	got := Map("#a and `#a` and #b\n", func(tag string) string {
+		return strings.ToUpper(tag)
+	})
+	if want := "#A and `#a` and #B\n"; got != want {
+		t.Fatalf("Map() = %q, want %q", got, want)
+	}
+}
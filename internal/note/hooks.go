@@ -11,6 +11,7 @@ import (
 
 	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/pathutil"
+	"github.com/Paintersrp/an/internal/snapshot"
 )
 
 type hookContext struct {
@@ -57,6 +58,10 @@ func executeHookCommands(phase string, commands []config.CommandTemplate, path s
 		return nil
 	}
 
+	if err := snapshotBeforeHooks(phase, path); err != nil {
+		return err
+	}
+
 	ctx := newHookContext(path)
 	for _, command := range commands {
 		cmd, wait, name, err := buildHookCommand(command, ctx)
@@ -86,6 +91,23 @@ func executeHookCommands(phase string, commands []config.CommandTemplate, path s
 	return nil
 }
 
+// snapshotBeforeHooks keeps the current content of path in the note history,
+// since hook commands may rewrite it in place.
+func snapshotBeforeHooks(phase, path string) error {
+	var cfg config.HistoryConfig
+	if err := viper.UnmarshalKey("history", &cfg); err != nil {
+		return fmt.Errorf("failed to load history settings: %w", err)
+	}
+	store, err := snapshot.FromConfig(viper.GetString("vaultdir"), cfg)
+	if err != nil {
+		return err
+	}
+	if err := store.SaveFile(phase+" hook", path); err != nil {
+		return fmt.Errorf("%s hooks: snapshot %s: %w", phase, path, err)
+	}
+	return nil
+}
+
 func buildHookCommand(template config.CommandTemplate, ctx hookContext) (*exec.Cmd, bool, string, error) {
 	execName := strings.TrimSpace(applyHookPlaceholders(template.Exec, ctx))
 	if execName == "" {
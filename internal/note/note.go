@@ -28,6 +28,9 @@ type ZettelkastenNote struct {
 	Upstream      string
 	OriginalTags  []string
 	OriginalLinks []string
+	// PostCreate lists extra hooks run after the workspace post_create hooks,
+	// such as those added by a matching capture rule.
+	PostCreate []config.CommandTemplate
 }
 
 // NewZettelkastenNote creates a new ZettelkastenNote instance.
@@ -146,6 +149,9 @@ func (note *ZettelkastenNote) Create(
 	if err := RunPostCreateHooks(path); err != nil {
 		return false, fmt.Errorf("post-create hook failed: %w", err)
 	}
+	if err := executeHookCommands("post_create", note.PostCreate, path); err != nil {
+		return false, fmt.Errorf("post-create hook failed: %w", err)
+	}
 
 	return true, nil
 }
@@ -311,6 +317,10 @@ func collectTemplateMetadata(
 		return map[string]interface{}{}, nil
 	}
 
+	if err := manifest.CheckExpressions(); err != nil {
+		return nil, err
+	}
+
 	answers := make(map[string]interface{}, len(manifest.Fields))
 	consumedPrefills := make(map[string]struct{}, len(prefills))
 
@@ -323,6 +333,17 @@ func collectTemplateMetadata(
 			continue
 		}
 
+		applies, err := field.Applies(answers)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			if _, ok := prefills[field.Key]; ok {
+				consumedPrefills[field.Key] = struct{}{}
+			}
+			continue
+		}
+
 		if value, ok := prefills[field.Key]; ok {
 			normalized, err := normalizePrefillValue(field, value)
 			if err != nil {
@@ -333,6 +354,10 @@ func collectTemplateMetadata(
 			continue
 		}
 
+		if field.IsComputed() {
+			continue
+		}
+
 		var value interface{}
 
 		if field.Multi || len(field.Defaults) > 0 {
@@ -346,7 +371,11 @@ func collectTemplateMetadata(
 						"field %q is required but interactive input is not available", field.Key,
 					)
 				}
-				value = defaults
+				typed, err := templater.ParseFieldValues(field, defaults)
+				if err != nil {
+					return nil, fmt.Errorf("default for field %q: %w", field.Key, err)
+				}
+				value = typed
 			} else {
 				for {
 					prompt := fieldPrompt(field)
@@ -354,7 +383,11 @@ func collectTemplateMetadata(
 					input, _ := reader.ReadString('\n')
 					input = strings.TrimSpace(input)
 					if input == "" && len(defaults) > 0 {
-						value = defaults
+						typed, err := templater.ParseFieldValues(field, defaults)
+						if err != nil {
+							return nil, fmt.Errorf("default for field %q: %w", field.Key, err)
+						}
+						value = typed
 						break
 					}
 					if input == "" && field.Required {
@@ -372,14 +405,23 @@ func collectTemplateMetadata(
 							continue
 						}
 					}
-					value = entries
+					typed, err := templater.ParseFieldValues(field, entries)
+					if err != nil {
+						fmt.Printf("%v\n", err)
+						continue
+					}
+					value = typed
 					break
 				}
 			}
 		} else {
 			if !interactive {
 				if field.Default != "" {
-					value = field.Default
+					typed, err := templater.ParseFieldValue(field, field.Default)
+					if err != nil {
+						return nil, fmt.Errorf("default for field %q: %w", field.Key, err)
+					}
+					value = typed
 				} else if field.Required {
 					return nil, fmt.Errorf(
 						"field %q is required but interactive input is not available", field.Key,
@@ -394,8 +436,7 @@ func collectTemplateMetadata(
 					input, _ := reader.ReadString('\n')
 					input = strings.TrimSpace(input)
 					if input == "" && field.Default != "" {
-						value = field.Default
-						break
+						input = field.Default
 					}
 					if input == "" && field.Required {
 						fmt.Println("This field is required. Please enter a value.")
@@ -411,7 +452,19 @@ func collectTemplateMetadata(
 							continue
 						}
 					}
-					value = input
+					if field.Kind() == templater.FieldTypeNoteLink {
+						completed, ok := completeNoteLink(t, input)
+						if !ok {
+							continue
+						}
+						input = completed
+					}
+					typed, err := templater.ParseFieldValue(field, input)
+					if err != nil {
+						fmt.Printf("%v\n", err)
+						continue
+					}
+					value = typed
 					break
 				}
 			}
@@ -427,11 +480,24 @@ func collectTemplateMetadata(
 		return nil, fmt.Errorf("prefill provided for unknown field %q", key)
 	}
 
+	if err := templater.ApplyComputedFields(manifest, answers); err != nil {
+		return nil, err
+	}
+
 	return answers, nil
 }
 
 func normalizePrefillValue(field templater.TemplateField, value any) (interface{}, error) {
+	typed := isTypedField(field)
+
 	if field.Multi || len(field.Defaults) > 0 {
+		if list, ok := value.([]interface{}); ok && typed {
+			entries := make([]string, 0, len(list))
+			for _, entry := range list {
+				entries = append(entries, fmt.Sprint(entry))
+			}
+			value = entries
+		}
 		entries, err := coerceToStringSlice(value)
 		if err != nil {
 			return nil, err
@@ -444,7 +510,18 @@ func normalizePrefillValue(field templater.TemplateField, value any) (interface{
 				return nil, err
 			}
 		}
-		return entries, nil
+		return templater.ParseFieldValues(field, entries)
+	}
+
+	if typed {
+		parsed, err := templater.ParseFieldValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		if parsed == "" && field.Required {
+			return nil, errors.New("value is required")
+		}
+		return parsed, nil
 	}
 
 	str, err := coerceToString(value)
@@ -462,7 +539,44 @@ func normalizePrefillValue(field templater.TemplateField, value any) (interface{
 			return nil, err
 		}
 	}
-	return str, nil
+	return templater.ParseFieldValue(field, str)
+}
+
+// isTypedField reports whether the field stores something other than free
+// text, in which case prefills may arrive as YAML-typed values.
+func isTypedField(field templater.TemplateField) bool {
+	switch field.Kind() {
+	case templater.FieldTypeText, templater.FieldTypeSelect:
+		return false
+	}
+	return true
+}
+
+// completeNoteLink resolves interactive note-link input against the vault.
+// A single match is accepted, several matches are listed so the user can
+// refine the input, and unknown names are kept as links to future notes.
+// Input already wrapped in [[ ]] is taken as written.
+func completeNoteLink(t *templater.Templater, input string) (string, bool) {
+	if strings.HasPrefix(input, "[[") {
+		return input, true
+	}
+	candidates := t.NoteLinkCandidates(input, 8)
+	switch {
+	case len(candidates) == 0:
+		return input, true
+	case len(candidates) == 1:
+		if candidates[0] != input {
+			fmt.Printf("Linked to %s\n", candidates[0])
+		}
+		return candidates[0], true
+	}
+	needle := strings.ToLower(input)
+	first := strings.ToLower(candidates[0])
+	if first == needle || strings.HasSuffix(first, "/"+needle) {
+		return candidates[0], true
+	}
+	fmt.Printf("Matching notes: %s (wrap in [[ ]] to link a new note)\n", strings.Join(candidates, ", "))
+	return "", false
 }
 
 func coerceToStringSlice(value any) ([]string, error) {
@@ -524,6 +638,9 @@ func fieldPrompt(field templater.TemplateField) string {
 	if len(field.Options) > 0 {
 		parts = append(parts, fmt.Sprintf("[%s]", strings.Join(field.Options, ", ")))
 	}
+	if hint := field.Hint(); hint != "" && len(field.Options) == 0 {
+		parts = append(parts, fmt.Sprintf("(%s)", hint))
+	}
 	if field.Multi {
 		parts = append(parts, "(comma separated)")
 	}
@@ -1,10 +1,13 @@
 package note
 
 import (
+	"bufio"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -186,6 +189,130 @@ func TestCollectTemplateMetadataPrefillInvalidType(t *testing.T) {
 	}
 }
 
+func TestCollectTemplateMetadataTypedPrefills(t *testing.T) {
+	dir := t.TempDir()
+	templatesDir := filepath.Join(dir, ".an", "templates")
+	if err := os.MkdirAll(templatesDir, 0o755); err != nil {
+		t.Fatalf("failed to create templates dir: %v", err)
+	}
+	content := `{{/* an:manifest
+name: typed
+fields:
+  - key: due
+    type: date
+  - key: effort
+    type: number
+    min: 1
+    max: 8
+  - key: billable
+    type: bool
+    default: "no"
+  - key: ticket
+    type: "regex:^AN-[0-9]+$"
+*/}}
+Body
+`
+	if err := os.WriteFile(filepath.Join(templatesDir, "typed.tmpl"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	tmpl, err := templater.NewTemplater(&config.Workspace{VaultDir: dir})
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "typed") })
+
+	metadata, err := CollectTemplateMetadataNonInteractive(tmpl, "typed", map[string]any{
+		"due":    "2024-06-01",
+		"effort": 3,
+		"ticket": "AN-7",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if due, ok := metadata["due"].(templater.Date); !ok || due.String() != "2024-06-01" {
+		t.Fatalf("expected due date, got %#v", metadata["due"])
+	}
+	if metadata["effort"] != 3 {
+		t.Fatalf("expected effort 3, got %#v", metadata["effort"])
+	}
+	if metadata["billable"] != false {
+		t.Fatalf("expected billable default false, got %#v", metadata["billable"])
+	}
+
+	for key, value := range map[string]any{"effort": "12", "ticket": "7", "due": "later"} {
+		prefills := map[string]any{key: value}
+		if _, err := CollectTemplateMetadataNonInteractive(tmpl, "typed", prefills); err == nil {
+			t.Fatalf("expected invalid %s prefill %v to be rejected", key, value)
+		}
+	}
+}
+
+func TestCollectTemplateMetadataConditionalAndComputed(t *testing.T) {
+	dir := t.TempDir()
+	templatesDir := filepath.Join(dir, ".an", "templates")
+	if err := os.MkdirAll(templatesDir, 0o755); err != nil {
+		t.Fatalf("failed to create templates dir: %v", err)
+	}
+	content := `{{/* an:manifest
+name: release
+fields:
+  - key: risk
+    options: [low, high]
+    required: true
+  - key: rollback
+    prompt: Rollback plan
+    required: true
+    when: risk == "high"
+  - key: effort_days
+    type: number
+    default: "2"
+  - key: due
+    type: date
+    compute: created + effort_days
+*/}}
+Body
+`
+	if err := os.WriteFile(filepath.Join(templatesDir, "release.tmpl"), []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	tmpl, err := templater.NewTemplater(&config.Workspace{VaultDir: dir})
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "release") })
+
+	low, err := CollectTemplateMetadataNonInteractive(tmpl, "release", map[string]any{
+		"risk":     "low",
+		"rollback": "ignored",
+	})
+	if err != nil {
+		t.Fatalf("expected no error for low risk, got %v", err)
+	}
+	if _, ok := low["rollback"]; ok {
+		t.Fatalf("expected rollback to be skipped for low risk, got %#v", low)
+	}
+	if _, ok := low["due"].(templater.Date); !ok {
+		t.Fatalf("expected computed due date, got %#v", low["due"])
+	}
+
+	if _, err := CollectTemplateMetadataNonInteractive(tmpl, "release", map[string]any{"risk": "high"}); err == nil {
+		t.Fatalf("expected missing rollback plan to fail for high risk")
+	}
+
+	input := bufio.NewReader(strings.NewReader("high\nrevert tag\n5\n"))
+	high, err := collectTemplateMetadata(tmpl, "release", nil, true, input)
+	if err != nil {
+		t.Fatalf("interactive collection returned error: %v", err)
+	}
+	if high["rollback"] != "revert tag" || high["effort_days"] != 5 {
+		t.Fatalf("unexpected interactive answers %#v", high)
+	}
+	expected := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+	if due, _ := high["due"].(templater.Date); due.String() != expected {
+		t.Fatalf("expected due %s, got %#v", expected, high["due"])
+	}
+}
+
 func newTestTemplater(t *testing.T) *templater.Templater {
 	t.Helper()
 
@@ -0,0 +1,131 @@
+package note
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+// Scaffold is a set of linked notes created together from a scaffold template.
+// Tags apply to every note, while Links, Upstream, and Content only apply to
+// the first, root note.
+type Scaffold struct {
+	VaultDir string
+	SubDir   string
+	Files    []templater.ScaffoldFile
+	Tags     []string
+	Links    []string
+	Upstream string
+	Content  string
+}
+
+// Paths returns the file path of every note in the scaffold, in order.
+func (s *Scaffold) Paths() []string {
+	paths := make([]string, len(s.Files))
+	for i, file := range s.Files {
+		subDir := file.Subdir
+		if subDir == "" {
+			subDir = s.SubDir
+		}
+		paths[i] = filepath.Join(s.VaultDir, subDir, file.Filename+".md")
+	}
+	return paths
+}
+
+// Create renders and writes every note of the scaffold and then runs the
+// post_create hooks once per note. Metadata for all notes is collected and
+// every template is rendered before anything is written, and if writing or a
+// hook fails all notes created so far are removed again.
+func (s *Scaffold) Create(t *templater.Templater) ([]string, error) {
+	paths := s.Paths()
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if seen[path] {
+			return nil, fmt.Errorf("scaffold creates %s more than once", path)
+		}
+		seen[path] = true
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("note %s already exists", path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	outputs := make([]string, len(s.Files))
+	for i, file := range s.Files {
+		metadata, err := CollectTemplateMetadata(t, file.Template, nil)
+		if err != nil {
+			return nil, fmt.Errorf("collect metadata for %s: %w", file.Filename, err)
+		}
+
+		zetTime, generated := t.GenerateTagsAndDate(file.Template)
+		tags := append(append(append([]string{}, s.Tags...), file.Tags...), generated...)
+		data := templater.TemplateData{
+			Title:    file.Filename,
+			Date:     zetTime,
+			Tags:     tags,
+			Links:    file.Links,
+			Upstream: file.Upstream,
+			Metadata: metadata,
+		}
+		if i == 0 {
+			data.Links = append(append([]string{}, s.Links...), file.Links...)
+			data.Content = s.Content
+			if data.Upstream == "" {
+				data.Upstream = s.Upstream
+			}
+		}
+
+		output, err := t.Execute(file.Template, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute template %s: %w", file.Template, err)
+		}
+		outputs[i] = output
+	}
+
+	var created []string
+	rollback := func() {
+		for i := len(created) - 1; i >= 0; i-- {
+			removeCreatedArtifacts(created[i], s.VaultDir)
+		}
+	}
+
+	for i, path := range paths {
+		if err := writeNewFile(path, outputs[i]); err != nil {
+			rollback()
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		created = append(created, path)
+	}
+
+	for _, path := range paths {
+		if err := RunPostCreateHooks(path); err != nil {
+			rollback()
+			return nil, fmt.Errorf("post-create hook failed for %s: %w", path, err)
+		}
+	}
+
+	return paths, nil
+}
+
+// writeNewFile writes content to path, creating parent directories and
+// refusing to replace an existing file.
+func writeNewFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
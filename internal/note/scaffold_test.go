@@ -0,0 +1,131 @@
+package note
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+func newScaffoldFixture(t *testing.T) (*templater.Templater, *Scaffold) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	vaultDir := t.TempDir()
+	viper.Set("vaultdir", vaultDir)
+
+	dir := filepath.Join(vaultDir, ".an", "templates")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create template dir: %v", err)
+	}
+	manifest := "{{/* an:manifest\nscaffold:\n  - key: hub\n    template: zet\n    links: [plan]\n  - key: plan\n    template: zet\n    subdir: plans\n    up: hub\n*/}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "launch.tmpl"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "launch") })
+
+	tmpl, err := templater.NewTemplater(&config.Workspace{VaultDir: vaultDir})
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	files, err := tmpl.ScaffoldFiles("launch", "apollo")
+	if err != nil {
+		t.Fatalf("ScaffoldFiles returned error: %v", err)
+	}
+	return tmpl, &Scaffold{VaultDir: vaultDir, SubDir: "atoms", Files: files, Tags: []string{"apollo"}}
+}
+
+func TestScaffoldCreateWritesLinkedNotesAndRunsHooks(t *testing.T) {
+	tmpl, scaffold := newScaffoldFixture(t)
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	viper.Set("workspace_hooks", config.HookConfig{
+		PostCreate: []config.CommandTemplate{
+			{Exec: "sh", Args: []string{"-c", "echo {filename} >> " + logPath}},
+		},
+	})
+
+	paths, err := scaffold.Create(tmpl)
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	want := []string{
+		filepath.Join(scaffold.VaultDir, "atoms", "apollo.md"),
+		filepath.Join(scaffold.VaultDir, "plans", "apollo-plan.md"),
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+
+	hub, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatalf("failed to read hub: %v", err)
+	}
+	if !strings.Contains(string(hub), "[[apollo-plan]]") || !strings.Contains(string(hub), "- apollo") {
+		t.Fatalf("expected hub to link to the plan and carry tags:\n%s", hub)
+	}
+	plan, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatalf("failed to read plan: %v", err)
+	}
+	if !strings.Contains(string(plan), `up: "[[apollo]]"`) {
+		t.Fatalf("expected plan to link up to the hub:\n%s", plan)
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("expected hook log: %v", err)
+	}
+	if got := strings.Fields(string(log)); strings.Join(got, ",") != "apollo.md,apollo-plan.md" {
+		t.Fatalf("expected one hook run per note, got %v", got)
+	}
+}
+
+func TestScaffoldCreateRollsBackWhenHookFails(t *testing.T) {
+	tmpl, scaffold := newScaffoldFixture(t)
+	viper.Set("workspace_hooks", config.HookConfig{
+		PostCreate: []config.CommandTemplate{
+			{Exec: "sh", Args: []string{"-c", `case "{filename}" in *plan*) exit 1;; esac`}},
+		},
+	})
+
+	if _, err := scaffold.Create(tmpl); err == nil {
+		t.Fatalf("expected hook failure")
+	}
+	for _, path := range scaffold.Paths() {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be rolled back, got err %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(scaffold.VaultDir, "plans")); !os.IsNotExist(err) {
+		t.Fatalf("expected created directory to be removed, got err %v", err)
+	}
+}
+
+func TestScaffoldCreateRefusesExistingNotes(t *testing.T) {
+	tmpl, scaffold := newScaffoldFixture(t)
+	existing := scaffold.Paths()[1]
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(existing, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	if _, err := scaffold.Create(tmpl); err == nil {
+		t.Fatalf("expected conflict error")
+	}
+	if _, err := os.Stat(scaffold.Paths()[0]); !os.IsNotExist(err) {
+		t.Fatalf("expected no notes to be written, got err %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep" {
+		t.Fatalf("existing note was modified: %q", data)
+	}
+}
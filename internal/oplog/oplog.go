@@ -0,0 +1,568 @@
+// Package oplog keeps an append-only journal of the changes made to a vault
+// so that they can be listed and undone.
+package oplog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OpUndo marks the journal entries written when an operation is undone.
+const OpUndo = "undo"
+
+// ErrNothingToUndo is returned when every recorded operation is undone.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// Entry is one recorded operation. Paths are relative to the vault. Path is
+// where the note ended up; From is where it was before a move. Before holds
+// the previous content when the operation changed or deleted the file, and
+// Hash the SHA-256 of the content it left behind, so an undo can tell
+// whether the file has been edited since. Entries recorded inside the same
+// Group share a Group ID and are undone together.
+type Entry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	Path    string    `json:"path"`
+	From    string    `json:"from,omitempty"`
+	Created bool      `json:"created,omitempty"`
+	Deleted bool      `json:"deleted,omitempty"`
+	Before  *string   `json:"before,omitempty"`
+	Hash    string    `json:"hash,omitempty"`
+	Undoes  int       `json:"undoes,omitempty"`
+	Group   int       `json:"group,omitempty"`
+}
+
+// Describe summarises the entry for history listings.
+func (e Entry) Describe() string {
+	switch {
+	case e.Op == OpUndo:
+		return fmt.Sprintf("undo #%d", e.Undoes)
+	case e.From != "" && e.From != e.Path:
+		return fmt.Sprintf("%s %s -> %s", e.Op, e.From, e.Path)
+	default:
+		return fmt.Sprintf("%s %s", e.Op, e.Path)
+	}
+}
+
+// maxLogSize bounds ops.log. Once it is exceeded the oldest operations are
+// dropped until the log is half that size, so undo reaches back over the
+// most recent changes only.
+var maxLogSize int64 = 8 << 20
+
+// Files carries out the file changes of an undo. The file handler provides
+// one so that reverted content is snapshotted like any other write; without
+// it the journal changes files directly.
+type Files interface {
+	WriteFile(path string, data []byte) error
+	Remove(path string) error
+	Rename(from, to string) error
+}
+
+// Journal records operations in `<vault>/.an/ops.log`.
+type Journal struct {
+	vault string
+	path  string
+	mu    sync.Mutex
+	now   func() time.Time
+	group int
+	files Files
+
+	// lastID and offset remember how far ops.log has been read, so an
+	// append only reads the entries written since, e.g. by another an
+	// process.
+	lastID int
+	offset int64
+}
+
+// New returns the journal for vault. Nothing is written until the first
+// operation is recorded.
+func New(vault string) *Journal {
+	return &Journal{
+		vault: vault,
+		path:  filepath.Join(vault, ".an", "ops.log"),
+		now:   time.Now,
+		files: osFiles{},
+	}
+}
+
+// UseFiles makes undo carry out its file changes through files.
+func (j *Journal) UseFiles(files Files) {
+	if j != nil && files != nil {
+		j.files = files
+	}
+}
+
+// Path returns the location of the journal file.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// RecordMove records that a note moved from one path to another.
+func (j *Journal) RecordMove(op, from, to string) error {
+	return j.record(op, to, func(e *Entry) error {
+		rel, err := j.rel(from)
+		if err != nil {
+			return err
+		}
+		e.From = rel
+		return nil
+	})
+}
+
+// RecordWrite records that path was written. before is the previous content
+// and existed reports whether the file was there at all.
+func (j *Journal) RecordWrite(op, path string, before []byte, existed bool) error {
+	return j.record(op, path, func(e *Entry) error {
+		if !existed {
+			e.Created = true
+			return nil
+		}
+		content := string(before)
+		e.Before = &content
+		return nil
+	})
+}
+
+// RecordRename records a move that may also have rewritten the note. before
+// is the content prior to the rewrite, or nil when only the path changed.
+func (j *Journal) RecordRename(op, from, to string, before []byte) error {
+	return j.record(op, to, func(e *Entry) error {
+		rel, err := j.rel(from)
+		if err != nil {
+			return err
+		}
+		e.From = rel
+		if before != nil {
+			content := string(before)
+			e.Before = &content
+		}
+		return nil
+	})
+}
+
+// RecordDelete records that path was removed along with its content.
+func (j *Journal) RecordDelete(op, path string, before []byte) error {
+	return j.record(op, path, func(e *Entry) error {
+		content := string(before)
+		e.Before = &content
+		e.Deleted = true
+		return nil
+	})
+}
+
+// Group runs fn and records every operation journaled meanwhile as one
+// step, so a single undo reverts them all. Nested groups join the outer one.
+func (j *Journal) Group(fn func() error) error {
+	if j == nil {
+		return fn()
+	}
+
+	j.mu.Lock()
+	if j.group != 0 {
+		j.mu.Unlock()
+		return fn()
+	}
+	if err := j.syncLocked(); err != nil {
+		j.mu.Unlock()
+		return err
+	}
+	j.group = j.lastID + 1
+	j.mu.Unlock()
+
+	defer func() {
+		j.mu.Lock()
+		j.group = 0
+		j.mu.Unlock()
+	}()
+	return fn()
+}
+
+func (j *Journal) record(op, path string, fill func(*Entry) error) error {
+	if j == nil {
+		return nil
+	}
+	rel, err := j.rel(path)
+	if err != nil {
+		return err
+	}
+	entry := Entry{Op: op, Path: rel}
+	if err := fill(&entry); err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry.Group = j.group
+	if !entry.Deleted {
+		hash, err := hashFile(path)
+		if err != nil {
+			return fmt.Errorf("journal %s: %w", rel, err)
+		}
+		entry.Hash = hash
+	}
+	return j.appendLocked(entry)
+}
+
+// Entries returns every recorded entry, oldest first.
+func (j *Journal) Entries() ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.entriesLocked()
+}
+
+// Pending returns the operations that have not been undone, newest first.
+func (j *Journal) Pending() ([]Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+	return pending(entries), nil
+}
+
+// Undo reverts the most recent steps operations that have not been undone
+// yet, newest first, and returns the reverted entries. A group of operations
+// counts as one step. It stops at the first operation that cannot be
+// reverted.
+func (j *Journal) Undo(steps int) ([]Entry, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries, err := j.entriesLocked()
+	if err != nil {
+		return nil, err
+	}
+	candidates := pending(entries)
+	if len(candidates) == 0 {
+		return nil, ErrNothingToUndo
+	}
+
+	var undone []Entry
+	for _, entry := range takeSteps(candidates, steps) {
+		restored, err := j.revert(entry)
+		if err != nil {
+			return undone, fmt.Errorf("undo #%d (%s): %w", entry.ID, entry.Describe(), err)
+		}
+		mark := Entry{Op: OpUndo, Path: restored, Undoes: entry.ID}
+		if hash, err := hashFile(j.abs(restored)); err == nil {
+			mark.Hash = hash
+		}
+		if err := j.appendLocked(mark); err != nil {
+			return undone, err
+		}
+		undone = append(undone, entry)
+	}
+	return undone, nil
+}
+
+// revert reverses entry on disk and returns the path the note is left at,
+// or its former path when the undo removed it.
+func (j *Journal) revert(entry Entry) (string, error) {
+	current := j.abs(entry.Path)
+
+	if entry.Deleted {
+		if _, err := os.Stat(current); err == nil {
+			return "", fmt.Errorf("%s already exists", entry.Path)
+		}
+		if err := j.files.WriteFile(current, []byte(*entry.Before)); err != nil {
+			return "", err
+		}
+		return entry.Path, nil
+	}
+
+	hash, err := hashFile(current)
+	if err != nil {
+		return "", err
+	}
+	if entry.Hash != "" && hash != entry.Hash {
+		return "", fmt.Errorf("%s has changed since the operation", entry.Path)
+	}
+
+	if entry.Created {
+		if err := j.files.Remove(current); err != nil {
+			return "", err
+		}
+		return entry.Path, nil
+	}
+
+	target := entry.Path
+	if entry.From != "" && entry.From != entry.Path {
+		original := j.abs(entry.From)
+		if _, err := os.Stat(original); err == nil {
+			return "", fmt.Errorf("%s already exists", entry.From)
+		}
+		if err := j.files.Rename(current, original); err != nil {
+			return "", err
+		}
+		current, target = original, entry.From
+	}
+
+	if entry.Before != nil {
+		if err := j.files.WriteFile(current, []byte(*entry.Before)); err != nil {
+			return "", err
+		}
+	}
+	return target, nil
+}
+
+// takeSteps returns the leading entries of candidates that make up steps
+// undo steps, keeping grouped entries together.
+func takeSteps(candidates []Entry, steps int) []Entry {
+	taken := 0
+	for i, entry := range candidates {
+		if i == 0 || entry.Group == 0 || entry.Group != candidates[i-1].Group {
+			taken++
+			if taken > steps {
+				return candidates[:i]
+			}
+		}
+	}
+	return candidates
+}
+
+// pending returns the entries that are neither undo markers nor undone,
+// newest first.
+func pending(entries []Entry) []Entry {
+	undone := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.Op == OpUndo {
+			undone[entry.Undoes] = true
+		}
+	}
+
+	var out []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Op == OpUndo || undone[entry.ID] {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func (j *Journal) entriesLocked() ([]Entry, error) {
+	file, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", j.path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// syncLocked reads the entries appended since the last call to keep lastID
+// current. A log smaller than what was already read has been compacted and
+// is read again from the start.
+func (j *Journal) syncLocked() error {
+	file, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			j.offset = 0
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < j.offset {
+		j.offset = 0
+	}
+	if info.Size() == j.offset {
+		return nil
+	}
+	if _, err := file.Seek(j.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			j.offset += int64(len(line))
+			var head struct {
+				ID int `json:"id"`
+			}
+			if json.Unmarshal(line, &head) == nil && head.ID > j.lastID {
+				j.lastID = head.ID
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (j *Journal) appendLocked(entry Entry) error {
+	if err := j.syncLocked(); err != nil {
+		return err
+	}
+	entry.ID = j.lastID + 1
+	entry.Time = j.now().UTC()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	j.lastID = entry.ID
+
+	if info.Size() > maxLogSize {
+		return j.compactLocked()
+	}
+	return nil
+}
+
+// compactLocked drops the oldest entries until the log fits in half of
+// maxLogSize. The entries of a group are kept or dropped together, and the
+// newest group is always kept.
+func (j *Journal) compactLocked() error {
+	entries, err := j.entriesLocked()
+	if err != nil {
+		return err
+	}
+	lines := make([][]byte, len(entries))
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines[i] = append(data, '\n')
+	}
+
+	start, size := len(entries), int64(0)
+	for start > 0 && size+int64(len(lines[start-1])) <= maxLogSize/2 {
+		start--
+		size += int64(len(lines[start]))
+	}
+	for start > 0 && start < len(entries) && sameGroup(entries[start-1], entries[start]) {
+		start++
+	}
+	if start == len(entries) && start > 0 {
+		start--
+		for start > 0 && sameGroup(entries[start-1], entries[start]) {
+			start--
+		}
+	}
+	if start == 0 {
+		return nil
+	}
+
+	kept := bytes.Join(lines[start:], nil)
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+	j.offset = int64(len(kept))
+	return nil
+}
+
+func sameGroup(a, b Entry) bool {
+	return a.Group != 0 && a.Group == b.Group
+}
+
+func (j *Journal) rel(path string) (string, error) {
+	rel, err := filepath.Rel(j.vault, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func (j *Journal) abs(rel string) string {
+	return filepath.Join(j.vault, filepath.FromSlash(rel))
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// osFiles changes files directly.
+type osFiles struct{}
+
+func (osFiles) WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (osFiles) Remove(path string) error {
+	return os.Remove(path)
+}
+
+func (osFiles) Rename(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
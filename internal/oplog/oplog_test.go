@@ -0,0 +1,236 @@
+package oplog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeNote(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func readNote(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestUndoRevertsOperationsNewestFirst(t *testing.T) {
+	vault := t.TempDir()
+	j := New(vault)
+
+	original := filepath.Join(vault, "atoms", "idea.md")
+	renamed := filepath.Join(vault, "atoms", "better.md")
+	trashed := filepath.Join(vault, "trash", "atoms", "better.md")
+
+	writeNote(t, original, "---\ntitle: idea\n---\n")
+	writeNote(t, original, "---\ntitle: better\n---\n")
+	if err := os.Rename(original, renamed); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.RecordRename("rename", original, renamed, []byte("---\ntitle: idea\n---\n")); err != nil {
+		t.Fatalf("RecordRename returned error: %v", err)
+	}
+
+	writeNote(t, trashed, readNote(t, renamed))
+	os.Remove(renamed)
+	if err := j.RecordMove("trash", renamed, trashed); err != nil {
+		t.Fatalf("RecordMove returned error: %v", err)
+	}
+
+	undone, err := j.Undo(1)
+	if err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if len(undone) != 1 || undone[0].Op != "trash" {
+		t.Fatalf("expected trash to be undone first, got %#v", undone)
+	}
+	if readNote(t, renamed) != "---\ntitle: better\n---\n" {
+		t.Fatalf("expected note restored from trash")
+	}
+
+	if _, err := j.Undo(5); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if readNote(t, original) != "---\ntitle: idea\n---\n" {
+		t.Fatalf("expected rename and title change to be reverted")
+	}
+	if _, err := os.Stat(renamed); !os.IsNotExist(err) {
+		t.Fatalf("expected renamed path to be gone, got %v", err)
+	}
+
+	if _, err := j.Undo(1); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo, got %v", err)
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	if len(entries) != 4 || entries[2].Undoes != 2 || entries[3].Undoes != 1 {
+		t.Fatalf("expected undo markers appended to the journal, got %#v", entries)
+	}
+}
+
+func TestUndoRefusesModifiedNotes(t *testing.T) {
+	vault := t.TempDir()
+	j := New(vault)
+	path := filepath.Join(vault, "note.md")
+
+	writeNote(t, path, "after")
+	if err := j.RecordWrite("edit", path, []byte("before"), true); err != nil {
+		t.Fatalf("RecordWrite returned error: %v", err)
+	}
+	writeNote(t, path, "edited again")
+
+	if _, err := j.Undo(1); err == nil {
+		t.Fatalf("expected undo to refuse a note changed since the operation")
+	}
+	if readNote(t, path) != "edited again" {
+		t.Fatalf("expected later edits to be kept")
+	}
+}
+
+func TestUndoCreateAndDelete(t *testing.T) {
+	vault := t.TempDir()
+	j := New(vault)
+	created := filepath.Join(vault, "copy.md")
+	deleted := filepath.Join(vault, "trash", "gone.md")
+
+	writeNote(t, created, "copy")
+	if err := j.RecordWrite("copy", created, nil, false); err != nil {
+		t.Fatalf("RecordWrite returned error: %v", err)
+	}
+	if err := j.RecordDelete("delete", deleted, []byte("gone")); err != nil {
+		t.Fatalf("RecordDelete returned error: %v", err)
+	}
+
+	if _, err := j.Undo(2); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if readNote(t, deleted) != "gone" {
+		t.Fatalf("expected deleted note to be recreated")
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Fatalf("expected copied note to be removed, got %v", err)
+	}
+}
+
+func TestUndoRevertsGroupAsOneStep(t *testing.T) {
+	vault := t.TempDir()
+	j := New(vault)
+
+	var paths []string
+	for _, name := range []string{"one.md", "two.md", "three.md"} {
+		paths = append(paths, filepath.Join(vault, name))
+	}
+
+	writeNote(t, paths[0], "single")
+	if err := j.RecordWrite("copy", paths[0], nil, false); err != nil {
+		t.Fatalf("RecordWrite returned error: %v", err)
+	}
+
+	err := j.Group(func() error {
+		for _, path := range paths[1:] {
+			writeNote(t, path, "batch")
+			if err := j.RecordWrite("copy", path, nil, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Group returned error: %v", err)
+	}
+
+	undone, err := j.Undo(1)
+	if err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if len(undone) != 2 {
+		t.Fatalf("expected the whole group to be undone, got %#v", undone)
+	}
+	for _, path := range paths[1:] {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s removed, got %v", path, err)
+		}
+	}
+	if readNote(t, paths[0]) != "single" {
+		t.Fatalf("expected the operation before the group to be kept")
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	if entries[0].Group != 0 || entries[1].Group == 0 || entries[1].Group != entries[2].Group {
+		t.Fatalf("unexpected groups %#v", entries)
+	}
+}
+
+func TestLogIsCompactedAndIDsKeepGrowing(t *testing.T) {
+	prev := maxLogSize
+	maxLogSize = 4096
+	t.Cleanup(func() { maxLogSize = prev })
+
+	vault := t.TempDir()
+	path := filepath.Join(vault, "note.md")
+	writeNote(t, path, "current")
+	before := []byte(strings.Repeat("previous content ", 10))
+
+	j := New(vault)
+	other := New(vault)
+	for i := 0; i < 40; i++ {
+		journal := j
+		if i%5 == 0 {
+			journal = other
+		}
+		if err := journal.RecordWrite("edit", path, before, true); err != nil {
+			t.Fatalf("RecordWrite returned error: %v", err)
+		}
+	}
+
+	info, err := os.Stat(j.Path())
+	if err != nil {
+		t.Fatalf("failed to stat journal: %v", err)
+	}
+	if info.Size() > maxLogSize {
+		t.Fatalf("expected the journal to stay under %d bytes, got %d", maxLogSize, info.Size())
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	if len(entries) == 0 || len(entries) == 40 {
+		t.Fatalf("expected the oldest entries dropped, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if want := 40 - len(entries) + i + 1; entry.ID != want {
+			t.Fatalf("expected entry %d to have ID %d, got %d", i, want, entry.ID)
+		}
+	}
+
+	if err := New(vault).RecordWrite("edit", path, before, true); err != nil {
+		t.Fatalf("RecordWrite returned error: %v", err)
+	}
+	entries, err = j.Entries()
+	if err != nil {
+		t.Fatalf("Entries returned error: %v", err)
+	}
+	if last := entries[len(entries)-1].ID; last != 41 {
+		t.Fatalf("expected a fresh journal to continue at ID 41, got %d", last)
+	}
+}
@@ -14,7 +14,6 @@ import (
 
 type Parser struct {
 	TaskHandler *TaskHandler
-	TagHandler  *TagHandler
 	DirPath     string
 }
 
@@ -22,7 +21,6 @@ func NewParser(dirPath string) *Parser {
 	return &Parser{
 		DirPath:     dirPath,
 		TaskHandler: NewTaskHandler(),
-		TagHandler:  NewTagHandler(),
 	}
 }
 
@@ -58,43 +56,25 @@ func (p *Parser) parse(path string) error {
 	reader := text.NewReader(source)
 	document := parser.Parse(reader)
 
-	var inTagsSection bool
-
 	ast.Walk(
 		document,
 		func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-			if entering {
-				switch n := n.(type) {
-				case *ast.ListItem:
-					content := strings.TrimSpace(string(n.Text(source)))
-					line := 0
-					if lines := n.Lines(); lines != nil && lines.Len() > 0 {
-						segment := lines.At(0)
-						line = 1 + bytes.Count(source[:segment.Start], []byte("\n"))
-					} else if child := n.FirstChild(); child != nil {
-						if clines := child.Lines(); clines != nil && clines.Len() > 0 {
-							segment := clines.At(0)
-							line = 1 + bytes.Count(source[:segment.Start], []byte("\n"))
-						}
-					}
-
-					if inTagsSection {
-						p.TagHandler.ParseTag(content)
-					} else {
-						p.TaskHandler.ParseTask(content, path, line)
-					}
-				case *ast.Text:
-					content := strings.TrimSpace(string(n.Text(source)))
-
-					if content == "tags:" {
-						inTagsSection = true
-					}
-				}
-			} else {
-				if _, ok := n.(*ast.List); ok && inTagsSection {
-					inTagsSection = false
+			item, ok := n.(*ast.ListItem)
+			if !entering || !ok {
+				return ast.WalkContinue, nil
+			}
+			content := strings.TrimSpace(string(item.Text(source)))
+			line := 0
+			if lines := item.Lines(); lines != nil && lines.Len() > 0 {
+				segment := lines.At(0)
+				line = 1 + bytes.Count(source[:segment.Start], []byte("\n"))
+			} else if child := item.FirstChild(); child != nil {
+				if clines := child.Lines(); clines != nil && clines.Len() > 0 {
+					segment := clines.At(0)
+					line = 1 + bytes.Count(source[:segment.Start], []byte("\n"))
 				}
 			}
+			p.TaskHandler.ParseTask(content, path, line)
 			return ast.WalkContinue, nil
 		},
 	)
@@ -102,18 +82,6 @@ func (p *Parser) parse(path string) error {
 	return nil
 }
 
-func (p *Parser) PrintTagCounts() {
-	p.TagHandler.PrintTagCounts()
-}
-
-func (p *Parser) PrintSortedTagCounts(order string) {
-	p.TagHandler.PrintSortedTagCounts(order)
-}
-
-func (p *Parser) ShowTagTable() {
-	p.TagHandler.ShowTagTable()
-}
-
 func (p *Parser) PrintTasks(sortType, sortOrder string) {
 	p.TaskHandler.PrintTasks(sortType, sortOrder)
 }
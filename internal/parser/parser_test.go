@@ -6,7 +6,7 @@ import (
 	"testing"
 )
 
-func TestParserWalkExtractsTasksAndTags(t *testing.T) {
+func TestParserWalkExtractsTasks(t *testing.T) {
 	dir := t.TempDir()
 	notePath := filepath.Join(dir, "note.md")
 	content := `# Title
@@ -64,16 +64,8 @@ tags:
 		t.Fatalf("expected to find both tracked tasks, got %#v", p.TaskHandler.Tasks)
 	}
 
-	if got := p.TagHandler.TagCounts["project/foo"]; got != 2 {
-		t.Fatalf("expected tag 'project/foo' to be counted twice, got %d", got)
-	}
-
-	if got := p.TagHandler.TagCounts["weekly"]; got != 1 {
-		t.Fatalf("expected tag 'weekly' to be counted once, got %d", got)
-	}
-
-	if len(p.TagHandler.TagList) != 2 {
-		t.Fatalf("expected TagList to only contain unique tags, got %#v", p.TagHandler.TagList)
+	if len(p.TaskHandler.Tasks) != 2 {
+		t.Fatalf("expected plain list items to be ignored, got %#v", p.TaskHandler.Tasks)
 	}
 }
 
@@ -102,17 +94,3 @@ func TestTaskHandlerRecordsPathAndLine(t *testing.T) {
 		t.Fatalf("expected line number 42, got %d", task.Line)
 	}
 }
-
-func TestTagHandlerParseTagIncrementsCounts(t *testing.T) {
-	handler := NewTagHandler()
-	handler.ParseTag("project/foo")
-	handler.ParseTag("project/foo")
-
-	if handler.TagCounts["project/foo"] != 2 {
-		t.Fatalf("expected tag count to increment to 2, got %d", handler.TagCounts["project/foo"])
-	}
-
-	if len(handler.TagList) != 1 {
-		t.Fatalf("expected TagList to deduplicate entries, got %#v", handler.TagList)
-	}
-}
@@ -0,0 +1,146 @@
+package relocate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/frontmatter"
+)
+
+// Merge plans folding sources into the note at into. The source bodies are
+// appended in order, list values such as tags are unioned, and front matter
+// keys the target lacks are copied over; the target's own values win. When
+// into does not exist yet it starts from the first source's front matter.
+// Links to the sources in every note in files are redirected to the target
+// and the sources are moved to the trash.
+func Merge(vault string, sources []string, into string, files []string) (*Plan, error) {
+	into = filepath.Clean(into)
+	if rel, err := filepath.Rel(vault, into); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("%s is outside the vault", into)
+	}
+
+	plan := &Plan{op: "merge", vault: vault, TrashReason: "merged into " + noteName(into)}
+	moved := make(map[string]string, len(sources))
+	for _, source := range sources {
+		source = filepath.Clean(source)
+		if source == into {
+			return nil, errors.New("cannot merge a note into itself")
+		}
+		if _, seen := moved[source]; seen {
+			continue
+		}
+		if _, err := os.Stat(source); err != nil {
+			return nil, err
+		}
+		moved[source] = into
+		plan.Trash = append(plan.Trash, source)
+	}
+	if len(plan.Trash) == 0 {
+		return nil, errors.New("no notes to merge")
+	}
+	l := newLinks(vault, moved, nil)
+
+	var target *frontmatter.Document
+	content, err := os.ReadFile(into)
+	exists := err == nil
+	switch {
+	case exists:
+		if target, err = frontmatter.Parse(content); err != nil {
+			return nil, fmt.Errorf("%s: %w", plan.rel(into), err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if target, err = readDocument(plan.Trash[0]); err != nil {
+			return nil, err
+		}
+		target.SetBody("")
+		if _, ok := target.Get("title"); ok {
+			if err := target.Set("title", noteName(into)); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, err
+	}
+
+	r := rewriter{links: l, oldPath: into, newPath: into}
+	body, changes := r.rewrite([]byte(target.Body()))
+	merged := strings.TrimRight(string(body), "\n")
+	for _, source := range plan.Trash {
+		doc, err := readDocument(source)
+		if err != nil {
+			return nil, err
+		}
+		mergeFrontMatter(target, doc)
+
+		r := rewriter{links: l, oldPath: source, newPath: into}
+		text, _ := r.rewrite([]byte(doc.Body()))
+		if part := strings.TrimSpace(string(text)); part != "" {
+			if merged != "" {
+				merged += "\n\n"
+			}
+			merged += part
+		}
+	}
+	if !exists && len(target.Keys()) > 0 {
+		merged = "\n" + merged
+	}
+	target.SetBody(merged + "\n")
+
+	data, err := target.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("merged %d note(s)", len(plan.Trash))
+	if exists {
+		plan.Rewrites = append(plan.Rewrites, Rewrite{Path: into, Summary: summary, Changes: changes, content: data})
+	} else {
+		plan.Creates = append(plan.Creates, Note{Path: into, content: data})
+	}
+
+	skip := map[string]bool{into: true}
+	for _, source := range plan.Trash {
+		skip[source] = true
+	}
+	rewrites, err := relink(l, files, skip)
+	if err != nil {
+		return nil, err
+	}
+	plan.Rewrites = append(plan.Rewrites, rewrites...)
+	return plan, nil
+}
+
+// mergeFrontMatter copies keys from source that target lacks and unions
+// list values present in both.
+func mergeFrontMatter(target, source *frontmatter.Document) {
+	for _, key := range source.Keys() {
+		value, _ := source.Get(key)
+		existing, ok := target.Get(key)
+		switch {
+		case !ok || isEmpty(existing):
+			target.SetNode(key, value)
+		case existing.Kind == yaml.SequenceNode || value.Kind == yaml.SequenceNode:
+			target.AddValues(key, source.Strings(key)...)
+		}
+	}
+}
+
+func isEmpty(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && (node.Tag == "!!null" || node.Value == "")
+}
+
+func readDocument(path string) (*frontmatter.Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return doc, nil
+}
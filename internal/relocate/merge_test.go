@@ -0,0 +1,91 @@
+package relocate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+func TestMergeFoldsNotesIntoTarget(t *testing.T) {
+	vault := t.TempDir()
+	target := filepath.Join(vault, "atoms", "topic.md")
+	a := filepath.Join(vault, "inbox", "a.md")
+	b := filepath.Join(vault, "inbox", "b.md")
+	hub := filepath.Join(vault, "hubs", "index.md")
+
+	writeNote(t, target, "---\ntitle: topic\ntags:\n  - go\n---\n\nExisting body.\n")
+	writeNote(t, a, "---\ntitle: a\nstatus: draft\ntags:\n  - go\n  - cli\n---\n\nFrom a, see [b](b.md).\n")
+	writeNote(t, b, "---\ntitle: b\ntags: [tui]\n---\n\nFrom b.\n")
+	writeNote(t, hub, "- [[a]]\n- [[inbox/b#Part|b]]\n- [a](../inbox/a.md)\n")
+
+	h := handler.NewFileHandler(vault)
+	files, err := h.WalkFiles(nil, nil, "")
+	if err != nil {
+		t.Fatalf("WalkFiles returned error: %v", err)
+	}
+
+	plan, err := Merge(vault, []string{a, b}, target, files)
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if err := plan.Apply(h); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	want := "---\ntitle: topic\ntags:\n  - go\n  - cli\n  - tui\nstatus: draft\n---\n\nExisting body.\n\nFrom a, see [b](topic.md).\n\nFrom b.\n"
+	if got := readNote(t, target); got != want {
+		t.Fatalf("unexpected target:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := readNote(t, hub), "- [[topic]]\n- [[atoms/topic#Part|b]]\n- [a](../atoms/topic.md)\n"; got != want {
+		t.Fatalf("unexpected backlinks:\n%s\nwant:\n%s", got, want)
+	}
+	for _, name := range []string{"a.md", "b.md"} {
+		if _, err := os.Stat(filepath.Join(vault, "trash", "inbox", name)); err != nil {
+			t.Fatalf("expected %s in the trash: %v", name, err)
+		}
+	}
+	entries, err := h.TrashEntries()
+	if err != nil || len(entries) != 2 || entries[0].Reason != "merged into topic" {
+		t.Fatalf("expected trash reasons to name the target, got %#v (%v)", entries, err)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Fatalf("expected one undo to restore the sources: %v", err)
+	}
+	if strings.Contains(readNote(t, target), "From b.") {
+		t.Fatalf("expected one undo to restore the target")
+	}
+}
+
+func TestMergeCreatesMissingTarget(t *testing.T) {
+	vault := t.TempDir()
+	a := filepath.Join(vault, "a.md")
+	b := filepath.Join(vault, "b.md")
+	writeNote(t, a, "---\ntitle: a\ntags: [x]\n---\n\nA.\n")
+	writeNote(t, b, "B.\n")
+
+	into := filepath.Join(vault, "both.md")
+	plan, err := Merge(vault, []string{a, b}, into, nil)
+	if err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	if len(plan.Creates) != 1 {
+		t.Fatalf("expected the target to be created, got %#v", plan.Creates)
+	}
+	if err := plan.Apply(handler.NewFileHandler(vault)); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if got, want := readNote(t, into), "---\ntitle: both\ntags: [x]\n---\n\nA.\n\nB.\n"; got != want {
+		t.Fatalf("unexpected target:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := Merge(vault, []string{into}, into, nil); err == nil {
+		t.Fatalf("expected merging a note into itself to fail")
+	}
+}
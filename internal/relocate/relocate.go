@@ -0,0 +1,449 @@
+// Package relocate moves, splits, and merges notes and rewrites the links
+// that would otherwise break: relative markdown links inside the changed
+// notes and in the notes that point at them, and wiki links by path, name,
+// or heading.
+package relocate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+var (
+	markdownLinkRe = regexp.MustCompile(`!?\[[^\]]*\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	wikiLinkRe     = regexp.MustCompile(`\[\[([^\]|#]*)(#[^\]|]*)?([^\]]*)\]\]`)
+	anchorRe       = regexp.MustCompile(`[^\p{L}\p{N}_-]+`)
+)
+
+// Move is a note moving from one absolute path to another.
+type Move struct {
+	From string
+	To   string
+}
+
+// LinkChange is one link target rewritten inside a note.
+type LinkChange struct {
+	Old string
+	New string
+}
+
+// Rewrite holds the updated content of a note whose links change. Path is
+// where the note is before the moves run. Summary describes any change
+// beyond the listed links, such as sections replaced by a split.
+type Rewrite struct {
+	Path    string
+	Summary string
+	Changes []LinkChange
+	content []byte
+}
+
+// Note is a note the plan creates.
+type Note struct {
+	Path    string
+	content []byte
+}
+
+// Plan is the full set of changes for one relocation, split, or merge.
+type Plan struct {
+	Subdir      string
+	Creates     []Note
+	Moves       []Move
+	Rewrites    []Rewrite
+	Trash       []string
+	TrashReason string
+	op          string
+	vault       string
+}
+
+// Build plans moving notes into subdir, a directory relative to vault.
+// files lists the notes whose links should be checked; the moved notes are
+// always checked. Notes already in subdir are left out of the plan.
+func Build(vault string, notes []string, subdir string, files []string) (*Plan, error) {
+	subdir = strings.Trim(filepath.ToSlash(filepath.Clean(strings.TrimSpace(subdir))), "/")
+	if subdir == "" || subdir == "." || subdir == ".." || strings.HasPrefix(subdir, "../") || filepath.IsAbs(subdir) {
+		return nil, fmt.Errorf("invalid subdirectory %q", subdir)
+	}
+	if len(notes) == 0 {
+		return nil, errors.New("no notes to move")
+	}
+
+	plan := &Plan{Subdir: subdir, op: "move", vault: vault}
+	moved := make(map[string]string, len(notes))
+	targets := make(map[string]string, len(notes))
+	dir := filepath.Join(vault, filepath.FromSlash(subdir))
+
+	for _, note := range notes {
+		from := filepath.Clean(note)
+		if rel, err := filepath.Rel(vault, from); err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fmt.Errorf("%s is outside the vault", note)
+		}
+		if _, err := os.Stat(from); err != nil {
+			return nil, err
+		}
+		if _, seen := moved[from]; seen {
+			continue
+		}
+
+		to := filepath.Join(dir, filepath.Base(from))
+		if to == from {
+			continue
+		}
+		if other, clash := targets[to]; clash {
+			return nil, fmt.Errorf("%s and %s would both move to %s", plan.rel(other), plan.rel(from), plan.rel(to))
+		}
+		if _, err := os.Stat(to); err == nil {
+			return nil, fmt.Errorf("%s already exists", plan.rel(to))
+		}
+		moved[from] = to
+		targets[to] = from
+		plan.Moves = append(plan.Moves, Move{From: from, To: to})
+	}
+
+	l := newLinks(vault, moved, nil)
+	checked := make(map[string]bool, len(files)+len(plan.Moves))
+	candidates := make([]string, 0, len(files)+len(plan.Moves))
+	for _, move := range plan.Moves {
+		candidates = append(candidates, move.From)
+	}
+	candidates = append(candidates, files...)
+	for _, path := range candidates {
+		path = filepath.Clean(path)
+		if checked[path] {
+			continue
+		}
+		checked[path] = true
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		newPath := path
+		if to, ok := moved[path]; ok {
+			newPath = to
+		}
+		r := rewriter{links: l, oldPath: path, newPath: newPath}
+		updated, changes := r.rewrite(content)
+		if len(changes) > 0 {
+			plan.Rewrites = append(plan.Rewrites, Rewrite{Path: path, Changes: changes, content: updated})
+		}
+	}
+	sort.Slice(plan.Rewrites, func(i, j int) bool {
+		return plan.Rewrites[i].Path < plan.Rewrites[j].Path
+	})
+	return plan, nil
+}
+
+// Apply creates, rewrites, moves, and trashes notes in that order.
+// Everything is journaled as one group, so a single undo reverts the plan.
+func (p *Plan) Apply(h *handler.FileHandler) error {
+	return h.Journal().Group(func() error {
+		for _, note := range p.Creates {
+			if _, err := os.Stat(note.Path); err == nil {
+				return fmt.Errorf("%s already exists", p.rel(note.Path))
+			}
+			if err := h.WriteFileOp(p.op, note.Path, note.content); err != nil {
+				return fmt.Errorf("create %s: %w", p.rel(note.Path), err)
+			}
+		}
+		for _, rewrite := range p.Rewrites {
+			op := "relink"
+			if rewrite.Summary != "" {
+				op = p.op
+			}
+			if err := h.WriteFileOp(op, rewrite.Path, rewrite.content); err != nil {
+				return fmt.Errorf("update links in %s: %w", p.rel(rewrite.Path), err)
+			}
+		}
+		for _, move := range p.Moves {
+			if err := h.Move(move.From, move.To); err != nil {
+				return fmt.Errorf("move %s: %w", p.rel(move.From), err)
+			}
+		}
+		for _, path := range p.Trash {
+			if err := h.TrashWithReason(path, p.TrashReason); err != nil {
+				return fmt.Errorf("trash %s: %w", p.rel(path), err)
+			}
+		}
+		return nil
+	})
+}
+
+// Describe writes the plan, one change per line, with paths relative to
+// the vault.
+func (p *Plan) Describe(w io.Writer) {
+	for _, note := range p.Creates {
+		fmt.Fprintf(w, "create %s\n", p.rel(note.Path))
+	}
+	for _, move := range p.Moves {
+		fmt.Fprintf(w, "move   %s -> %s\n", p.rel(move.From), p.rel(move.To))
+	}
+	for _, rewrite := range p.Rewrites {
+		if rewrite.Summary != "" {
+			fmt.Fprintf(w, "update %s: %s\n", p.rel(rewrite.Path), rewrite.Summary)
+		}
+		for _, change := range rewrite.Changes {
+			fmt.Fprintf(w, "relink %s: %s -> %s\n", p.rel(rewrite.Path), change.Old, change.New)
+		}
+	}
+	for _, path := range p.Trash {
+		fmt.Fprintf(w, "trash  %s\n", p.rel(path))
+	}
+}
+
+// Relinked counts the notes whose links change, besides the notes the plan
+// creates or rewrites wholesale.
+func (p *Plan) Relinked() int {
+	count := 0
+	for _, rewrite := range p.Rewrites {
+		if rewrite.Summary == "" {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *Plan) rel(path string) string {
+	rel, err := filepath.Rel(p.vault, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// section is where a link to a heading points after a split: the new note
+// and, for headings below the split level, the heading inside it.
+type section struct {
+	path    string
+	heading string
+}
+
+// links describes how note paths change so link targets can be redirected.
+// moved maps old note paths to new ones; sections maps a note path and a
+// normalised heading to the note that heading now lives in.
+type links struct {
+	vault    string
+	moved    map[string]string
+	sections map[string]map[string]section
+	names    map[string]string
+}
+
+func newLinks(vault string, moved map[string]string, sections map[string]map[string]section) *links {
+	l := &links{vault: vault, moved: moved, sections: sections, names: make(map[string]string)}
+	for path := range moved {
+		l.names[noteName(path)] = path
+	}
+	for path := range sections {
+		l.names[noteName(path)] = path
+	}
+	return l
+}
+
+func (l *links) known(path string) bool {
+	if _, ok := l.moved[path]; ok {
+		return true
+	}
+	_, ok := l.sections[path]
+	return ok
+}
+
+// redirect returns where a link to abs#anchor points after the change.
+// bySection reports that the anchor named a heading that moved to another
+// note, in which case the returned heading replaces the anchor. Links that
+// leave out the `.md` extension keep leaving it out.
+func (l *links) redirect(abs, anchor string) (target section, bySection, ok bool) {
+	file, trimmed := abs, false
+	if !strings.HasSuffix(abs, ".md") && l.known(abs+".md") {
+		file, trimmed = abs+".md", true
+	}
+	trim := func(path string) string {
+		if trimmed {
+			return strings.TrimSuffix(path, ".md")
+		}
+		return path
+	}
+	if anchor != "" {
+		if s, found := l.sections[file][anchorKey(anchor)]; found {
+			return section{path: trim(s.path), heading: s.heading}, true, true
+		}
+	}
+	if to, found := l.moved[file]; found {
+		return section{path: trim(to), heading: anchor}, false, true
+	}
+	return section{path: abs, heading: anchor}, false, false
+}
+
+type rewriter struct {
+	*links
+	oldPath string
+	newPath string
+}
+
+func (r rewriter) rewrite(content []byte) ([]byte, []LinkChange) {
+	var changes []LinkChange
+	text := replaceGroup(string(content), markdownLinkRe, func(link string) string {
+		updated := r.markdownTarget(link)
+		if updated != link {
+			changes = append(changes, LinkChange{Old: link, New: updated})
+		}
+		return updated
+	})
+	text = wikiLinkRe.ReplaceAllStringFunc(text, func(match string) string {
+		parts := wikiLinkRe.FindStringSubmatch(match)
+		link := parts[1] + parts[2]
+		updated := r.wikiTarget(parts[1], strings.TrimPrefix(parts[2], "#"))
+		if updated == link {
+			return match
+		}
+		changes = append(changes, LinkChange{Old: link, New: updated})
+		return "[[" + updated + parts[3] + "]]"
+	})
+	return []byte(text), changes
+}
+
+// markdownTarget rewrites a link relative to the note so that it still
+// points at the same file, or the note a heading was split into.
+func (r rewriter) markdownTarget(link string) string {
+	lowered := strings.ToLower(link)
+	if link == "" || strings.HasPrefix(link, "/") ||
+		strings.Contains(lowered, "://") || strings.HasPrefix(lowered, "mailto:") {
+		return link
+	}
+
+	target, anchor := link, ""
+	if hash := strings.Index(link, "#"); hash >= 0 {
+		target, anchor = link[:hash], link[hash+1:]
+	}
+	escaped := strings.Contains(target, "%")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	abs := r.oldPath
+	if target != "" {
+		abs = filepath.Join(filepath.Dir(r.oldPath), filepath.FromSlash(target))
+	}
+	to, bySection, changed := r.redirect(abs, anchor)
+	if target == "" && !bySection || !changed && r.newPath == r.oldPath {
+		return link
+	}
+	if bySection {
+		anchor = anchorKey(to.heading)
+	}
+	if bySection && anchor != "" && strings.TrimSuffix(to.path, ".md") == strings.TrimSuffix(r.newPath, ".md") {
+		return "#" + anchor
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(r.newPath), to.path)
+	if err != nil {
+		return link
+	}
+	updated := filepath.ToSlash(rel)
+	if strings.HasPrefix(target, "./") && !strings.HasPrefix(updated, "../") {
+		updated = "./" + updated
+	}
+	if escaped {
+		updated = strings.ReplaceAll(updated, " ", "%20")
+	}
+	if anchor != "" {
+		updated += "#" + anchor
+	}
+	return updated
+}
+
+// wikiTarget rewrites a wiki link target and anchor. Path-qualified links
+// such as [[atoms/idea]] resolve from the vault root and follow moves; bare
+// names resolve anywhere and only change when the note is renamed.
+func (r rewriter) wikiTarget(target, anchor string) string {
+	link := target
+	if anchor != "" {
+		link += "#" + anchor
+	}
+
+	name := strings.TrimSpace(target)
+	qualified := strings.Contains(name, "/")
+	var abs string
+	switch {
+	case name == "":
+		abs = r.oldPath
+	case qualified:
+		abs = filepath.Join(r.vault, filepath.FromSlash(strings.Trim(name, "/")))
+	default:
+		path, ok := r.names[strings.TrimSuffix(name, ".md")]
+		if !ok {
+			return link
+		}
+		abs = path
+		if !strings.HasSuffix(name, ".md") {
+			abs = strings.TrimSuffix(path, ".md")
+		}
+	}
+
+	to, bySection, changed := r.redirect(abs, anchor)
+	if !changed || name == "" && !bySection {
+		return link
+	}
+	if bySection {
+		anchor = to.heading
+	}
+
+	self := bySection && strings.TrimSuffix(to.path, ".md") == strings.TrimSuffix(r.newPath, ".md")
+	var updated string
+	switch {
+	case self && anchor != "":
+	case name == "":
+		updated = noteName(to.path)
+	case qualified:
+		rel, err := filepath.Rel(r.vault, to.path)
+		if err != nil {
+			return link
+		}
+		updated = filepath.ToSlash(rel)
+	default:
+		updated = filepath.Base(to.path)
+	}
+	if anchor != "" {
+		updated += "#" + anchor
+	}
+	if updated == "" {
+		return link
+	}
+	return updated
+}
+
+// noteName is the bare wiki link name of a note.
+func noteName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".md")
+}
+
+// anchorKey normalises a heading or link anchor so that "My Heading" and
+// "my-heading" compare equal.
+func anchorKey(anchor string) string {
+	return anchorRe.ReplaceAllString(strings.Join(strings.Fields(strings.ToLower(anchor)), "-"), "")
+}
+
+// replaceGroup replaces the first capture group of every match of re.
+func replaceGroup(text string, re *regexp.Regexp, replace func(string) string) string {
+	matches := re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2], match[3]
+		out.WriteString(text[last:start])
+		out.WriteString(replace(text[start:end]))
+		last = end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
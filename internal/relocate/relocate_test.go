@@ -0,0 +1,130 @@
+package relocate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+func writeNote(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func readNote(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRelocateRewritesLinks(t *testing.T) {
+	vault := t.TempDir()
+	idea := filepath.Join(vault, "atoms", "idea.md")
+	sibling := filepath.Join(vault, "atoms", "sibling.md")
+	hub := filepath.Join(vault, "hubs", "index.md")
+
+	writeNote(t, idea, "See [sibling](sibling.md), [image](../assets/diagram.png), [web](https://example.com) and [top](#top).\n")
+	writeNote(t, sibling, "Back to [idea](./idea.md#summary) and [bare](idea).\n")
+	writeNote(t, hub, "---\nup: \"[[atoms/idea]]\"\n---\n- [idea](../atoms/idea.md)\n- [[idea]]\n- [other](../atoms/sibling.md)\n")
+
+	h := handler.NewFileHandler(vault)
+	files, err := h.WalkFiles(nil, nil, "")
+	if err != nil {
+		t.Fatalf("WalkFiles returned error: %v", err)
+	}
+
+	plan, err := Build(vault, []string{idea}, "projects/2024", files)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	var out bytes.Buffer
+	plan.Describe(&out)
+	for _, want := range []string{
+		"move   atoms/idea.md -> projects/2024/idea.md",
+		"relink atoms/idea.md: sibling.md -> ../../atoms/sibling.md",
+		"relink atoms/sibling.md: ./idea.md#summary -> ../projects/2024/idea.md#summary",
+		"relink hubs/index.md: atoms/idea -> projects/2024/idea",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected plan to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if _, err := os.Stat(idea); err != nil {
+		t.Fatalf("expected Build to leave the vault untouched: %v", err)
+	}
+
+	if err := plan.Apply(h); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	moved := filepath.Join(vault, "projects", "2024", "idea.md")
+	if got, want := readNote(t, moved), "See [sibling](../../atoms/sibling.md), [image](../../assets/diagram.png), [web](https://example.com) and [top](#top).\n"; got != want {
+		t.Fatalf("unexpected moved note:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := readNote(t, sibling), "Back to [idea](../projects/2024/idea.md#summary) and [bare](../projects/2024/idea).\n"; got != want {
+		t.Fatalf("unexpected sibling:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := readNote(t, hub), "---\nup: \"[[projects/2024/idea]]\"\n---\n- [idea](../projects/2024/idea.md)\n- [[idea]]\n- [other](../atoms/sibling.md)\n"; got != want {
+		t.Fatalf("unexpected hub:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if !strings.Contains(readNote(t, idea), "[sibling](sibling.md)") {
+		t.Fatalf("expected one undo to restore the moved note")
+	}
+	if !strings.Contains(readNote(t, hub), "[idea](../atoms/idea.md)") {
+		t.Fatalf("expected one undo to restore backlinks")
+	}
+}
+
+func TestRelocateMovesNotesTogether(t *testing.T) {
+	vault := t.TempDir()
+	a := filepath.Join(vault, "inbox", "a.md")
+	b := filepath.Join(vault, "inbox", "b.md")
+	writeNote(t, a, "[b](b.md)\n")
+	writeNote(t, b, "[a](a.md)\n")
+
+	plan, err := Build(vault, []string{a, b}, "atoms", nil)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if len(plan.Rewrites) != 0 {
+		t.Fatalf("expected links between notes moving together to stay valid, got %#v", plan.Rewrites)
+	}
+}
+
+func TestRelocateRejectsConflicts(t *testing.T) {
+	vault := t.TempDir()
+	a := filepath.Join(vault, "inbox", "note.md")
+	b := filepath.Join(vault, "drafts", "note.md")
+	existing := filepath.Join(vault, "atoms", "taken.md")
+	taken := filepath.Join(vault, "inbox", "taken.md")
+	for _, path := range []string{a, b, existing, taken} {
+		writeNote(t, path, "x\n")
+	}
+
+	if _, err := Build(vault, []string{a, b}, "atoms", nil); err == nil {
+		t.Fatalf("expected notes with the same name to conflict")
+	}
+	if _, err := Build(vault, []string{taken}, "atoms", nil); err == nil {
+		t.Fatalf("expected an existing destination to be refused")
+	}
+	if _, err := Build(vault, []string{a}, "../outside", nil); err == nil {
+		t.Fatalf("expected a destination outside the vault to be refused")
+	}
+}
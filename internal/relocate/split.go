@@ -0,0 +1,244 @@
+package relocate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Paintersrp/an/internal/frontmatter"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fenceRe   = regexp.MustCompile("^\\s*(```|~~~)")
+	ruleRe    = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	slugRe    = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// keptHeadings are sections a split leaves in the parent, such as the
+// relations list the default zet template ends with.
+var keptHeadings = map[string]bool{"relations": true}
+
+type heading struct {
+	line  int
+	level int
+	text  string
+}
+
+// splitSection is one heading at the split level and the lines under it,
+// body[start:end].
+type splitSection struct {
+	heading
+	end   int
+	subs  []heading
+	path  string
+	title string
+}
+
+// Split plans turning every heading of the given level in note into a note
+// of its own, next to the source. The new notes copy the source's front
+// matter, so they keep its template layout and tags, with the title set to
+// the heading and `up` pointing back at the source. The source keeps
+// everything else, with each section replaced by a link to its new note.
+// Links to the split headings, such as [[note#Heading]], are retargeted in
+// every note in files.
+func Split(vault, note string, level int, files []string, now time.Time) (*Plan, error) {
+	if level < 1 || level > 6 {
+		return nil, fmt.Errorf("heading level must be between 1 and 6, got %d", level)
+	}
+	note = filepath.Clean(note)
+	content, err := os.ReadFile(note)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(note), err)
+	}
+
+	plan := &Plan{op: "split", vault: vault}
+	lines := strings.Split(doc.Body(), "\n")
+	sections := findSections(lines, level)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%s has no level %d headings to split", plan.rel(note), level)
+	}
+
+	dir := filepath.Dir(note)
+	targets := make(map[string]map[string]section)
+	anchors := make(map[string]section)
+	seen := make(map[string]string)
+	for i := range sections {
+		s := &sections[i]
+		slug := slugify(s.text)
+		if slug == "" {
+			return nil, fmt.Errorf("heading %q has no characters usable in a file name", s.text)
+		}
+		s.path = filepath.Join(dir, slug+".md")
+		s.title = s.text
+		if other, clash := seen[s.path]; clash {
+			return nil, fmt.Errorf("headings %q and %q would both become %s", other, s.text, plan.rel(s.path))
+		}
+		if _, err := os.Stat(s.path); err == nil {
+			return nil, fmt.Errorf("%s already exists", plan.rel(s.path))
+		}
+		seen[s.path] = s.text
+
+		anchors[anchorKey(s.text)] = section{path: s.path}
+		for _, sub := range s.subs {
+			anchors[anchorKey(sub.text)] = section{path: s.path, heading: sub.text}
+		}
+	}
+	targets[note] = anchors
+	l := newLinks(vault, nil, targets)
+
+	for _, s := range sections {
+		child, err := frontmatter.Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		if err := child.Set("title", s.title); err != nil {
+			return nil, err
+		}
+		for _, key := range []string{"created", "date"} {
+			if _, ok := child.Get(key); ok {
+				if err := child.Set(key, now.UTC().Format("20060102150405")); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := child.Set("up", "[["+noteName(note)+"]]"); err != nil {
+			return nil, err
+		}
+
+		text := strings.Trim(strings.Join(lines[s.line+1:s.end], "\n"), "\n")
+		r := rewriter{links: l, oldPath: note, newPath: s.path}
+		updated, _ := r.rewrite([]byte(text))
+		child.SetBody("\n" + string(updated) + "\n")
+
+		data, err := child.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		plan.Creates = append(plan.Creates, Note{Path: s.path, content: data})
+	}
+
+	var parent []string
+	next := 0
+	for i := 0; i < len(lines); {
+		if next < len(sections) && sections[next].line == i {
+			s := sections[next]
+			item := "- [[" + noteName(s.path) + "]]"
+			if noteName(s.path) != s.text {
+				item = "- [[" + noteName(s.path) + "|" + s.text + "]]"
+			}
+			parent = append(parent, item)
+			i = s.end
+			next++
+			gap := i
+			for gap < len(lines) && strings.TrimSpace(lines[gap]) == "" {
+				gap++
+			}
+			if next < len(sections) && sections[next].line == gap {
+				i = gap
+				continue
+			}
+			if i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				parent = append(parent, "")
+			}
+			continue
+		}
+		parent = append(parent, lines[i])
+		i++
+	}
+	r := rewriter{links: l, oldPath: note, newPath: note}
+	body, changes := r.rewrite([]byte(strings.Join(parent, "\n")))
+	doc.SetBody(string(body))
+	data, err := doc.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	plan.Rewrites = append(plan.Rewrites, Rewrite{
+		Path:    note,
+		Summary: fmt.Sprintf("replaced %d section(s) with links", len(sections)),
+		Changes: changes,
+		content: data,
+	})
+
+	rewrites, err := relink(l, files, map[string]bool{note: true})
+	if err != nil {
+		return nil, err
+	}
+	plan.Rewrites = append(plan.Rewrites, rewrites...)
+	return plan, nil
+}
+
+// findSections returns the headings of the given level, outside code
+// fences, with the lines each one spans. A section ends at the next heading
+// of the same or a higher level; trailing blank lines and thematic breaks
+// stay with the parent.
+func findSections(lines []string, level int) []splitSection {
+	var headings []heading
+	inFence := false
+	for i, line := range lines {
+		if fenceRe.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil && m[2] != "" {
+			headings = append(headings, heading{line: i, level: len(m[1]), text: m[2]})
+		}
+	}
+
+	var sections []splitSection
+	for i, h := range headings {
+		if h.level != level || keptHeadings[strings.ToLower(strings.Trim(h.text, "*_ "))] {
+			continue
+		}
+		s := splitSection{heading: h, end: len(lines)}
+		for _, next := range headings[i+1:] {
+			if next.level <= level {
+				s.end = next.line
+				break
+			}
+			s.subs = append(s.subs, next)
+		}
+		for s.end > h.line+1 && (strings.TrimSpace(lines[s.end-1]) == "" || ruleRe.MatchString(lines[s.end-1])) {
+			s.end--
+		}
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// relink rewrites links in files, skipping the notes in skip.
+func relink(l *links, files []string, skip map[string]bool) ([]Rewrite, error) {
+	var rewrites []Rewrite
+	checked := make(map[string]bool, len(files))
+	for _, path := range files {
+		path = filepath.Clean(path)
+		if skip[path] || checked[path] {
+			continue
+		}
+		checked[path] = true
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		r := rewriter{links: l, oldPath: path, newPath: path}
+		if updated, changes := r.rewrite(content); len(changes) > 0 {
+			rewrites = append(rewrites, Rewrite{Path: path, Changes: changes, content: updated})
+		}
+	}
+	return rewrites, nil
+}
+
+func slugify(value string) string {
+	return strings.Trim(slugRe.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
@@ -0,0 +1,121 @@
+package relocate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+func TestSplitTurnsSectionsIntoNotes(t *testing.T) {
+	vault := t.TempDir()
+	source := filepath.Join(vault, "atoms", "guide.md")
+	other := filepath.Join(vault, "atoms", "other.md")
+
+	writeNote(t, source, strings.Join([]string{
+		"---",
+		"title: guide",
+		"created: 20240101000000",
+		"up: \"[[hub]]\"",
+		"tags:",
+		"  - go",
+		"---",
+		"",
+		"Intro text.",
+		"",
+		"## Install Steps",
+		"",
+		"Run the installer.",
+		"",
+		"### Linux",
+		"",
+		"Use the package.",
+		"",
+		"```sh",
+		"## not a heading",
+		"```",
+		"",
+		"## Usage",
+		"",
+		"See [install](#install-steps).",
+		"",
+		"---",
+		"",
+		"## **Relations**",
+		"",
+		"[[hub]]",
+		"",
+	}, "\n"))
+	writeNote(t, other, "Read [[guide#Usage]], [[guide#Linux|linux notes]] and [steps](guide.md#install-steps).\n")
+
+	h := handler.NewFileHandler(vault)
+	files, err := h.WalkFiles(nil, nil, "")
+	if err != nil {
+		t.Fatalf("WalkFiles returned error: %v", err)
+	}
+
+	plan, err := Split(vault, source, 2, files, time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+	var out bytes.Buffer
+	plan.Describe(&out)
+	for _, want := range []string{
+		"create atoms/install-steps.md",
+		"create atoms/usage.md",
+		"update atoms/guide.md: replaced 2 section(s) with links",
+		"relink atoms/other.md: guide#Usage -> usage",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected plan to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	if err := plan.Apply(h); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	wantParent := "---\ntitle: guide\ncreated: 20240101000000\nup: \"[[hub]]\"\ntags:\n  - go\n---\n\nIntro text.\n\n- [[install-steps|Install Steps]]\n- [[usage|Usage]]\n\n---\n\n## **Relations**\n\n[[hub]]\n"
+	if got := readNote(t, source); got != wantParent {
+		t.Fatalf("unexpected parent:\n%s\nwant:\n%s", got, wantParent)
+	}
+
+	install := readNote(t, filepath.Join(vault, "atoms", "install-steps.md"))
+	for _, want := range []string{"title: Install Steps", "created: \"20240506070809\"", "up: \"[[guide]]\"", "  - go", "### Linux", "## not a heading"} {
+		if !strings.Contains(install, want) {
+			t.Fatalf("expected new note to contain %q, got:\n%s", want, install)
+		}
+	}
+	usage := readNote(t, filepath.Join(vault, "atoms", "usage.md"))
+	if !strings.Contains(usage, "See [install](install-steps.md).") {
+		t.Fatalf("expected the section link to follow the split, got:\n%s", usage)
+	}
+
+	if got, want := readNote(t, other), "Read [[usage]], [[install-steps#Linux|linux notes]] and [steps](install-steps.md).\n"; got != want {
+		t.Fatalf("unexpected backlinks:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vault, "atoms", "usage.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected one undo to remove the new notes, got %v", err)
+	}
+	if !strings.Contains(readNote(t, source), "## Usage") {
+		t.Fatalf("expected one undo to restore the parent")
+	}
+}
+
+func TestSplitRejectsMissingHeadings(t *testing.T) {
+	vault := t.TempDir()
+	source := filepath.Join(vault, "flat.md")
+	writeNote(t, source, "# Title\n\nNo sections here.\n")
+
+	if _, err := Split(vault, source, 2, nil, time.Now()); err == nil {
+		t.Fatalf("expected an error when there is nothing to split")
+	}
+}
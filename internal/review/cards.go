@@ -0,0 +1,382 @@
+package review
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+var (
+	cardHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*$`)
+	cardTagPattern     = regexp.MustCompile(`(^|\s)#card\b`)
+)
+
+// Card is a question/answer pair declared inside a note. Cards are written
+// either as a single `question:: answer` line or as a heading tagged with
+// `#card` whose answer is the block that follows it.
+type Card struct {
+	// ID is a stable identifier derived from the question, so it survives
+	// moving or renaming the note. It doubles as the Anki GUID.
+	ID       string
+	Path     string
+	Question string
+	Answer   string
+	Line     int
+	Tags     []string
+}
+
+// ScheduleKey returns the key used to store the card in the review schedule.
+func (c Card) ScheduleKey() string {
+	return c.Path + "#" + c.ID
+}
+
+// ExtractCards parses the provided note content and returns the declared
+// cards. Relative paths are used for stable identifiers when the note lives
+// inside the vault.
+func ExtractCards(vault, path string, content []byte) []Card {
+	rel := path
+	if vault != "" {
+		if r, err := filepath.Rel(vault, path); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(rel)
+
+	lines := normalizeLogLines(string(content))
+	tags := frontMatterTags(lines)
+
+	var cards []Card
+	inFence := false
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		start = skipFrontMatter(lines, 0) + 1
+	}
+
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || trimmed == "" {
+			continue
+		}
+
+		if match := cardHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			if !cardTagPattern.MatchString(match[2]) {
+				continue
+			}
+			question := strings.TrimSpace(cardTagPattern.ReplaceAllString(match[2], "$1"))
+			answer, end := collectCardAnswer(lines, i+1)
+			if question != "" && answer != "" {
+				cards = append(cards, newCard(rel, question, answer, i+1, tags))
+			}
+			i = end - 1
+			continue
+		}
+
+		if question, answer, ok := splitInlineCard(trimmed); ok {
+			cards = append(cards, newCard(rel, question, answer, i+1, tags))
+		}
+	}
+	return cards
+}
+
+// CollectCards reads the provided note paths and returns every declared card
+// ordered by path and position.
+func CollectCards(vault string, paths []string) ([]Card, error) {
+	var cards []Card
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		cards = append(cards, ExtractCards(vault, path, content)...)
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		if cards[i].Path != cards[j].Path {
+			return cards[i].Path < cards[j].Path
+		}
+		return cards[i].Line < cards[j].Line
+	})
+	disambiguateCardIDs(cards)
+	return cards, nil
+}
+
+// disambiguateCardIDs gives every card after the first that shares a
+// question an ID that also covers its path, so the Anki GUIDs stay unique.
+func disambiguateCardIDs(cards []Card) {
+	seen := make(map[string]bool, len(cards))
+	for i := range cards {
+		if seen[cards[i].ID] {
+			cards[i].ID = cardID(cards[i].Path + "\x00" + cards[i].Question)
+		}
+		seen[cards[i].ID] = true
+	}
+}
+
+// CollectVaultCards walks the vault and returns the cards declared in every
+// note outside of the archive, trash, hidden, and ignored folders.
+func CollectVaultCards(vault string, ignored []string) ([]Card, error) {
+	paths, err := VaultNotes(vault, ignored)
+	if err != nil {
+		return nil, err
+	}
+	return CollectCards(vault, paths)
+}
+
+// VaultNotes returns the Markdown notes in the vault, skipping the archive,
+// trash, hidden, and ignored folders.
+func VaultNotes(vault string, ignored []string) ([]string, error) {
+	skip := map[string]struct{}{"archive": {}, "trash": {}}
+	for _, dir := range ignored {
+		skip[strings.ToLower(dir)] = struct{}{}
+	}
+
+	var paths []string
+	err := filepath.WalkDir(vault, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := strings.ToLower(d.Name())
+			if path == vault {
+				return nil
+			}
+			if _, ok := skip[name]; ok || strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(d.Name()), ".md") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// DueCards filters cards to those that are unscheduled or due according to
+// the provided schedule, ordering overdue cards first.
+func DueCards(cards []Card, schedule *Schedule, now time.Time) []Card {
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	type dueCard struct {
+		card Card
+		due  time.Time
+	}
+
+	due := make([]dueCard, 0, len(cards))
+	for _, card := range cards {
+		entry, ok := schedule.Entry(card.ScheduleKey())
+		if ok && entry.Due.After(now) {
+			continue
+		}
+		due = append(due, dueCard{card: card, due: entry.Due})
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].due.Before(due[j].due)
+	})
+
+	out := make([]Card, len(due))
+	for i, item := range due {
+		out[i] = item.card
+	}
+	return out
+}
+
+// CardSessionManifest describes a drilled card session so it can be written
+// with WriteMarkdownLog. Each card becomes a checklist step whose response is
+// the recorded grade.
+func CardSessionManifest(cards []Card) templater.TemplateManifest {
+	manifest := templater.TemplateManifest{
+		Name:        "review-cards",
+		Description: "Flashcard drill results.",
+	}
+	for _, card := range cards {
+		manifest.Fields = append(manifest.Fields, templater.TemplateField{
+			Key:   card.ScheduleKey(),
+			Label: fmt.Sprintf("%s (%s)", card.Question, card.Path),
+		})
+	}
+	return manifest
+}
+
+// WriteAnkiExport writes the cards as a tab-separated Anki import file. The
+// first column carries the stable card GUID so re-importing updates existing
+// notes instead of duplicating them.
+func WriteAnkiExport(w io.Writer, cards []Card) error {
+	header := []string{
+		"#separator:tab",
+		"#html:true",
+		"#notetype:Basic",
+		"#guid column:1",
+		"#tags column:4",
+	}
+	for _, line := range header {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	for _, card := range cards {
+		tags := append([]string{"an"}, card.Tags...)
+		for i, tag := range tags {
+			tags[i] = strings.ReplaceAll(strings.TrimSpace(tag), " ", "_")
+		}
+		if _, err := fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			card.ID,
+			ankiField(card.Question),
+			ankiField(card.Answer),
+			strings.Join(tags, " "),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ankiField(value string) string {
+	escaped := html.EscapeString(strings.TrimSpace(value))
+	escaped = strings.ReplaceAll(escaped, "\t", "    ")
+	return strings.ReplaceAll(escaped, "\n", "<br>")
+}
+
+func newCard(path, question, answer string, line int, tags []string) Card {
+	return Card{
+		ID:       cardID(strings.Join(strings.Fields(question), " ")),
+		Path:     path,
+		Question: question,
+		Answer:   answer,
+		Line:     line,
+		Tags:     append([]string(nil), tags...),
+	}
+}
+
+func cardID(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// legacyCardID is the path-dependent ID cards had before IDs were derived
+// from the question alone. Schedules may still be keyed by it.
+func legacyCardID(card Card) string {
+	return cardID(card.Path + "\x00" + card.Question)
+}
+
+// splitInlineCard splits a `question:: answer` line. The separator must be
+// followed by whitespace and sit outside inline code and Dataview-style
+// `[key:: value]` or `(key:: value)` fields, so `std::vector`, `Foo::Bar`
+// and code spans such as `a :: b` are not cards.
+func splitInlineCard(line string) (string, string, bool) {
+	idx := inlineCardSeparator(line)
+	if idx < 0 {
+		return "", "", false
+	}
+	question := strings.TrimSpace(line[:idx])
+	answer := strings.TrimSpace(line[idx+2:])
+	question = strings.TrimSpace(strings.TrimLeft(question, "-*+ "))
+	if question == "" || answer == "" {
+		return "", "", false
+	}
+	return question, answer, true
+}
+
+// inlineCardSeparator returns the index of the first card separator in
+// line, or -1.
+func inlineCardSeparator(line string) int {
+	inCode := false
+	depth := 0
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '`':
+			inCode = !inCode
+		case inCode:
+		case c == '[' || c == '(':
+			depth++
+		case (c == ']' || c == ')') && depth > 0:
+			depth--
+		case depth > 0:
+		case strings.HasPrefix(line[i:], "::") && (i == 0 || line[i-1] != ':') && i+2 < len(line) && isBlank(line[i+2]):
+			return i
+		}
+	}
+	return -1
+}
+
+func isBlank(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// collectCardAnswer gathers the block following a card heading, stopping at
+// the next heading or thematic break. It returns the answer and the index of
+// the line that ended the block.
+func collectCardAnswer(lines []string, start int) (string, int) {
+	var block []string
+	inFence := false
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		} else if !inFence && (cardHeadingPattern.MatchString(trimmed) || trimmed == "---") {
+			break
+		}
+		block = append(block, strings.TrimRight(lines[i], " \t"))
+	}
+	return strings.TrimSpace(strings.Join(block, "\n")), i
+}
+
+func frontMatterTags(lines []string) []string {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+	end := skipFrontMatter(lines, 0)
+	var tags []string
+	inTags := false
+	for _, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "tags:"):
+			rest := strings.TrimSpace(strings.TrimPrefix(trimmed, "tags:"))
+			inTags = rest == ""
+			rest = strings.Trim(rest, "[]")
+			for _, tag := range strings.Split(rest, ",") {
+				if tag = strings.Trim(strings.TrimSpace(tag), `"'`); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		case inTags && strings.HasPrefix(trimmed, "-"):
+			if tag := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")), `"'`); tag != "" {
+				tags = append(tags, tag)
+			}
+		default:
+			inTags = false
+		}
+	}
+	return tags
+}
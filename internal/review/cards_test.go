@@ -0,0 +1,176 @@
+package review
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractCardsParsesInlineAndHeadingCards(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	path := filepath.Join(vault, "atoms", "sm2.md")
+	content := strings.Join([]string{
+		"---",
+		"title: SM-2",
+		"tags:",
+		"  - memory",
+		"---",
+		"# SM-2",
+		"",
+		"What does SM-2 adjust after each review? :: The ease factor",
+		"",
+		"```",
+		"ignored :: inside code",
+		"```",
+		"",
+		"## Why do intervals grow? #card",
+		"Each successful recall multiplies",
+		"the interval by the ease.",
+		"",
+		"## Notes",
+		"Not a card.",
+	}, "\n")
+
+	cards := ExtractCards(vault, path, []byte(content))
+	if len(cards) != 2 {
+		t.Fatalf("expected 2 cards, got %d: %+v", len(cards), cards)
+	}
+
+	if cards[0].Question != "What does SM-2 adjust after each review?" || cards[0].Answer != "The ease factor" {
+		t.Fatalf("unexpected inline card: %+v", cards[0])
+	}
+	if cards[1].Question != "Why do intervals grow?" {
+		t.Fatalf("unexpected heading card question: %q", cards[1].Question)
+	}
+	if cards[1].Answer != "Each successful recall multiplies\nthe interval by the ease." {
+		t.Fatalf("unexpected heading card answer: %q", cards[1].Answer)
+	}
+	if cards[0].Path != "atoms/sm2.md" || len(cards[0].Tags) != 1 || cards[0].Tags[0] != "memory" {
+		t.Fatalf("expected relative path and note tags, got %+v", cards[0])
+	}
+
+	again := ExtractCards(vault, path, []byte(content))
+	if again[0].ID != cards[0].ID || cards[0].ID == cards[1].ID {
+		t.Fatalf("expected stable, distinct card IDs: %q %q", cards[0].ID, cards[1].ID)
+	}
+}
+
+func TestExtractCardsIgnoresNonCardDoubleColons(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Join([]string{
+		"Q:: answer",
+		"- [due:: 2024-01-01] pay rent",
+		"Use std::vector when the size changes.",
+		"Call Foo::Bar() to reset.",
+		"Run `a :: b` in GHCi to see the type.",
+		"Prepend with `x :: xs` :: cons",
+		"```haskell",
+		"length :: [a] -> Int",
+		"```",
+	}, "\n")
+
+	cards := ExtractCards("", "note.md", []byte(content))
+	if len(cards) != 2 {
+		t.Fatalf("expected only the real cards, got %+v", cards)
+	}
+	if cards[0].Question != "Q" || cards[0].Answer != "answer" {
+		t.Fatalf("unexpected card: %+v", cards[0])
+	}
+	if cards[1].Question != "Prepend with `x :: xs`" || cards[1].Answer != "cons" {
+		t.Fatalf("unexpected card: %+v", cards[1])
+	}
+}
+
+func TestCardIDsSurviveMovesAndRelinkSchedule(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	moved := newCard("projects/sm2.md", "What is SM-2?", "A scheduler", 1, nil)
+	if original := newCard("atoms/sm2.md", "What  is SM-2?", "A scheduler", 1, nil); original.ID != moved.ID {
+		t.Fatalf("expected the ID to ignore the path, got %q and %q", original.ID, moved.ID)
+	}
+	legacy := newCard("inbox/old.md", "Why review?", "To remember", 1, nil)
+
+	schedule, err := LoadSchedule(vault)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+	schedule.Grade("atoms/sm2.md#"+moved.ID, GradeGood, now)
+	schedule.Grade(legacy.Path+"#"+legacyCardID(legacy), GradeEasy, now)
+
+	if !schedule.RelinkCards([]Card{moved, legacy}) {
+		t.Fatalf("expected RelinkCards to move entries")
+	}
+	if keys := schedule.Keys(); len(keys) != 2 || keys[0] != legacy.ScheduleKey() || keys[1] != moved.ScheduleKey() {
+		t.Fatalf("expected entries under the current keys, got %v", keys)
+	}
+	if schedule.RelinkCards([]Card{moved, legacy}) {
+		t.Fatalf("expected a second relink to be a no-op")
+	}
+}
+
+func TestCollectCardsKeepsGUIDsUnique(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	for _, name := range []string{"a.md", "b.md"} {
+		writeFile(t, filepath.Join(vault, name), "Same question?:: yes\n")
+	}
+	cards, err := CollectCards(vault, []string{filepath.Join(vault, "a.md"), filepath.Join(vault, "b.md")})
+	if err != nil {
+		t.Fatalf("CollectCards returned error: %v", err)
+	}
+	if len(cards) != 2 || cards[0].ID == cards[1].ID {
+		t.Fatalf("expected distinct IDs for repeated questions, got %+v", cards)
+	}
+	if cards[0].ID != newCard("a.md", "Same question?", "yes", 1, nil).ID {
+		t.Fatalf("expected the first card to keep the question ID")
+	}
+}
+
+func TestDueCardsSkipsScheduledFutureCards(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	cards := []Card{
+		newCard("a.md", "first", "1", 1, nil),
+		newCard("a.md", "second", "2", 2, nil),
+	}
+
+	schedule, err := LoadSchedule(vault)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+	schedule.Grade(cards[0].ScheduleKey(), GradeGood, now)
+
+	due := DueCards(cards, schedule, now)
+	if len(due) != 1 || due[0].Question != "second" {
+		t.Fatalf("expected only the unscheduled card to be due, got %+v", due)
+	}
+}
+
+func TestWriteAnkiExport(t *testing.T) {
+	t.Parallel()
+
+	card := newCard("atoms/a.md", "What is <b>?", "line one\nline\ttwo", 3, []string{"study notes"})
+	var buf bytes.Buffer
+	if err := WriteAnkiExport(&buf, []Card{card}); err != nil {
+		t.Fatalf("WriteAnkiExport returned error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "#guid column:1\n") {
+		t.Fatalf("expected guid header, got %q", out)
+	}
+	want := card.ID + "\tWhat is &lt;b&gt;?\tline one<br>line    two\tan study_notes\n"
+	if !strings.HasSuffix(out, want) {
+		t.Fatalf("unexpected card row:\n%q\nwant suffix\n%q", out, want)
+	}
+}
@@ -12,7 +12,8 @@ import (
 
 // RunChecklist steps through the provided template manifest, prompting the
 // reader for responses and displaying contextual resurfacing suggestions to the
-// writer.
+// writer. Responses are validated against the field type; invalid answers are
+// re-prompted until the reader is exhausted.
 func RunChecklist(
 	manifest templater.TemplateManifest,
 	queue []ResurfaceItem,
@@ -57,20 +58,44 @@ func RunChecklist(
 			}
 		}
 
-		if len(field.Options) > 0 {
-			fmt.Fprintf(writer, "Options: %s\n", strings.Join(field.Options, ", "))
+		kind := FieldKind(field)
+		if hint := FieldHint(field); hint != "" {
+			fmt.Fprintln(writer, hint)
 		}
-
-		fmt.Fprint(writer, "> ")
-		input, err := bufReader.ReadString('\n')
-		if err != nil && err != io.EOF {
-			return responses, err
+		switch kind {
+		case FieldSelect, FieldMultiSelect:
+			for i, option := range field.Options {
+				fmt.Fprintf(writer, "  %d) %s\n", i+1, option)
+			}
+		case FieldNotes:
+			if len(queue) == 0 {
+				fmt.Fprintln(writer, "No resurfacing candidates to pick from.")
+			}
+			for i, item := range queue {
+				fmt.Fprintf(writer, "  %d) %s\n", i+1, item.Path)
+			}
+		case FieldTextarea:
+			fmt.Fprintln(writer, "Finish with an empty line.")
+		}
+		if field.Default != "" {
+			fmt.Fprintf(writer, "Default: %s\n", field.Default)
 		}
-		cleaned := strings.TrimSpace(input)
-		responses[field.Key] = cleaned
 
-		if err == io.EOF {
-			break
+		for {
+			fmt.Fprint(writer, "> ")
+			input, eof, err := readChecklistInput(bufReader, kind == FieldTextarea)
+			if err != nil {
+				return responses, err
+			}
+			value, verr := NormalizeResponse(field, input, queue)
+			if verr == nil {
+				responses[field.Key] = value
+				break
+			}
+			if eof {
+				return responses, fmt.Errorf("%s: %w", fieldTitle(field), verr)
+			}
+			fmt.Fprintf(writer, "%v\n", verr)
 		}
 	}
 
@@ -98,3 +123,26 @@ func humanizeKey(key string) string {
 	}
 	return strings.Join(parts, " ")
 }
+
+// readChecklistInput reads a single line, or every line up to the first empty
+// one when multiline is set. It reports whether the reader is exhausted.
+func readChecklistInput(reader *bufio.Reader, multiline bool) (string, bool, error) {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", false, err
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if !multiline {
+			return strings.TrimSpace(trimmed), err == io.EOF, nil
+		}
+		if strings.TrimSpace(trimmed) == "" || err == io.EOF {
+			if strings.TrimSpace(trimmed) != "" {
+				lines = append(lines, trimmed)
+			}
+			return strings.TrimSpace(strings.Join(lines, "\n")), err == io.EOF, nil
+		}
+		lines = append(lines, trimmed)
+	}
+}
@@ -0,0 +1,274 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+// Checklist field kinds understood by the review checklist. Manifests set them
+// through the field `type`; aliases are normalized by FieldKind.
+const (
+	FieldText        = "text"
+	FieldTextarea    = "textarea"
+	FieldSelect      = "select"
+	FieldMultiSelect = "multiselect"
+	FieldBool        = "bool"
+	FieldRating      = "rating"
+	FieldNotes       = "notes"
+)
+
+const (
+	ratingMin = 1
+	ratingMax = 5
+)
+
+// FieldKind resolves the checklist kind for a manifest field. Text fields that
+// declare options are treated as select lists, and multi selects are derived
+// from the Multi flag.
+func FieldKind(field templater.TemplateField) string {
+	switch strings.ToLower(strings.TrimSpace(field.Type)) {
+	case "textarea", "multiline", "long":
+		return FieldTextarea
+	case "multiselect", "multi-select":
+		return FieldMultiSelect
+	case "bool", "boolean", "yesno", "yes/no", "confirm":
+		return FieldBool
+	case "rating", "scale":
+		return FieldRating
+	case "notes", "queue", "pick-notes":
+		return FieldNotes
+	}
+	if len(field.Options) > 0 || strings.EqualFold(strings.TrimSpace(field.Type), FieldSelect) {
+		if field.Multi {
+			return FieldMultiSelect
+		}
+		return FieldSelect
+	}
+	return FieldText
+}
+
+// FieldHint describes how a field expects to be answered.
+func FieldHint(field templater.TemplateField) string {
+	switch FieldKind(field) {
+	case FieldTextarea:
+		return "Multi-line answer."
+	case FieldSelect:
+		return "Choose one option by name or number."
+	case FieldMultiSelect:
+		return "Choose one or more options by name or number, separated by commas."
+	case FieldBool:
+		return "Answer yes or no."
+	case FieldRating:
+		return fmt.Sprintf("Rate from %d to %d.", ratingMin, ratingMax)
+	case FieldNotes:
+		return "Pick notes from the resurfacing queue by number, separated by commas."
+	}
+	return ""
+}
+
+// NormalizeResponse validates raw input for the field and returns the
+// canonical string stored in the response map. Empty input falls back to the
+// field default and is rejected when the field is required. Option and queue
+// picks may be given by 1-based number.
+func NormalizeResponse(field templater.TemplateField, raw string, queue []ResurfaceItem) (string, error) {
+	kind := FieldKind(field)
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		value = strings.TrimSpace(field.Default)
+	}
+	if value == "" {
+		if field.Required {
+			return "", errors.New("a response is required")
+		}
+		return "", nil
+	}
+
+	switch kind {
+	case FieldSelect:
+		return matchOption(value, field.Options)
+	case FieldMultiSelect:
+		var picked []string
+		for _, entry := range splitResponseList(value) {
+			option, err := matchOption(entry, field.Options)
+			if err != nil {
+				return "", err
+			}
+			picked = appendUnique(picked, option)
+		}
+		return strings.Join(picked, ", "), nil
+	case FieldBool:
+		switch strings.ToLower(value) {
+		case "y", "yes", "true", "1":
+			return "yes", nil
+		case "n", "no", "false", "0":
+			return "no", nil
+		}
+		return "", fmt.Errorf("answer %q is not yes or no", value)
+	case FieldRating:
+		rating, err := strconv.Atoi(value)
+		if err != nil || rating < ratingMin || rating > ratingMax {
+			return "", fmt.Errorf("rating %q must be a number from %d to %d", value, ratingMin, ratingMax)
+		}
+		return strconv.Itoa(rating), nil
+	case FieldNotes:
+		var picked []string
+		for _, entry := range splitResponseList(value) {
+			path, err := matchQueueItem(entry, queue)
+			if err != nil {
+				return "", err
+			}
+			picked = appendUnique(picked, path)
+		}
+		return strings.Join(picked, ", "), nil
+	}
+	return value, nil
+}
+
+// NormalizeResponses validates every manifest field against the provided
+// responses and returns the canonical map. The error names the first invalid
+// step and its index.
+func NormalizeResponses(
+	manifest templater.TemplateManifest,
+	responses map[string]string,
+	queue []ResurfaceItem,
+) (map[string]string, int, error) {
+	normalized := make(map[string]string, len(responses))
+	for key, value := range responses {
+		normalized[key] = value
+	}
+	for idx, field := range manifest.Fields {
+		value, err := NormalizeResponse(field, responses[field.Key], queue)
+		if err != nil {
+			return normalized, idx, fmt.Errorf("%s: %w", fieldTitle(field), err)
+		}
+		normalized[field.Key] = value
+	}
+	return normalized, -1, nil
+}
+
+// TypedResponse converts a canonical response into the value written to the
+// review log front matter. Empty responses yield nil.
+func TypedResponse(field templater.TemplateField, value, vault string) any {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	switch FieldKind(field) {
+	case FieldMultiSelect:
+		return splitResponseList(value)
+	case FieldNotes:
+		paths := splitResponseList(value)
+		for i, path := range paths {
+			paths[i] = vaultRelative(vault, path)
+		}
+		return paths
+	case FieldBool:
+		return value == "yes"
+	case FieldRating:
+		if rating, err := strconv.Atoi(value); err == nil {
+			return rating
+		}
+	}
+	return value
+}
+
+// displayResponse renders a canonical response for the Markdown body of the
+// review log.
+func displayResponse(field templater.TemplateField, value, vault string) string {
+	if FieldKind(field) != FieldNotes {
+		return value
+	}
+	paths := splitResponseList(value)
+	for i, path := range paths {
+		paths[i] = vaultRelative(vault, path)
+	}
+	return strings.Join(paths, ", ")
+}
+
+func matchOption(value string, options []string) (string, error) {
+	if len(options) == 0 {
+		return value, nil
+	}
+	if idx, err := strconv.Atoi(value); err == nil {
+		if idx >= 1 && idx <= len(options) {
+			return options[idx-1], nil
+		}
+		if !containsFold(options, value) {
+			return "", fmt.Errorf("option %d is out of range (1-%d)", idx, len(options))
+		}
+	}
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), value) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("value %q is not one of the allowed options: %s", value, strings.Join(options, ", "))
+}
+
+func matchQueueItem(value string, queue []ResurfaceItem) (string, error) {
+	if idx, err := strconv.Atoi(value); err == nil {
+		if idx < 1 || idx > len(queue) {
+			return "", fmt.Errorf("queue item %d is out of range (1-%d)", idx, len(queue))
+		}
+		return queue[idx-1].Path, nil
+	}
+	slashed := filepath.ToSlash(value)
+	for _, item := range queue {
+		path := filepath.ToSlash(item.Path)
+		if path == slashed || strings.HasSuffix(path, "/"+slashed) {
+			return item.Path, nil
+		}
+	}
+	return "", fmt.Errorf("note %q is not in the resurfacing queue", value)
+}
+
+func splitResponseList(value string) []string {
+	parts := strings.Split(value, ",")
+	entries := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			entries = append(entries, trimmed)
+		}
+	}
+	return entries
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(strings.TrimSpace(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func vaultRelative(vault, path string) string {
+	if vault == "" || !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	if rel, err := filepath.Rel(vault, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+func fieldTitle(field templater.TemplateField) string {
+	if title := strings.TrimSpace(field.Label); title != "" {
+		return title
+	}
+	return humanizeKey(field.Key)
+}
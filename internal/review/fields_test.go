@@ -0,0 +1,161 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+func TestNormalizeResponseByKind(t *testing.T) {
+	t.Parallel()
+
+	queue := []ResurfaceItem{
+		{Path: "/vault/atoms/a.md"},
+		{Path: "/vault/atoms/b.md"},
+	}
+
+	cases := []struct {
+		name    string
+		field   templater.TemplateField
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "text", field: templater.TemplateField{Key: "t"}, input: "  free form ", want: "free form"},
+		{name: "required", field: templater.TemplateField{Key: "t", Required: true}, input: "", wantErr: true},
+		{name: "default", field: templater.TemplateField{Key: "t", Default: "fallback"}, input: "", want: "fallback"},
+		{name: "select by number", field: templater.TemplateField{Key: "s", Type: "select", Options: []string{"low", "high"}}, input: "2", want: "high"},
+		{name: "select by name", field: templater.TemplateField{Key: "s", Options: []string{"low", "high"}}, input: "LOW", want: "low"},
+		{name: "select invalid", field: templater.TemplateField{Key: "s", Options: []string{"low", "high"}}, input: "medium", wantErr: true},
+		{name: "multi select", field: templater.TemplateField{Key: "m", Options: []string{"a", "b", "c"}, Multi: true}, input: "3, a, c", want: "c, a"},
+		{name: "bool", field: templater.TemplateField{Key: "b", Type: "yesno"}, input: "Y", want: "yes"},
+		{name: "bool invalid", field: templater.TemplateField{Key: "b", Type: "bool"}, input: "maybe", wantErr: true},
+		{name: "rating", field: templater.TemplateField{Key: "r", Type: "rating"}, input: "4", want: "4"},
+		{name: "rating range", field: templater.TemplateField{Key: "r", Type: "rating"}, input: "6", wantErr: true},
+		{name: "notes", field: templater.TemplateField{Key: "n", Type: "notes"}, input: "2, atoms/a.md", want: "/vault/atoms/b.md, /vault/atoms/a.md"},
+		{name: "notes range", field: templater.TemplateField{Key: "n", Type: "notes"}, input: "3", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, err := NormalizeResponse(tc.field, tc.input, queue)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %q", tc.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: NormalizeResponse = %q, %v; want %q", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestRunChecklistRepromptsInvalidAnswers(t *testing.T) {
+	t.Parallel()
+
+	manifest := templater.TemplateManifest{
+		Name: "review-daily",
+		Fields: []templater.TemplateField{
+			{Key: "energy", Label: "Energy", Type: "rating"},
+			{Key: "notes", Label: "Notes", Type: "textarea"},
+			{Key: "shipped", Label: "Shipped", Type: "bool"},
+		},
+	}
+
+	input := strings.NewReader("9\n3\nfirst line\nsecond line\n\nno\n")
+	var output strings.Builder
+	responses, err := RunChecklist(manifest, nil, input, &output)
+	if err != nil {
+		t.Fatalf("RunChecklist returned error: %v", err)
+	}
+
+	want := map[string]string{"energy": "3", "notes": "first line\nsecond line", "shipped": "no"}
+	if !reflect.DeepEqual(responses, want) {
+		t.Fatalf("unexpected responses: %#v", responses)
+	}
+	if !strings.Contains(output.String(), "must be a number from 1 to 5") {
+		t.Fatalf("expected validation message, got %q", output.String())
+	}
+
+	required := templater.TemplateManifest{Fields: []templater.TemplateField{{Key: "x", Label: "X", Required: true}}}
+	if _, err := RunChecklist(required, nil, strings.NewReader(""), nil); err == nil {
+		t.Fatalf("expected required field to fail once input is exhausted")
+	}
+}
+
+func TestWriteMarkdownLogRecordsTypedFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	dir := filepath.Join(vault, "reviews")
+	note := filepath.Join(vault, "atoms", "a.md")
+	manifest := templater.TemplateManifest{
+		Name: "review-daily",
+		Fields: []templater.TemplateField{
+			{Key: "energy", Type: "rating"},
+			{Key: "shipped", Type: "bool"},
+			{Key: "areas", Options: []string{"work", "home"}, Multi: true},
+			{Key: "picks", Type: "notes"},
+			{Key: "skipped"},
+		},
+	}
+	responses := map[string]string{"energy": "4", "shipped": "yes", "areas": "work, home", "picks": note}
+	ts := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	path, err := WriteMarkdownLog(dir, manifest, responses, nil, ts, vault)
+	if err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+	if _, err := WriteMarkdownLog(dir, manifest, map[string]string{"energy": "2"}, nil, ts.Add(time.Hour), vault); err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	text := string(content)
+	if !strings.Contains(text, "- **Picks:** atoms/a.md") {
+		t.Fatalf("expected vault-relative note pick in body:\n%s", text)
+	}
+
+	parts := strings.SplitN(text, "---\n", 3)
+	if len(parts) != 3 || parts[0] != "" {
+		t.Fatalf("expected log to start with front matter:\n%s", text)
+	}
+	var meta struct {
+		Ritual   string `yaml:"ritual"`
+		Sessions []struct {
+			Timestamp string         `yaml:"timestamp"`
+			Responses map[string]any `yaml:"responses"`
+		} `yaml:"sessions"`
+	}
+	if err := yaml.Unmarshal([]byte(parts[1]), &meta); err != nil {
+		t.Fatalf("failed to parse front matter: %v", err)
+	}
+	if meta.Ritual != "review-daily" || len(meta.Sessions) != 2 {
+		t.Fatalf("unexpected front matter: %+v", meta)
+	}
+	first := meta.Sessions[0].Responses
+	if first["energy"] != 4 || first["shipped"] != true || first["skipped"] != nil {
+		t.Fatalf("expected typed scalar responses, got %#v", first)
+	}
+	if !reflect.DeepEqual(first["areas"], []any{"work", "home"}) || !reflect.DeepEqual(first["picks"], []any{"atoms/a.md"}) {
+		t.Fatalf("expected list responses, got %#v", first)
+	}
+	if meta.Sessions[1].Responses["energy"] != 2 {
+		t.Fatalf("expected appended session in front matter, got %#v", meta.Sessions[1])
+	}
+
+	sessions, err := LoadSessions(dir)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("expected sessions to parse beneath front matter, got %d (%v)", len(sessions), err)
+	}
+}
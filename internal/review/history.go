@@ -0,0 +1,440 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	sessionHeadingPattern = regexp.MustCompile(`^##\s+(.+?)\s+—\s+(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z)`)
+	responseLinePattern   = regexp.MustCompile(`^- \*\*(.+?):\*\*\s*(.*)$`)
+	queueLinePattern      = regexp.MustCompile(`^- (.+?) — last touched \d{4}-\d{2}-\d{2}`)
+)
+
+const (
+	noResponseMarker = "_(no response)_"
+	pendingDirName   = "review-pending"
+)
+
+// Session is a single review ritual parsed back out of a Markdown log.
+type Session struct {
+	Ritual    string
+	Path      string
+	Timestamp time.Time
+	Fields    []SessionField
+	// Notes lists the vault-relative paths of the resurfaced notes.
+	Notes []string
+}
+
+// SessionField is a checklist step recorded in a session log.
+type SessionField struct {
+	Label    string
+	Response string
+	Skipped  bool
+}
+
+// Answered reports how many checklist steps received a response.
+func (s Session) Answered() int {
+	count := 0
+	for _, field := range s.Fields {
+		if !field.Skipped {
+			count++
+		}
+	}
+	return count
+}
+
+// HistoryOptions tunes how sessions are summarized.
+type HistoryOptions struct {
+	Now time.Time
+	// Weeks is the number of trailing weeks reported per ritual.
+	Weeks int
+	// Top caps the most/least reviewed and never-resurfaced lists.
+	Top int
+	// Notes lists vault-relative note paths used to find notes that were
+	// never resurfaced. Leave empty to skip that section.
+	Notes []string
+}
+
+// HistorySummary aggregates review sessions across rituals.
+type HistorySummary struct {
+	Sessions int
+	First    time.Time
+	Last     time.Time
+	// Weeks holds the start (Monday, UTC) of each reported week, oldest first.
+	Weeks           []time.Time
+	Rituals         []RitualHistory
+	MostReviewed    []NoteReviewCount
+	LeastReviewed   []NoteReviewCount
+	NeverResurfaced []string
+	// NeverResurfacedTotal counts every never-resurfaced note, including those
+	// trimmed from NeverResurfaced by the Top option.
+	NeverResurfacedTotal int
+}
+
+// RitualHistory summarizes the sessions of a single ritual.
+type RitualHistory struct {
+	Name     string
+	Sessions int
+	// PerWeek is aligned with HistorySummary.Weeks.
+	PerWeek      []int
+	LastSession  time.Time
+	AverageSteps float64
+	AverageNotes float64
+	Skipped      []FieldSkipCount
+}
+
+// FieldSkipCount records how often a checklist step was left empty.
+type FieldSkipCount struct {
+	Label string
+	Count int
+}
+
+// NoteReviewCount records how often a note appeared in a resurfacing queue.
+type NoteReviewCount struct {
+	Path  string
+	Count int
+	Last  time.Time
+}
+
+// LoadSessions parses every session recorded in the Markdown logs stored in
+// dir. Sessions are returned oldest first.
+func LoadSessions(dir string) ([]Session, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var sessions []Session
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, ParseSessions(path, string(content))...)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Timestamp.Before(sessions[j].Timestamp)
+	})
+	return sessions, nil
+}
+
+// ParseSessions extracts the sessions written by WriteMarkdownLog from the
+// provided log content. Sections other than the checklist responses and the
+// resurfacing queue are ignored.
+func ParseSessions(path, content string) []Session {
+	const (
+		sectionNone = iota
+		sectionResponses
+		sectionQueue
+	)
+
+	var (
+		sessions []Session
+		current  *Session
+		section  = sectionNone
+	)
+
+	for _, line := range normalizeLogLines(content) {
+		trimmed := strings.TrimSpace(line)
+
+		if match := sessionHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			ts, err := time.Parse(time.RFC3339, match[2])
+			if err != nil {
+				current = nil
+				continue
+			}
+			sessions = append(sessions, Session{Ritual: match[1], Path: path, Timestamp: ts.UTC()})
+			current = &sessions[len(sessions)-1]
+			section = sectionNone
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			switch strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))) {
+			case "checklist responses":
+				section = sectionResponses
+			case "resurfacing queue":
+				section = sectionQueue
+			default:
+				section = sectionNone
+			}
+			continue
+		}
+
+		switch section {
+		case sectionResponses:
+			if match := responseLinePattern.FindStringSubmatch(trimmed); match != nil {
+				response := strings.TrimSpace(match[2])
+				current.Fields = append(current.Fields, SessionField{
+					Label:    match[1],
+					Response: response,
+					Skipped:  response == noResponseMarker,
+				})
+				continue
+			}
+			// Multi-line responses are written on indented lines after an
+			// empty "- **Label:**" entry.
+			if n := len(current.Fields); n > 0 && trimmed != "" && strings.HasPrefix(line, "  ") {
+				field := &current.Fields[n-1]
+				if field.Response == "" {
+					field.Response = trimmed
+				} else {
+					field.Response += "\n" + trimmed
+				}
+			}
+		case sectionQueue:
+			if match := queueLinePattern.FindStringSubmatch(trimmed); match != nil {
+				current.Notes = append(current.Notes, match[1])
+			}
+		}
+	}
+	return sessions
+}
+
+// SummarizeHistory aggregates sessions into per-ritual and per-note statistics.
+func SummarizeHistory(sessions []Session, opts HistoryOptions) HistorySummary {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	weeks := opts.Weeks
+	if weeks <= 0 {
+		weeks = 8
+	}
+	top := opts.Top
+	if top <= 0 {
+		top = 5
+	}
+
+	summary := HistorySummary{Sessions: len(sessions)}
+	current := weekStart(now)
+	for i := weeks - 1; i >= 0; i-- {
+		summary.Weeks = append(summary.Weeks, current.AddDate(0, 0, -7*i))
+	}
+
+	rituals := make(map[string]*RitualHistory)
+	skipped := make(map[string]map[string]int)
+	notes := make(map[string]*NoteReviewCount)
+
+	for _, session := range sessions {
+		if summary.First.IsZero() || session.Timestamp.Before(summary.First) {
+			summary.First = session.Timestamp
+		}
+		if session.Timestamp.After(summary.Last) {
+			summary.Last = session.Timestamp
+		}
+
+		ritual, ok := rituals[session.Ritual]
+		if !ok {
+			ritual = &RitualHistory{Name: session.Ritual, PerWeek: make([]int, weeks)}
+			rituals[session.Ritual] = ritual
+			skipped[session.Ritual] = make(map[string]int)
+		}
+		ritual.Sessions++
+		ritual.AverageSteps += float64(session.Answered())
+		ritual.AverageNotes += float64(len(session.Notes))
+		if session.Timestamp.After(ritual.LastSession) {
+			ritual.LastSession = session.Timestamp
+		}
+		start := weekStart(session.Timestamp)
+		for i, week := range summary.Weeks {
+			if week.Equal(start) {
+				ritual.PerWeek[i]++
+				break
+			}
+		}
+		for _, field := range session.Fields {
+			if field.Skipped {
+				skipped[session.Ritual][field.Label]++
+			}
+		}
+
+		for _, note := range session.Notes {
+			count, ok := notes[note]
+			if !ok {
+				count = &NoteReviewCount{Path: note}
+				notes[note] = count
+			}
+			count.Count++
+			if session.Timestamp.After(count.Last) {
+				count.Last = session.Timestamp
+			}
+		}
+	}
+
+	for name, ritual := range rituals {
+		ritual.AverageSteps /= float64(ritual.Sessions)
+		ritual.AverageNotes /= float64(ritual.Sessions)
+		for label, count := range skipped[name] {
+			ritual.Skipped = append(ritual.Skipped, FieldSkipCount{Label: label, Count: count})
+		}
+		sort.Slice(ritual.Skipped, func(i, j int) bool {
+			if ritual.Skipped[i].Count != ritual.Skipped[j].Count {
+				return ritual.Skipped[i].Count > ritual.Skipped[j].Count
+			}
+			return ritual.Skipped[i].Label < ritual.Skipped[j].Label
+		})
+		summary.Rituals = append(summary.Rituals, *ritual)
+	}
+	sort.Slice(summary.Rituals, func(i, j int) bool {
+		return summary.Rituals[i].Name < summary.Rituals[j].Name
+	})
+
+	counts := make([]NoteReviewCount, 0, len(notes))
+	for _, count := range notes {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Path < counts[j].Path
+	})
+	summary.MostReviewed = append([]NoteReviewCount(nil), counts[:min(top, len(counts))]...)
+	for i := len(counts) - 1; i >= 0 && len(summary.LeastReviewed) < top; i-- {
+		summary.LeastReviewed = append(summary.LeastReviewed, counts[i])
+	}
+
+	for _, note := range opts.Notes {
+		note = filepath.ToSlash(note)
+		if _, ok := notes[note]; ok {
+			continue
+		}
+		summary.NeverResurfacedTotal++
+		if len(summary.NeverResurfaced) < top {
+			summary.NeverResurfaced = append(summary.NeverResurfaced, note)
+		}
+	}
+	return summary
+}
+
+// RenderHistoryMarkdown formats the summary as Markdown suitable for the
+// terminal or for inclusion in a review log.
+func RenderHistoryMarkdown(summary HistorySummary) string {
+	var builder strings.Builder
+
+	builder.WriteString("### Review history\n\n")
+	if summary.Sessions == 0 {
+		builder.WriteString("- _No review sessions logged yet._\n")
+		return builder.String()
+	}
+	fmt.Fprintf(
+		&builder,
+		"%d sessions between %s and %s.\n\n",
+		summary.Sessions,
+		summary.First.Format("2006-01-02"),
+		summary.Last.Format("2006-01-02"),
+	)
+
+	builder.WriteString("#### Sessions per week\n\n")
+	header := []string{"Ritual"}
+	for _, week := range summary.Weeks {
+		header = append(header, week.Format("01-02"))
+	}
+	fmt.Fprintf(&builder, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(&builder, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, ritual := range summary.Rituals {
+		row := []string{ritual.Name}
+		for _, count := range ritual.PerWeek {
+			row = append(row, fmt.Sprintf("%d", count))
+		}
+		fmt.Fprintf(&builder, "| %s |\n", strings.Join(row, " | "))
+	}
+
+	builder.WriteString("\n#### Rituals\n\n")
+	for _, ritual := range summary.Rituals {
+		fmt.Fprintf(
+			&builder,
+			"- **%s:** %d sessions, last %s, %.1f steps answered and %.1f notes resurfaced on average\n",
+			ritual.Name,
+			ritual.Sessions,
+			ritual.LastSession.Format("2006-01-02"),
+			ritual.AverageSteps,
+			ritual.AverageNotes,
+		)
+		if len(ritual.Skipped) > 0 {
+			parts := make([]string, 0, len(ritual.Skipped))
+			for _, skip := range ritual.Skipped {
+				parts = append(parts, fmt.Sprintf("%s (%d)", skip.Label, skip.Count))
+			}
+			fmt.Fprintf(&builder, "  - Skipped: %s\n", strings.Join(parts, ", "))
+		}
+	}
+
+	writeCounts := func(title string, counts []NoteReviewCount) {
+		fmt.Fprintf(&builder, "\n#### %s\n\n", title)
+		if len(counts) == 0 {
+			builder.WriteString("- _None._\n")
+			return
+		}
+		for _, count := range counts {
+			fmt.Fprintf(&builder, "- %s — %d times, last %s\n", count.Path, count.Count, count.Last.Format("2006-01-02"))
+		}
+	}
+	writeCounts("Most reviewed", summary.MostReviewed)
+	writeCounts("Least reviewed", summary.LeastReviewed)
+
+	if summary.NeverResurfacedTotal > 0 {
+		fmt.Fprintf(&builder, "\n#### Never resurfaced (%d)\n\n", summary.NeverResurfacedTotal)
+		for _, note := range summary.NeverResurfaced {
+			fmt.Fprintf(&builder, "- %s\n", note)
+		}
+		if extra := summary.NeverResurfacedTotal - len(summary.NeverResurfaced); extra > 0 {
+			fmt.Fprintf(&builder, "- _…and %d more._\n", extra)
+		}
+	}
+	return builder.String()
+}
+
+// QueueLogAppendix stores content that WriteMarkdownLog appends to the next
+// log written for the named ritual. Queuing again replaces pending content.
+func QueueLogAppendix(vault, ritual, content string) (string, error) {
+	path := pendingAppendixPath(vault, ritual)
+	if path == "" {
+		return "", fmt.Errorf("vault directory is not configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(strings.TrimRight(content, "\n")+"\n"), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func pendingAppendixPath(vault, ritual string) string {
+	if strings.TrimSpace(vault) == "" {
+		return ""
+	}
+	slug := strings.Trim(filenameSanitizer.ReplaceAllString(strings.ToLower(ritual), "-"), "-")
+	if slug == "" {
+		slug = "review"
+	}
+	return filepath.Join(vault, ".an", pendingDirName, slug+".md")
+}
+
+func weekStart(ts time.Time) time.Time {
+	ts = ts.UTC()
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,133 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+func TestLoadSessionsAndSummarizeHistory(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	dir := filepath.Join(vault, "reviews")
+	manifest := templater.TemplateManifest{
+		Name: "review-daily",
+		Fields: []templater.TemplateField{
+			{Key: "inbox", Label: "Clear inbox"},
+			{Key: "plan", Label: "Plan focus"},
+		},
+	}
+
+	first := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	queue := []ResurfaceItem{
+		{Path: filepath.Join(vault, "atoms", "a.md"), ModifiedAt: first, Bucket: "weekly"},
+		{Path: filepath.Join(vault, "atoms", "b.md"), ModifiedAt: first, Bucket: "daily"},
+	}
+
+	if _, err := WriteMarkdownLog(dir, manifest, map[string]string{"inbox": "done"}, queue, first, vault); err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+	responses := map[string]string{"inbox": "done", "plan": "line one\nline two"}
+	if _, err := WriteMarkdownLog(dir, manifest, responses, queue[:1], second, vault); err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+
+	sessions, err := LoadSessions(dir)
+	if err != nil {
+		t.Fatalf("LoadSessions returned error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d: %+v", len(sessions), sessions)
+	}
+	if !sessions[0].Timestamp.Equal(first) || sessions[0].Ritual != "review-daily" {
+		t.Fatalf("unexpected first session: %+v", sessions[0])
+	}
+	if !sessions[0].Fields[1].Skipped || sessions[0].Answered() != 1 {
+		t.Fatalf("expected plan step to be skipped in first session: %+v", sessions[0].Fields)
+	}
+	if got := sessions[1].Fields[1].Response; got != "line one\nline two" {
+		t.Fatalf("expected multi-line response to be joined, got %q", got)
+	}
+
+	summary := SummarizeHistory(sessions, HistoryOptions{
+		Now:   time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC),
+		Weeks: 2,
+		Notes: []string{"atoms/a.md", "atoms/b.md", "atoms/c.md"},
+	})
+	if summary.Sessions != 2 || len(summary.Rituals) != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	ritual := summary.Rituals[0]
+	if ritual.PerWeek[0] != 1 || ritual.PerWeek[1] != 1 {
+		t.Fatalf("expected one session per week, got %v", ritual.PerWeek)
+	}
+	if ritual.AverageSteps != 1.5 || ritual.AverageNotes != 1.5 {
+		t.Fatalf("unexpected averages: %+v", ritual)
+	}
+	if len(ritual.Skipped) != 1 || ritual.Skipped[0].Label != "Plan focus" {
+		t.Fatalf("unexpected skipped steps: %+v", ritual.Skipped)
+	}
+	if summary.MostReviewed[0].Path != "atoms/a.md" || summary.MostReviewed[0].Count != 2 {
+		t.Fatalf("unexpected most reviewed: %+v", summary.MostReviewed)
+	}
+	if summary.LeastReviewed[0].Path != "atoms/b.md" {
+		t.Fatalf("unexpected least reviewed: %+v", summary.LeastReviewed)
+	}
+	if len(summary.NeverResurfaced) != 1 || summary.NeverResurfaced[0] != "atoms/c.md" {
+		t.Fatalf("unexpected never resurfaced: %+v", summary.NeverResurfaced)
+	}
+
+	report := RenderHistoryMarkdown(summary)
+	if !strings.Contains(report, "Skipped: Plan focus (1)") || !strings.Contains(report, "#### Never resurfaced (1)") {
+		t.Fatalf("unexpected report:\n%s", report)
+	}
+}
+
+func TestQueueLogAppendixIsWrittenOnce(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	dir := filepath.Join(vault, "reviews")
+	manifest := templater.TemplateManifest{Name: "review-weekly"}
+
+	pending, err := QueueLogAppendix(vault, "review-weekly", "### Review history\n\n- summary line\n")
+	if err != nil {
+		t.Fatalf("QueueLogAppendix returned error: %v", err)
+	}
+
+	daily := templater.TemplateManifest{Name: "review-daily"}
+	dailyPath, err := WriteMarkdownLog(dir, daily, nil, nil, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), vault)
+	if err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+	if content, _ := os.ReadFile(dailyPath); strings.Contains(string(content), "summary line") {
+		t.Fatalf("expected appendix to wait for the weekly review")
+	}
+
+	ts := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	path, err := WriteMarkdownLog(dir, manifest, nil, nil, ts, vault)
+	if err != nil {
+		t.Fatalf("WriteMarkdownLog returned error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if !strings.Contains(string(content), "- summary line") {
+		t.Fatalf("expected appendix in weekly log:\n%s", content)
+	}
+	if _, err := os.Stat(pending); !os.IsNotExist(err) {
+		t.Fatalf("expected pending appendix to be removed, stat err: %v", err)
+	}
+
+	sessions := ParseSessions(path, string(content))
+	if len(sessions) != 1 || len(sessions[0].Notes) != 0 {
+		t.Fatalf("expected appendix to be ignored when parsing sessions: %+v", sessions)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/Paintersrp/an/internal/templater"
 )
 
@@ -86,9 +88,29 @@ func WriteMarkdownLog(
 	filename := buildReviewFilename(manifest, ts)
 	path := filepath.Join(dir, filename+".md")
 	content := renderReviewLogContent(manifest, responses, queue, ts, vault)
+
+	// Summaries queued with QueueLogAppendix ride along with the next log for
+	// the same ritual and are cleared once written.
+	pending := pendingAppendixPath(vault, manifestSlug(manifest))
+	if pending != "" {
+		if appendix, err := os.ReadFile(pending); err == nil && strings.TrimSpace(string(appendix)) != "" {
+			content = strings.TrimRight(content, "\n") + "\n\n" + strings.TrimRight(string(appendix), "\n") + "\n"
+		} else {
+			pending = ""
+		}
+	}
+
 	if err := appendReviewLog(path, content); err != nil {
 		return "", err
 	}
+	if err := recordLogFrontMatter(path, manifest, responses, ts, vault); err != nil {
+		return path, err
+	}
+	if pending != "" {
+		if err := os.Remove(pending); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return path, err
+		}
+	}
 	return path, nil
 }
 
@@ -140,7 +162,7 @@ func renderReviewLogContent(
 			if label == "" {
 				label = humanizeKey(field.Key)
 			}
-			response := strings.TrimSpace(responses[field.Key])
+			response := strings.TrimSpace(displayResponse(field, responses[field.Key], vault))
 			if response == "" {
 				fmt.Fprintf(&builder, "- **%s:** _(no response)_\n", label)
 				continue
@@ -211,6 +233,93 @@ func appendReviewLog(path, content string) error {
 	return err
 }
 
+// recordLogFrontMatter adds the session's typed responses to the log's front
+// matter. Each log keeps a `sessions` list so appended sessions stay
+// queryable alongside the first one.
+func recordLogFrontMatter(
+	path string,
+	manifest templater.TemplateManifest,
+	responses map[string]string,
+	ts time.Time,
+	vault string,
+) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var doc yaml.Node
+	body := content
+	if strings.HasPrefix(content, "---\n") {
+		if end := strings.Index(content[4:], "\n---\n"); end >= 0 {
+			if err := yaml.Unmarshal([]byte(content[4:4+end]), &doc); err != nil {
+				return fmt.Errorf("parse review log front matter: %w", err)
+			}
+			body = strings.TrimLeft(content[4+end+5:], "\n")
+		}
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		root = doc.Content[0]
+	}
+	if mappingValue(root, "ritual") == nil {
+		setMappingValue(root, "ritual", scalarNode(manifestSlug(manifest)))
+	}
+	sessions := mappingValue(root, "sessions")
+	if sessions == nil || sessions.Kind != yaml.SequenceNode {
+		sessions = &yaml.Node{Kind: yaml.SequenceNode}
+		setMappingValue(root, "sessions", sessions)
+	}
+
+	answers := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range manifest.Fields {
+		value := TypedResponse(field, responses[field.Key], vault)
+		node := &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return err
+		}
+		answers.Content = append(answers.Content, scalarNode(field.Key), node)
+	}
+	session := &yaml.Node{Kind: yaml.MappingNode}
+	session.Content = append(
+		session.Content,
+		scalarNode("timestamp"), scalarNode(ts.UTC().Format(time.RFC3339)),
+		scalarNode("responses"), answers,
+	)
+	sessions.Content = append(sessions.Content, session)
+
+	encoded, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte("---\n"+string(encoded)+"---\n"+body), 0o644)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
 // ListReviewLogs returns the metadata for review logs associated with the provided
 // manifest and mode key. Results are sorted by the embedded timestamp in the log
 // content when available (falling back to the file modification time) in
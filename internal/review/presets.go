@@ -0,0 +1,117 @@
+package review
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/search"
+	"github.com/Paintersrp/an/internal/timeutil"
+)
+
+// QueuePreset is a named resurfacing queue resolved from the workspace
+// review configuration.
+type QueuePreset struct {
+	Name        string
+	Description string
+	Buckets     []Bucket
+	Tags        []string
+	Metadata    map[string][]string
+	MinimumAge  time.Duration
+	Limit       int
+	// Template names the checklist template linked to the queue. It is empty
+	// when the preset does not override the review mode template.
+	Template string
+}
+
+// LoadQueuePreset resolves the named queue preset from the review config.
+func LoadQueuePreset(cfg config.ReviewConfig, name string) (QueuePreset, error) {
+	name = strings.TrimSpace(name)
+	raw, ok := cfg.Queues[name]
+	if !ok {
+		available := cfg.QueueNames()
+		if len(available) == 0 {
+			return QueuePreset{}, fmt.Errorf("unknown review queue %q: no queues are configured under review.queues", name)
+		}
+		return QueuePreset{}, fmt.Errorf("unknown review queue %q (available: %s)", name, strings.Join(available, ", "))
+	}
+
+	preset := QueuePreset{
+		Name:        name,
+		Description: strings.TrimSpace(raw.Description),
+		Tags:        append([]string(nil), raw.Tags...),
+		Metadata:    cloneMetadata(raw.Metadata),
+		Limit:       raw.Limit,
+		Template:    strings.TrimSpace(raw.Template),
+	}
+
+	if strings.TrimSpace(raw.MinAge) != "" {
+		age, err := timeutil.ParseAge(raw.MinAge)
+		if err != nil {
+			return QueuePreset{}, fmt.Errorf("review queue %q: min_age: %w", name, err)
+		}
+		preset.MinimumAge = age
+	}
+
+	for i, bucket := range raw.Buckets {
+		after, err := timeutil.ParseAge(bucket.After)
+		if err != nil {
+			return QueuePreset{}, fmt.Errorf("review queue %q: bucket %d: %w", name, i+1, err)
+		}
+		label := strings.TrimSpace(bucket.Name)
+		if label == "" {
+			label = strings.TrimSpace(bucket.After)
+		}
+		preset.Buckets = append(preset.Buckets, Bucket{Name: label, After: after})
+	}
+	sort.SliceStable(preset.Buckets, func(i, j int) bool {
+		return preset.Buckets[i].After < preset.Buckets[j].After
+	})
+
+	return preset, nil
+}
+
+// LoadQueuePresets resolves every configured queue preset in name order.
+func LoadQueuePresets(cfg config.ReviewConfig) ([]QueuePreset, error) {
+	names := cfg.QueueNames()
+	presets := make([]QueuePreset, 0, len(names))
+	for _, name := range names {
+		preset, err := LoadQueuePreset(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	return presets, nil
+}
+
+// Apply layers the preset onto the provided options. Preset tags and metadata
+// are added to the query, while buckets, minimum age, and limit replace the
+// option values when the preset defines them.
+func (p QueuePreset) Apply(opts ResurfaceOptions) ResurfaceOptions {
+	if len(p.Buckets) > 0 {
+		opts.Buckets = append([]Bucket(nil), p.Buckets...)
+	}
+	if p.MinimumAge > 0 {
+		opts.MinimumAge = p.MinimumAge
+	}
+	if p.Limit > 0 {
+		opts.Limit = p.Limit
+	}
+
+	query := search.Query{
+		Term:     opts.Query.Term,
+		Tags:     append(append([]string(nil), opts.Query.Tags...), p.Tags...),
+		Metadata: cloneMetadata(opts.Query.Metadata),
+	}
+	if len(p.Metadata) > 0 && query.Metadata == nil {
+		query.Metadata = make(map[string][]string, len(p.Metadata))
+	}
+	for key, values := range p.Metadata {
+		query.Metadata[key] = append(query.Metadata[key], values...)
+	}
+	opts.Query = query
+	return opts
+}
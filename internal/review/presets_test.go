@@ -0,0 +1,59 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/search"
+)
+
+func TestLoadQueuePresetAppliesToOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.ReviewConfig{
+		Queues: map[string]config.ReviewQueue{
+			"reading": {
+				Tags:     []string{"reading"},
+				Metadata: map[string][]string{"status": {"draft"}},
+				MinAge:   "2d",
+				Limit:    4,
+				Template: "review-weekly",
+				Buckets: []config.ReviewBucket{
+					{Name: "stale", After: "1w"},
+					{After: "2d"},
+				},
+			},
+		},
+	}
+
+	preset, err := LoadQueuePreset(cfg, "reading")
+	if err != nil {
+		t.Fatalf("LoadQueuePreset returned error: %v", err)
+	}
+	if len(preset.Buckets) != 2 || preset.Buckets[0].Name != "2d" || preset.Buckets[1].After != 7*24*time.Hour {
+		t.Fatalf("expected buckets sorted by age with fallback labels, got %+v", preset.Buckets)
+	}
+
+	opts := preset.Apply(ResurfaceOptions{
+		Limit:   12,
+		Buckets: DefaultBuckets(),
+		Query: search.Query{
+			Tags:     []string{"inbox"},
+			Metadata: map[string][]string{"status": {"active"}},
+		},
+	})
+	if opts.Limit != 4 || opts.MinimumAge != 48*time.Hour || len(opts.Buckets) != 2 {
+		t.Fatalf("unexpected applied options: %+v", opts)
+	}
+	if len(opts.Query.Tags) != 2 || opts.Query.Tags[1] != "reading" {
+		t.Fatalf("expected preset tags appended, got %v", opts.Query.Tags)
+	}
+	if got := opts.Query.Metadata["status"]; len(got) != 2 || got[1] != "draft" {
+		t.Fatalf("expected preset metadata merged, got %v", opts.Query.Metadata)
+	}
+
+	if _, err := LoadQueuePreset(cfg, "missing"); err == nil {
+		t.Fatalf("expected error for unknown queue")
+	}
+}
@@ -25,6 +25,10 @@ type ResurfaceOptions struct {
 	Limit      int
 	Buckets    []Bucket
 	Query      search.Query
+	// Schedule, when provided, switches the queue to spaced-repetition
+	// ordering. Scheduled notes surface once their due date passes regardless
+	// of edits, while unscheduled notes fall back to the age buckets.
+	Schedule *Schedule
 }
 
 // ResurfaceItem captures queue metadata for a resurfaced note.
@@ -36,8 +40,16 @@ type ResurfaceItem struct {
 	ModifiedAt time.Time
 	Age        time.Duration
 	Bucket     string
+	// Due records when the note became due for review. Scheduled notes use
+	// their stored due date; unscheduled notes become due once they reach the
+	// first bucket.
+	Due       time.Time
+	Scheduled bool
 }
 
+// scheduledBucket labels queue items surfaced by the review schedule.
+const scheduledBucket = "scheduled"
+
 // DefaultBuckets returns the default resurfacing cadence buckets.
 func DefaultBuckets() []Bucket {
 	return []Bucket{
@@ -50,7 +62,8 @@ func DefaultBuckets() []Bucket {
 }
 
 // BuildResurfaceQueue evaluates the index metadata and returns the resurfacing
-// queue ordered from stalest to freshest.
+// queue ordered from stalest to freshest. When a schedule is provided the queue
+// is ordered by due date instead.
 func BuildResurfaceQueue(idx *search.Index, opts ResurfaceOptions) []ResurfaceItem {
 	if idx == nil {
 		return nil
@@ -77,6 +90,24 @@ func BuildResurfaceQueue(idx *search.Index, opts ResurfaceOptions) []ResurfaceIt
 	items := make([]ResurfaceItem, 0, len(docs))
 	for _, doc := range docs {
 		age := now.Sub(doc.ModifiedAt)
+		if entry, ok := opts.Schedule.Entry(doc.Path); ok {
+			if entry.Due.After(now) {
+				continue
+			}
+			items = append(items, ResurfaceItem{
+				Path:       doc.Path,
+				Tags:       append([]string(nil), doc.Tags...),
+				Metadata:   cloneMetadata(doc.FrontMatter),
+				Links:      append([]string(nil), doc.Links...),
+				ModifiedAt: doc.ModifiedAt,
+				Age:        age,
+				Bucket:     scheduledBucket,
+				Due:        entry.Due,
+				Scheduled:  true,
+			})
+			continue
+		}
+
 		if opts.MinimumAge > 0 && age < opts.MinimumAge {
 			continue
 		}
@@ -94,9 +125,23 @@ func BuildResurfaceQueue(idx *search.Index, opts ResurfaceOptions) []ResurfaceIt
 			ModifiedAt: doc.ModifiedAt,
 			Age:        age,
 			Bucket:     bucket,
+			Due:        doc.ModifiedAt.Add(buckets[0].After),
 		})
 	}
 
+	if opts.Schedule != nil {
+		sort.Slice(items, func(i, j int) bool {
+			if !items[i].Due.Equal(items[j].Due) {
+				return items[i].Due.Before(items[j].Due)
+			}
+			return items[i].Path < items[j].Path
+		})
+		if opts.Limit > 0 && len(items) > opts.Limit {
+			items = items[:opts.Limit]
+		}
+		return items
+	}
+
 	sort.Slice(items, func(i, j int) bool {
 		if items[i].Bucket != items[j].Bucket {
 			return bucketRank(items[i].Bucket, buckets) > bucketRank(items[j].Bucket, buckets)
@@ -0,0 +1,307 @@
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	scheduleStateFile = "review-state.json"
+	defaultEase       = 2.5
+	minimumEase       = 1.3
+	scheduleDay       = 24 * time.Hour
+)
+
+// Grade captures how well a resurfaced note was recalled during review.
+type Grade int
+
+const (
+	GradeAgain Grade = iota
+	GradeHard
+	GradeGood
+	GradeEasy
+)
+
+// Grades lists the supported grades from weakest to strongest recall.
+func Grades() []Grade {
+	return []Grade{GradeAgain, GradeHard, GradeGood, GradeEasy}
+}
+
+func (g Grade) String() string {
+	switch g {
+	case GradeAgain:
+		return "again"
+	case GradeHard:
+		return "hard"
+	case GradeGood:
+		return "good"
+	case GradeEasy:
+		return "easy"
+	default:
+		return fmt.Sprintf("grade(%d)", int(g))
+	}
+}
+
+// ParseGrade converts a grade name (again, hard, good, easy) or its 1-4
+// shorthand into a Grade.
+func ParseGrade(value string) (Grade, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "again", "1":
+		return GradeAgain, nil
+	case "hard", "2":
+		return GradeHard, nil
+	case "good", "3":
+		return GradeGood, nil
+	case "easy", "4":
+		return GradeEasy, nil
+	default:
+		return 0, fmt.Errorf("unknown grade %q (expected again, hard, good, or easy)", value)
+	}
+}
+
+// ScheduleEntry stores the spaced-repetition state for a single note.
+type ScheduleEntry struct {
+	Ease         float64   `json:"ease"`
+	IntervalDays float64   `json:"interval_days"`
+	Repetitions  int       `json:"repetitions"`
+	Lapses       int       `json:"lapses"`
+	Due          time.Time `json:"due"`
+	LastReviewed time.Time `json:"last_reviewed"`
+	LastGrade    string    `json:"last_grade,omitempty"`
+}
+
+// Interval returns the entry interval as a duration.
+func (e ScheduleEntry) Interval() time.Duration {
+	return time.Duration(e.IntervalDays * float64(scheduleDay))
+}
+
+// Schedule persists per-note review scheduling state in a sidecar JSON file
+// under the vault's .an directory. Entries are keyed by vault-relative,
+// slash-separated paths so the file survives moving the vault.
+type Schedule struct {
+	vault   string
+	path    string
+	Entries map[string]ScheduleEntry
+}
+
+// SchedulePath returns the location of the schedule sidecar for the vault.
+func SchedulePath(vault string) string {
+	return filepath.Join(vault, ".an", scheduleStateFile)
+}
+
+// LoadSchedule reads the schedule sidecar for the vault. A missing file yields
+// an empty schedule.
+func LoadSchedule(vault string) (*Schedule, error) {
+	vault = strings.TrimSpace(vault)
+	if vault == "" {
+		return nil, fmt.Errorf("vault directory is not configured")
+	}
+
+	schedule := &Schedule{
+		vault:   filepath.Clean(vault),
+		path:    SchedulePath(vault),
+		Entries: make(map[string]ScheduleEntry),
+	}
+
+	data, err := os.ReadFile(schedule.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return schedule, nil
+		}
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return schedule, nil
+	}
+
+	var raw struct {
+		Notes map[string]ScheduleEntry `json:"notes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse review schedule %s: %w", schedule.path, err)
+	}
+	for key, entry := range raw.Notes {
+		schedule.Entries[key] = entry
+	}
+	return schedule, nil
+}
+
+// Save writes the schedule sidecar, replacing the previous file atomically.
+func (s *Schedule) Save() error {
+	if s == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	payload := struct {
+		Notes map[string]ScheduleEntry `json:"notes"`
+	}{Notes: s.Entries}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Entry returns the schedule entry for the provided note path.
+func (s *Schedule) Entry(path string) (ScheduleEntry, bool) {
+	if s == nil {
+		return ScheduleEntry{}, false
+	}
+	entry, ok := s.Entries[s.key(path)]
+	return entry, ok
+}
+
+// Grade applies an SM-2 style update to the note's schedule entry and returns
+// the updated entry. The caller is responsible for persisting the schedule.
+func (s *Schedule) Grade(path string, grade Grade, now time.Time) ScheduleEntry {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	key := s.key(path)
+	entry, ok := s.Entries[key]
+	if !ok {
+		entry = ScheduleEntry{Ease: defaultEase}
+	}
+	entry = nextEntry(entry, grade, now.UTC())
+	s.Entries[key] = entry
+	return entry
+}
+
+// Forget removes the schedule entry for the provided note path.
+func (s *Schedule) Forget(path string) {
+	if s == nil {
+		return
+	}
+	delete(s.Entries, s.key(path))
+}
+
+// RelinkCards moves schedule entries recorded for cards under an older key
+// to the card's current ScheduleKey, so review history survives moving or
+// renaming a note and the switch away from path-derived card IDs. An entry
+// is adopted when it uses the card's legacy ID, or when its ID matches the
+// card and no other current card claims its key. It reports whether any
+// entry moved; the caller persists the schedule.
+func (s *Schedule) RelinkCards(cards []Card) bool {
+	if s == nil {
+		return false
+	}
+	current := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		current[card.ScheduleKey()] = true
+	}
+
+	changed := false
+	for _, card := range cards {
+		key := card.ScheduleKey()
+		if _, ok := s.Entries[key]; ok {
+			continue
+		}
+		old := card.Path + "#" + legacyCardID(card)
+		if _, ok := s.Entries[old]; !ok || current[old] {
+			old = ""
+			for candidate := range s.Entries {
+				if current[candidate] || !strings.HasSuffix(candidate, "#"+card.ID) {
+					continue
+				}
+				if old != "" {
+					old = ""
+					break
+				}
+				old = candidate
+			}
+		}
+		if old == "" {
+			continue
+		}
+		s.Entries[key] = s.Entries[old]
+		delete(s.Entries, old)
+		changed = true
+	}
+	return changed
+}
+
+// Keys returns the scheduled note keys in sorted order.
+func (s *Schedule) Keys() []string {
+	if s == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Entries))
+	for key := range s.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Schedule) key(path string) string {
+	cleaned := filepath.Clean(path)
+	if filepath.IsAbs(cleaned) {
+		if rel, err := filepath.Rel(s.vault, cleaned); err == nil && !strings.HasPrefix(rel, "..") {
+			cleaned = rel
+		}
+	}
+	return filepath.ToSlash(cleaned)
+}
+
+func nextEntry(entry ScheduleEntry, grade Grade, now time.Time) ScheduleEntry {
+	if entry.Ease == 0 {
+		entry.Ease = defaultEase
+	}
+
+	switch grade {
+	case GradeAgain:
+		entry.Repetitions = 0
+		entry.Lapses++
+		entry.IntervalDays = 1
+		entry.Ease -= 0.2
+	case GradeHard:
+		entry.Repetitions++
+		entry.IntervalDays = math.Max(1, entry.IntervalDays*1.2)
+		entry.Ease -= 0.15
+	case GradeGood:
+		entry.Repetitions++
+		switch entry.Repetitions {
+		case 1:
+			entry.IntervalDays = 1
+		case 2:
+			entry.IntervalDays = 6
+		default:
+			entry.IntervalDays = math.Round(entry.IntervalDays * entry.Ease)
+		}
+	case GradeEasy:
+		entry.Repetitions++
+		switch entry.Repetitions {
+		case 1:
+			entry.IntervalDays = 4
+		default:
+			entry.IntervalDays = math.Round(math.Max(4, entry.IntervalDays) * entry.Ease * 1.3)
+		}
+		entry.Ease += 0.15
+	}
+
+	if entry.Ease < minimumEase {
+		entry.Ease = minimumEase
+	}
+	entry.Ease = math.Round(entry.Ease*100) / 100
+
+	entry.LastReviewed = now
+	entry.LastGrade = grade.String()
+	entry.Due = now.Add(entry.Interval())
+	return entry
+}
@@ -0,0 +1,118 @@
+package review
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/search"
+)
+
+func TestScheduleGradeProgression(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	schedule, err := LoadSchedule(vault)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	note := filepath.Join(vault, "atoms", "idea.md")
+
+	first := schedule.Grade(note, GradeGood, now)
+	if first.IntervalDays != 1 || !first.Due.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected first good entry: %+v", first)
+	}
+
+	second := schedule.Grade(note, GradeGood, now)
+	if second.IntervalDays != 6 {
+		t.Fatalf("expected second good interval of 6 days, got %+v", second)
+	}
+
+	third := schedule.Grade(note, GradeGood, now)
+	if third.IntervalDays != 15 {
+		t.Fatalf("expected third interval to apply ease, got %+v", third)
+	}
+
+	lapsed := schedule.Grade(note, GradeAgain, now)
+	if lapsed.IntervalDays != 1 || lapsed.Repetitions != 0 || lapsed.Lapses != 1 {
+		t.Fatalf("expected again to reset the entry, got %+v", lapsed)
+	}
+	if lapsed.Ease >= third.Ease {
+		t.Fatalf("expected again to lower ease, got %.2f (was %.2f)", lapsed.Ease, third.Ease)
+	}
+
+	if err := schedule.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	reloaded, err := LoadSchedule(vault)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+	entry, ok := reloaded.Entry(note)
+	if !ok {
+		t.Fatalf("expected entry to persist, keys: %v", reloaded.Keys())
+	}
+	if entry.LastGrade != "again" || !entry.Due.Equal(lapsed.Due) {
+		t.Fatalf("unexpected reloaded entry: %+v", entry)
+	}
+	if keys := reloaded.Keys(); len(keys) != 1 || keys[0] != "atoms/idea.md" {
+		t.Fatalf("expected vault-relative key, got %v", keys)
+	}
+}
+
+func TestBuildResurfaceQueueOrdersByDueDate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	stale := writeNote(t, dir, "stale.md", now.Add(-10*24*time.Hour))
+	edited := writeNote(t, dir, "edited.md", now.Add(-time.Hour))
+	notDue := writeNote(t, dir, "not-due.md", now.Add(-30*24*time.Hour))
+
+	idx := search.NewIndex(dir, search.Config{})
+	if err := idx.Build([]string{stale, edited, notDue}); err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	schedule, err := LoadSchedule(dir)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+	schedule.Entries["edited.md"] = ScheduleEntry{Ease: 2.5, IntervalDays: 20, Due: now.Add(-15 * 24 * time.Hour)}
+	schedule.Entries["not-due.md"] = ScheduleEntry{Ease: 2.5, IntervalDays: 6, Due: now.Add(2 * 24 * time.Hour)}
+
+	queue := BuildResurfaceQueue(idx, ResurfaceOptions{
+		Now:      now,
+		Buckets:  DefaultBuckets(),
+		Schedule: schedule,
+	})
+
+	if len(queue) != 2 {
+		t.Fatalf("expected 2 due notes, got %d: %+v", len(queue), queue)
+	}
+	if queue[0].Path != filepath.Clean(edited) || !queue[0].Scheduled || queue[0].Bucket != scheduledBucket {
+		t.Fatalf("expected overdue scheduled note first despite recent edit, got %+v", queue[0])
+	}
+	if queue[1].Path != filepath.Clean(stale) || queue[1].Scheduled {
+		t.Fatalf("expected unscheduled stale note second, got %+v", queue[1])
+	}
+}
+
+func TestParseGrade(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]Grade{"again": GradeAgain, "HARD": GradeHard, "3": GradeGood, " easy ": GradeEasy}
+	for input, want := range cases {
+		got, err := ParseGrade(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseGrade(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseGrade("meh"); err == nil {
+		t.Fatalf("expected error for unknown grade")
+	}
+}
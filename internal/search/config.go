@@ -1,5 +1,7 @@
 package search
 
+import "strings"
+
 // Config describes index behavior.
 type Config struct {
 	// EnableBody controls whether the index searches note bodies in addition to
@@ -23,6 +25,33 @@ type Query struct {
 	Metadata map[string][]string
 }
 
+// ParseQuery reads the query language shared by the CLI and templates:
+// tag:x filters by tag, key:value filters by front matter, and the remaining
+// words are matched as free text.
+func ParseQuery(raw string) Query {
+	var (
+		q     Query
+		terms []string
+	)
+	for _, token := range strings.Fields(raw) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || key == "" || value == "" {
+			terms = append(terms, token)
+			continue
+		}
+		if strings.EqualFold(key, "tag") {
+			q.Tags = append(q.Tags, strings.TrimPrefix(value, "#"))
+			continue
+		}
+		if q.Metadata == nil {
+			q.Metadata = make(map[string][]string)
+		}
+		q.Metadata[key] = append(q.Metadata[key], value)
+	}
+	q.Term = strings.Join(terms, " ")
+	return q
+}
+
 // Result captures a document match from the index.
 type Result struct {
 	Path      string
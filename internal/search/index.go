@@ -11,9 +11,12 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode"
 	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/hashtag"
 )
 
 type document struct {
@@ -417,7 +420,7 @@ func (idx *Index) loadDocument(path string) (document, error) {
 
 	return document{
 		Path:        filepath.Clean(path),
-		Tags:        tags,
+		Tags:        mergeTags(tags, hashtag.Find(string(body))),
 		FrontMatter: parsed,
 		Links:       links,
 		Body:        string(body),
@@ -458,6 +461,16 @@ func (idx *Index) Documents() []Metadata {
 	return out
 }
 
+// Body returns the indexed body of the note at path, without its front
+// matter.
+func (idx *Index) Body(path string) (string, bool) {
+	doc, ok := idx.docs[filepath.Clean(path)]
+	if !ok {
+		return "", false
+	}
+	return doc.Body, true
+}
+
 // FilteredDocuments returns the metadata for notes matching the provided query.
 func (idx *Index) FilteredDocuments(q Query) []Metadata {
 	if len(idx.docs) == 0 {
@@ -654,7 +667,7 @@ func (d document) matchesFilters(q Query) bool {
 	if len(q.Tags) > 0 {
 		matched := false
 		for _, required := range q.Tags {
-			if containsFold(d.Tags, required) {
+			if hasTag(d.Tags, required) {
 				matched = true
 				break
 			}
@@ -853,7 +866,7 @@ func tagOverlapScore(tags, required []string) (float64, int) {
 	}
 	matches := 0
 	for _, want := range required {
-		if containsFold(tags, want) {
+		if hasTag(tags, want) {
 			matches++
 		}
 	}
@@ -1006,6 +1019,19 @@ func containsFold(values []string, target string) bool {
 	return false
 }
 
+// hasTag reports whether tags contains target or a tag nested below it, so
+// a filter on project also matches project/apollo.
+func hasTag(tags []string, target string) bool {
+	target = strings.ToLower(strings.TrimSuffix(target, "/"))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if tag == target || strings.HasPrefix(tag, target+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func splitFrontMatter(data []byte) ([]byte, []byte) {
 	re := regexp.MustCompile(`(?ms)^---\s*\n(.*?)\n---\s*\n?`)
 	loc := re.FindSubmatchIndex(data)
@@ -1056,6 +1082,32 @@ func parseFrontMatter(fm []byte) (map[string][]string, []string, error) {
 	return result, tags, nil
 }
 
+// mergeTags combines the front matter tags, where a scalar such as
+// "a, b" holds several tags, with the inline #tags of the body. Each tag is
+// kept once regardless of case, in order of first appearance.
+func mergeTags(frontMatter, inline []string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		tag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "#"), "/")
+		if key := strings.ToLower(tag); tag != "" && !seen[key] {
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	for _, value := range frontMatter {
+		for _, tag := range strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		}) {
+			add(tag)
+		}
+	}
+	for _, tag := range inline {
+		add(tag)
+	}
+	return tags
+}
+
 func flattenYAMLValue(node *yaml.Node) []string {
 	switch node.Kind {
 	case yaml.SequenceNode:
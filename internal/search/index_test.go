@@ -588,3 +588,29 @@ func alphabeticalSearch(idx *Index, q Query) []Result {
 	})
 	return results
 }
+
+func TestIndexTagFilterMatchesNestedTags(t *testing.T) {
+	dir := t.TempDir()
+	apollo := writeNote(t, dir, "apollo.md", "---\ntags:\n  - project/apollo\n---\nlaunch")
+	parent := writeNote(t, dir, "parent.md", "---\ntags:\n  - Project\n---\nplanning")
+	other := writeNote(t, dir, "other.md", "---\ntags:\n  - projects\n---\nunrelated")
+
+	idx := NewIndex(dir, Config{})
+	if err := idx.Build([]string{apollo, parent, other}); err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	docs := idx.FilteredDocuments(Query{Tags: []string{"project"}})
+	var paths []string
+	for _, doc := range docs {
+		paths = append(paths, doc.Path)
+	}
+	sort.Strings(paths)
+	if want := []string{apollo, parent}; strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected project to match nested tags only, got %v", paths)
+	}
+
+	if docs := idx.FilteredDocuments(Query{Tags: []string{"project/apollo"}}); len(docs) != 1 {
+		t.Fatalf("expected a nested filter to match one note, got %d", len(docs))
+	}
+}
@@ -0,0 +1,290 @@
+// Package inbox finds unsorted notes and applies the triage actions offered
+// by the inbox TUI.
+package inbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/frontmatter"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/relocate"
+)
+
+// Item is a note waiting in the inbox.
+type Item struct {
+	Path    string
+	Rel     string
+	Title   string
+	ModTime time.Time
+}
+
+type Service struct {
+	handler *handler.FileHandler
+	config  config.InboxConfig
+}
+
+func NewService(h *handler.FileHandler, cfg config.InboxConfig) *Service {
+	return &Service{handler: h, config: cfg.WithDefaults()}
+}
+
+// List returns the inbox notes, oldest first.
+func (s *Service) List() ([]Item, error) {
+	if s == nil || s.handler == nil {
+		return nil, errors.New("inbox service is not configured")
+	}
+	vault := s.handler.VaultDir()
+	folders := s.folders()
+
+	var items []Item
+	err := filepath.WalkDir(vault, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(vault, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (strings.HasPrefix(d.Name(), ".") || rel == "archive" || rel == "trash") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if !folders[filepath.ToSlash(filepath.Dir(rel))] && !s.matchesPattern(d.Name()) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		items = append(items, Item{
+			Path:    path,
+			Rel:     rel,
+			Title:   strings.TrimSuffix(d.Name(), ".md"),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].ModTime.Equal(items[j].ModTime) {
+			return items[i].ModTime.Before(items[j].ModTime)
+		}
+		return items[i].Rel < items[j].Rel
+	})
+	return items, nil
+}
+
+// Count returns the number of notes in the inbox.
+func (s *Service) Count() (int, error) {
+	items, err := s.List()
+	return len(items), err
+}
+
+// Contains reports whether the vault-relative path rel is, or would be, an
+// inbox note.
+func (s *Service) Contains(rel string) bool {
+	rel = filepath.ToSlash(filepath.Clean(rel))
+	if filepath.Ext(rel) != ".md" {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	if parts[0] == "archive" || parts[0] == "trash" {
+		return false
+	}
+	for _, part := range parts {
+		if strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return s.folders()[path.Dir(rel)] || s.matchesPattern(path.Base(rel))
+}
+
+func (s *Service) folders() map[string]bool {
+	folders := make(map[string]bool, len(s.config.Folders))
+	for _, folder := range s.config.Folders {
+		folder = strings.Trim(filepath.ToSlash(filepath.Clean(folder)), "/")
+		if folder != "" && folder != "." {
+			folders[folder] = true
+		}
+	}
+	return folders
+}
+
+func (s *Service) matchesPattern(name string) bool {
+	for _, pattern := range s.config.Patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Notes returns the vault-relative paths of every note outside the archive
+// and trash, for use as merge and upstream targets.
+func (s *Service) Notes() ([]string, error) {
+	files, err := s.handler.WalkFiles([]string{"archive", "trash"}, nil, "default")
+	if err != nil {
+		return nil, err
+	}
+	vault := s.handler.VaultDir()
+	notes := make([]string, 0, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(vault, file)
+		if err != nil {
+			continue
+		}
+		notes = append(notes, filepath.ToSlash(rel))
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
+
+// Move moves the note into subdir, rewriting links that point at it, and
+// returns its new path.
+func (s *Service) Move(path, subdir string) (string, error) {
+	files, err := s.handler.WalkFiles(nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+	plan, err := relocate.Build(s.handler.VaultDir(), []string{path}, subdir, files)
+	if err != nil {
+		return "", err
+	}
+	if len(plan.Moves) == 0 {
+		return path, nil
+	}
+	if err := plan.Apply(s.handler); err != nil {
+		return "", err
+	}
+	return plan.Moves[0].To, nil
+}
+
+// AddTags adds tags to the note's front matter.
+func (s *Service) AddTags(path string, tags []string) error {
+	return s.edit("tag", path, func(doc *frontmatter.Document) error {
+		doc.AddValues("tags", tags...)
+		return nil
+	})
+}
+
+// SetUpstream points the note's `up` link at the upstream note.
+func (s *Service) SetUpstream(path, upstream string) error {
+	name := strings.TrimSuffix(filepath.Base(upstream), ".md")
+	if name == "" {
+		return errors.New("upstream note cannot be empty")
+	}
+	return s.edit("upstream", path, func(doc *frontmatter.Document) error {
+		return doc.Set("up", "[["+name+"]]")
+	})
+}
+
+// Merge appends the note's body and tags to target, redirects links to the
+// note, and moves it to the trash.
+func (s *Service) Merge(path, target string) error {
+	files, err := s.handler.WalkFiles(nil, nil, "")
+	if err != nil {
+		return err
+	}
+	plan, err := relocate.Merge(s.handler.VaultDir(), []string{path}, target, files)
+	if err != nil {
+		return err
+	}
+	return plan.Apply(s.handler)
+}
+
+// ToTask appends the note as an open task to the task file at pinPath and
+// moves the note to the trash, journaled as one step. The task text is the first line of the note
+// body, or the note title when the body is empty.
+func (s *Service) ToTask(path, pinPath string) error {
+	if strings.TrimSpace(pinPath) == "" {
+		return errors.New("no task pin selected")
+	}
+
+	doc, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSuffix(filepath.Base(path), ".md")
+	for _, line := range strings.Split(doc.Body(), "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#-*> "))
+		if line != "" {
+			text = line
+			break
+		}
+	}
+
+	existing, err := s.handler.ReadFile(pinPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	entry := "- [ ] " + text + "\n"
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		entry = "\n" + entry
+	}
+	data := append(existing, entry...)
+
+	return s.handler.Journal().Group(func() error {
+		if err := s.handler.WriteFileOp("to-task", pinPath, data); err != nil {
+			return err
+		}
+		return s.handler.TrashWithReason(path, "converted to task")
+	})
+}
+
+// Archive moves the note to the archive.
+func (s *Service) Archive(path string) error {
+	return s.handler.Archive(path)
+}
+
+// Trash moves the note to the trash.
+func (s *Service) Trash(path string) error {
+	return s.handler.TrashWithReason(path, "inbox triage")
+}
+
+func (s *Service) read(path string) (*frontmatter.Document, error) {
+	data, err := s.handler.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return doc, nil
+}
+
+func (s *Service) edit(op, path string, apply func(*frontmatter.Document) error) error {
+	doc, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	if err := apply(doc); err != nil {
+		return err
+	}
+	data, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+	return s.handler.WriteFileOp(op, path, data)
+}
@@ -0,0 +1,145 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+func writeNote(t *testing.T, dir, rel, content string, mod time.Time) string {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", rel, err)
+	}
+	if !mod.IsZero() {
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatalf("failed to set mod time: %v", err)
+		}
+	}
+	return path
+}
+
+func TestListFindsFoldersAndPatterns(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeNote(t, dir, "echoes/echo_1.md", "a", now.Add(-time.Hour))
+	writeNote(t, dir, "atoms/scratch-20240301-100000.md", "b", now.Add(-2*time.Hour))
+	writeNote(t, dir, "inbox/nested/deep.md", "c", now)
+	writeNote(t, dir, "atoms/sorted.md", "d", now)
+	writeNote(t, dir, "trash/echoes/old.md", "e", now)
+	writeNote(t, dir, "trash/scratch-old.md", "f", now)
+
+	svc := NewService(handler.NewFileHandler(dir), config.InboxConfig{})
+	items, err := svc.List()
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Rel)
+	}
+	want := "atoms/scratch-20240301-100000.md,echoes/echo_1.md"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %s, got %v", want, got)
+	}
+
+	custom := NewService(handler.NewFileHandler(dir), config.InboxConfig{Folders: []string{"inbox/nested"}, Patterns: []string{"none"}})
+	if count, err := custom.Count(); err != nil || count != 1 {
+		t.Fatalf("expected 1 item in custom inbox, got %d (%v)", count, err)
+	}
+
+	for rel, want := range map[string]bool{
+		"echoes/new.md":                    true,
+		"atoms/scratch-20240301-100000.md": true,
+		"atoms/sorted.md":                  false,
+		"trash/echoes/old.md":              false,
+		"echoes/.hidden.md":                false,
+		"echoes/picture.png":               false,
+	} {
+		if got := svc.Contains(rel); got != want {
+			t.Fatalf("Contains(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
+
+func TestTriageActions(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewService(handler.NewFileHandler(dir), config.InboxConfig{})
+
+	source := writeNote(t, dir, "echoes/idea.md", "---\ntitle: idea\ntags:\n  - raw\n---\nCall the vendor\n", time.Time{})
+	if err := svc.AddTags(source, []string{"raw", "vendor"}); err != nil {
+		t.Fatalf("AddTags returned error: %v", err)
+	}
+	if err := svc.SetUpstream(source, "atoms/projects.md"); err != nil {
+		t.Fatalf("SetUpstream returned error: %v", err)
+	}
+	data, _ := os.ReadFile(source)
+	if !strings.Contains(string(data), "  - vendor\n") || !strings.Contains(string(data), "up: '[[projects]]'") {
+		t.Fatalf("unexpected front matter:\n%s", data)
+	}
+
+	moved, err := svc.Move(source, "atoms")
+	if err != nil {
+		t.Fatalf("Move returned error: %v", err)
+	}
+	if moved != filepath.Join(dir, "atoms", "idea.md") {
+		t.Fatalf("unexpected move target %s", moved)
+	}
+
+	target := writeNote(t, dir, "atoms/vendors.md", "---\ntitle: vendors\n---\n# Vendors\n", time.Time{})
+	if err := svc.Merge(moved, target); err != nil {
+		t.Fatalf("Merge returned error: %v", err)
+	}
+	merged, _ := os.ReadFile(target)
+	if !strings.HasSuffix(string(merged), "# Vendors\n\nCall the vendor\n") || !strings.Contains(string(merged), "- vendor") {
+		t.Fatalf("unexpected merged note:\n%s", merged)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "trash", "atoms", "idea.md")); err != nil {
+		t.Fatalf("expected merged note in trash: %v", err)
+	}
+
+	scratch := writeNote(t, dir, "atoms/scratch-1.md", "\n# Renew domain\n", time.Time{})
+	pin := writeNote(t, dir, "tasks.md", "- [ ] existing", time.Time{})
+	if err := svc.ToTask(scratch, pin); err != nil {
+		t.Fatalf("ToTask returned error: %v", err)
+	}
+	tasks, _ := os.ReadFile(pin)
+	if string(tasks) != "- [ ] existing\n- [ ] Renew domain\n" {
+		t.Fatalf("unexpected task file %q", tasks)
+	}
+	if _, err := os.Stat(scratch); !os.IsNotExist(err) {
+		t.Fatalf("expected converted note to leave the inbox, got %v", err)
+	}
+}
+
+func TestToTaskUndoesInOneStep(t *testing.T) {
+	dir := t.TempDir()
+	h := handler.NewFileHandler(dir)
+	svc := NewService(h, config.InboxConfig{})
+
+	scratch := writeNote(t, dir, "inbox/call.md", "Call the bank\n", time.Time{})
+	pin := writeNote(t, dir, "tasks.md", "- [ ] existing\n", time.Time{})
+	if err := svc.ToTask(scratch, pin); err != nil {
+		t.Fatalf("ToTask returned error: %v", err)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if tasks, _ := os.ReadFile(pin); string(tasks) != "- [ ] existing\n" {
+		t.Fatalf("expected the task file to be restored, got %q", tasks)
+	}
+	if data, err := os.ReadFile(scratch); err != nil || string(data) != "Call the bank\n" {
+		t.Fatalf("expected the note back in the inbox, got %q, %v", data, err)
+	}
+}
@@ -76,3 +76,34 @@ func TestServiceClosePreventsSnapshots(t *testing.T) {
 		t.Fatalf("expected ErrClosed after Close, got %v", err)
 	}
 }
+
+type stubSource struct {
+	idx *search.Index
+	err error
+}
+
+func (s stubSource) AcquireSnapshot() (*search.Index, error) { return s.idx, s.err }
+
+func TestSnapshotFallsBackWhileUnavailable(t *testing.T) {
+	dir := t.TempDir()
+	note := writeTestNote(t, dir, "note.md", "Fallback content")
+	files := func() ([]string, error) { return []string{note}, nil }
+
+	for _, src := range []Source{nil, stubSource{err: ErrUnavailable}, stubSource{err: ErrClosed}} {
+		idx, err := Snapshot(src, dir, search.Config{EnableBody: true}, files)
+		if err != nil {
+			t.Fatalf("Snapshot(%v) returned error: %v", src, err)
+		}
+		if results := idx.Search(search.Query{Term: "fallback"}); len(results) != 1 {
+			t.Fatalf("expected the fallback index to contain the note, got %+v", results)
+		}
+	}
+
+	shared := search.NewIndex(dir, search.Config{})
+	if idx, err := Snapshot(stubSource{idx: shared}, dir, search.Config{}, files); err != nil || idx != shared {
+		t.Fatalf("expected the shared index, got %p, %v", idx, err)
+	}
+	if _, err := Snapshot(stubSource{err: os.ErrPermission}, dir, search.Config{}, files); err == nil {
+		t.Fatalf("expected an unexpected acquire error to be returned")
+	}
+}
@@ -0,0 +1,40 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Paintersrp/an/internal/search"
+)
+
+// Source hands out snapshots of a shared search index, such as a Service.
+type Source interface {
+	AcquireSnapshot() (*search.Index, error)
+}
+
+// Snapshot returns the shared index of src when it is ready. While it is
+// still warming up, has been closed, or src is nil, a fresh index is built
+// over the paths returned by files.
+func Snapshot(src Source, vault string, cfg search.Config, files func() ([]string, error)) (*search.Index, error) {
+	if src != nil {
+		idx, err := src.AcquireSnapshot()
+		switch {
+		case err == nil && idx != nil:
+			return idx, nil
+		case err == nil || errors.Is(err, ErrUnavailable) || errors.Is(err, ErrClosed):
+			// fall back to building a local index below
+		default:
+			return nil, fmt.Errorf("acquire search index: %w", err)
+		}
+	}
+
+	paths, err := files()
+	if err != nil {
+		return nil, err
+	}
+	idx := search.NewIndex(vault, cfg)
+	if err := idx.Build(paths); err != nil {
+		return nil, fmt.Errorf("build search index: %w", err)
+	}
+	return idx, nil
+}
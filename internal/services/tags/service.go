@@ -0,0 +1,262 @@
+// Package tags renames and merges tags across the vault and arranges nested
+// tags such as project/apollo into a tree.
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/frontmatter"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/hashtag"
+	"github.com/Paintersrp/an/utils"
+)
+
+var scalarTagRe = regexp.MustCompile(`[^\s,]+`)
+
+// Renames maps old tag names to new ones. A rename also applies to nested
+// tags below the old name, so project -> work turns project/apollo into
+// work/apollo.
+type Renames map[string]string
+
+// NewRenames builds the renames for merging every tag in from into into.
+// Renaming is a merge of a single tag.
+func NewRenames(from []string, into string) (Renames, error) {
+	into = normalize(into)
+	if err := validate(into); err != nil {
+		return nil, err
+	}
+	renames := make(Renames, len(from))
+	for _, tag := range from {
+		tag = normalize(tag)
+		if err := validate(tag); err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(tag, into) {
+			continue
+		}
+		renames[tag] = into
+	}
+	if len(renames) == 0 {
+		return nil, errors.New("nothing to rename")
+	}
+	return renames, nil
+}
+
+// Apply returns the new name for tag and whether it changed. The longest
+// matching old name wins, so a rename of project/apollo takes precedence
+// over a rename of project.
+func (r Renames) Apply(tag string) (string, bool) {
+	best, target := "", ""
+	lowered := strings.ToLower(tag)
+	for from, to := range r {
+		key := strings.ToLower(from)
+		if lowered != key && !strings.HasPrefix(lowered, key+"/") {
+			continue
+		}
+		if len(from) > len(best) {
+			best, target = from, to
+		}
+	}
+	if best == "" {
+		return tag, false
+	}
+	renamed := target + tag[len(best):]
+	return renamed, renamed != tag
+}
+
+// Change is the new content of one note.
+type Change struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Plan lists the notes a rename changes.
+type Plan struct {
+	Changes []Change
+}
+
+// NewPlan rewrites tags in files: the front matter `tags` value and inline
+// #tags in the body outside code.
+func NewPlan(files []string, renames Renames) (*Plan, error) {
+	plan := &Plan{}
+	for _, path := range files {
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		after, err := Rewrite(before, renames)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		if string(after) != string(before) {
+			plan.Changes = append(plan.Changes, Change{Path: path, Before: before, After: after})
+		}
+	}
+	return plan, nil
+}
+
+// Apply writes the changes, journaled as one group so a single undo
+// reverts the rename.
+func (p *Plan) Apply(h *handler.FileHandler) error {
+	return h.Journal().Group(func() error {
+		for _, change := range p.Changes {
+			if err := h.WriteFileOp("retag", change.Path, change.After); err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(change.Path), err)
+			}
+		}
+		return nil
+	})
+}
+
+// Rewrite renames the tags in one note. Notes without matching tags are
+// returned unchanged, byte for byte.
+func Rewrite(content []byte, renames Renames) ([]byte, error) {
+	doc, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := retagFrontMatter(doc, renames)
+	body, bodyChanged := retagBody(doc.Body(), renames)
+	if !changed && !bodyChanged {
+		return content, nil
+	}
+	if !changed && strings.HasSuffix(string(content), doc.Body()) {
+		return []byte(strings.TrimSuffix(string(content), doc.Body()) + body), nil
+	}
+	doc.SetBody(body)
+	return doc.Bytes()
+}
+
+func retagFrontMatter(doc *frontmatter.Document, renames Renames) bool {
+	node, ok := doc.Get("tags")
+	if !ok {
+		return false
+	}
+
+	changed := false
+	switch node.Kind {
+	case yaml.SequenceNode:
+		seen := make(map[string]bool, len(node.Content))
+		kept := node.Content[:0]
+		for _, item := range node.Content {
+			if renamed, ok := renames.Apply(item.Value); ok {
+				item.Value = renamed
+				changed = true
+			}
+			key := strings.ToLower(item.Value)
+			if seen[key] {
+				changed = true
+				continue
+			}
+			seen[key] = true
+			kept = append(kept, item)
+		}
+		node.Content = kept
+	case yaml.ScalarNode:
+		node.Value = scalarTagRe.ReplaceAllStringFunc(node.Value, func(tag string) string {
+			renamed, ok := renames.Apply(tag)
+			changed = changed || ok
+			return renamed
+		})
+	}
+	return changed
+}
+
+// retagBody renames inline #tags, skipping fenced code blocks and inline
+// code spans.
+func retagBody(body string, renames Renames) (string, bool) {
+	changed := false
+	body = hashtag.Map(body, func(tag string) string {
+		renamed, ok := renames.Apply(tag)
+		changed = changed || ok
+		return renamed
+	})
+	return body, changed
+}
+
+func normalize(tag string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "#"), "/")
+}
+
+func validate(tag string) error {
+	if tag == "" {
+		return errors.New("tag cannot be empty")
+	}
+	if strings.ContainsAny(tag, " \t") {
+		return fmt.Errorf("invalid tag %q: tags cannot contain spaces", tag)
+	}
+	if _, err := utils.ValidateTags(tag); err != nil {
+		return fmt.Errorf("invalid tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+// Node is one level of the tag hierarchy. Name is the full tag, Label its
+// last segment. Count is the number of uses of the tag itself and Total
+// includes every nested tag.
+type Node struct {
+	Name     string
+	Label    string
+	Count    int
+	Total    int
+	Children []*Node
+}
+
+// Tree arranges tag counts into a hierarchy split on "/". Parents that are
+// never used directly are added with a count of zero. Siblings are sorted by
+// total, then name.
+func Tree(counts map[string]int) []*Node {
+	root := &Node{}
+	nodes := map[string]*Node{"": root}
+	var ensure func(name string) *Node
+	ensure = func(name string) *Node {
+		if node, ok := nodes[name]; ok {
+			return node
+		}
+		parent, label := "", name
+		if idx := strings.LastIndex(name, "/"); idx >= 0 {
+			parent, label = name[:idx], name[idx+1:]
+		}
+		node := &Node{Name: name, Label: label}
+		nodes[name] = node
+		p := ensure(parent)
+		p.Children = append(p.Children, node)
+		return node
+	}
+
+	for tag, count := range counts {
+		tag = strings.Trim(tag, "/")
+		if tag == "" {
+			continue
+		}
+		ensure(tag).Count += count
+	}
+
+	var total func(node *Node) int
+	total = func(node *Node) int {
+		node.Total = node.Count
+		for _, child := range node.Children {
+			node.Total += total(child)
+		}
+		sort.Slice(node.Children, func(i, j int) bool {
+			a, b := node.Children[i], node.Children[j]
+			if a.Total != b.Total {
+				return a.Total > b.Total
+			}
+			return a.Name < b.Name
+		})
+		return node.Total
+	}
+	total(root)
+	return root.Children
+}
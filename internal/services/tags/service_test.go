@@ -0,0 +1,179 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/handler"
+)
+
+func TestRewriteRenamesFrontMatterAndInlineTags(t *testing.T) {
+	renames, err := NewRenames([]string{"project"}, "work")
+	if err != nil {
+		t.Fatalf("NewRenames returned error: %v", err)
+	}
+
+	content := "---\ntitle: plan\ntags:\n  - project/apollo\n  - go\n---\n\n" +
+		"Notes for #project and #project/apollo, not #projects.\n" +
+		"See [top](#project) and `#project` in code.\n" +
+		"```\n#project\n```\n"
+	want := "---\ntitle: plan\ntags:\n  - work/apollo\n  - go\n---\n\n" +
+		"Notes for #work and #work/apollo, not #projects.\n" +
+		"See [top](#project) and `#project` in code.\n" +
+		"```\n#project\n```\n"
+
+	got, err := Rewrite([]byte(content), renames)
+	if err != nil {
+		t.Fatalf("Rewrite returned error: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("unexpected rewrite:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRewriteKeepsOtherFrontMatterLines(t *testing.T) {
+	renames, err := NewRenames([]string{"project"}, "work")
+	if err != nil {
+		t.Fatalf("NewRenames returned error: %v", err)
+	}
+
+	content := "---\naliases: [a,b]\ntags:\n- project\n- go\nowners:\n- ann\n---\nBody.\n"
+	want := "---\naliases: [a,b]\ntags:\n- work\n- go\nowners:\n- ann\n---\nBody.\n"
+	got, err := Rewrite([]byte(content), renames)
+	if err != nil {
+		t.Fatalf("Rewrite returned error: %v", err)
+	}
+	if string(got) != want {
+		t.Fatalf("unexpected rewrite:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRewriteMergeDedupesTags(t *testing.T) {
+	renames, err := NewRenames([]string{"golang", "#Go-Lang"}, "go")
+	if err != nil {
+		t.Fatalf("NewRenames returned error: %v", err)
+	}
+
+	got, err := Rewrite([]byte("---\ntags: [golang, go, go-lang]\n---\n\nBody.\n"), renames)
+	if err != nil {
+		t.Fatalf("Rewrite returned error: %v", err)
+	}
+	if want := "---\ntags: [go]\n---\n\nBody.\n"; string(got) != want {
+		t.Fatalf("unexpected rewrite:\n%s\nwant:\n%s", got, want)
+	}
+
+	untouched := []byte("---\ntitle:   spaced\n---\nNo tags here.\n")
+	got, err = Rewrite(untouched, renames)
+	if err != nil || string(got) != string(untouched) {
+		t.Fatalf("expected notes without the tag to be unchanged, got %q (%v)", got, err)
+	}
+}
+
+func TestRenamesPreferLongestMatch(t *testing.T) {
+	renames := Renames{"project": "work", "project/apollo": "moon"}
+	cases := map[string]string{
+		"project":          "work",
+		"project/apollo":   "moon",
+		"project/apollo/x": "moon/x",
+		"project/gemini":   "work/gemini",
+		"projects":         "projects",
+	}
+	for tag, want := range cases {
+		if got, _ := renames.Apply(tag); got != want {
+			t.Fatalf("Apply(%q) = %q, want %q", tag, got, want)
+		}
+	}
+
+	if _, err := NewRenames([]string{"a b"}, "c"); err == nil {
+		t.Fatalf("expected tags with spaces to be rejected")
+	}
+	if _, err := NewRenames([]string{"go"}, "Go"); err == nil {
+		t.Fatalf("expected renaming a tag to itself to fail")
+	}
+}
+
+func TestPlanApplyIsOneUndoStep(t *testing.T) {
+	vault := t.TempDir()
+	a := filepath.Join(vault, "a.md")
+	b := filepath.Join(vault, "b.md")
+	c := filepath.Join(vault, "c.md")
+	writeFile(t, a, "---\ntags:\n  - old\n---\n\nA.\n")
+	writeFile(t, b, "B mentions #old.\n")
+	writeFile(t, c, "C is untagged.\n")
+
+	renames, err := NewRenames([]string{"old"}, "new")
+	if err != nil {
+		t.Fatalf("NewRenames returned error: %v", err)
+	}
+	plan, err := NewPlan([]string{a, b, c}, renames)
+	if err != nil {
+		t.Fatalf("NewPlan returned error: %v", err)
+	}
+	if len(plan.Changes) != 2 {
+		t.Fatalf("expected two notes to change, got %d", len(plan.Changes))
+	}
+
+	h := handler.NewFileHandler(vault)
+	if err := plan.Apply(h); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if got := readFile(t, b); got != "B mentions #new.\n" {
+		t.Fatalf("unexpected body rewrite %q", got)
+	}
+
+	if _, err := h.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if got := readFile(t, a); got != "---\ntags:\n  - old\n---\n\nA.\n" {
+		t.Fatalf("expected one undo to restore every note, got %q", got)
+	}
+	if got := readFile(t, b); got != "B mentions #old.\n" {
+		t.Fatalf("expected one undo to restore every note, got %q", got)
+	}
+}
+
+func TestTreeNestsTags(t *testing.T) {
+	counts := map[string]int{
+		"project/apollo":        1,
+		"project/apollo/launch": 1,
+		"project/gemini":        1,
+		"project":               1,
+		"go":                    1,
+	}
+
+	roots := Tree(counts)
+	if len(roots) != 2 || roots[0].Name != "project" || roots[1].Name != "go" {
+		t.Fatalf("unexpected roots %#v", roots)
+	}
+	project := roots[0]
+	if project.Count != 1 || project.Total != 4 {
+		t.Fatalf("expected project to count itself and its children, got %d/%d", project.Count, project.Total)
+	}
+	apollo := project.Children[0]
+	if apollo.Name != "project/apollo" || apollo.Label != "apollo" || apollo.Total != 2 {
+		t.Fatalf("unexpected child %#v", apollo)
+	}
+	if len(apollo.Children) != 1 || apollo.Children[0].Name != "project/apollo/launch" {
+		t.Fatalf("expected nested children, got %#v", apollo.Children)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
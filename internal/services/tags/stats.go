@@ -0,0 +1,94 @@
+package tags
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Paintersrp/an/internal/search"
+)
+
+// Stat summarizes one tag across the vault.
+type Stat struct {
+	Name     string    `json:"name"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+	Related  []Related `json:"related,omitempty"`
+}
+
+// Related counts the notes that carry a tag alongside another one.
+type Related struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Stats counts the tags of the indexed notes, front matter and inline alike.
+// Tags are matched regardless of case and reported with the spelling seen
+// first. LastUsed is the latest modification time of a note with the tag.
+// Stats are sorted by count, then name; related tags the same way.
+func Stats(docs []search.Metadata) []Stat {
+	stats := make(map[string]*Stat)
+	related := make(map[string]map[string]int)
+
+	for _, doc := range docs {
+		keys := make([]string, 0, len(doc.Tags))
+		seen := make(map[string]bool, len(doc.Tags))
+		for _, tag := range doc.Tags {
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			keys = append(keys, key)
+
+			stat, ok := stats[key]
+			if !ok {
+				stat = &Stat{Name: tag}
+				stats[key] = stat
+				related[key] = make(map[string]int)
+			}
+			stat.Count++
+			if doc.ModifiedAt.After(stat.LastUsed) {
+				stat.LastUsed = doc.ModifiedAt
+			}
+		}
+		for _, a := range keys {
+			for _, b := range keys {
+				if a != b {
+					related[a][b]++
+				}
+			}
+		}
+	}
+
+	out := make([]Stat, 0, len(stats))
+	for key, stat := range stats {
+		for other, count := range related[key] {
+			stat.Related = append(stat.Related, Related{Name: stats[other].Name, Count: count})
+		}
+		sort.Slice(stat.Related, func(i, j int) bool {
+			a, b := stat.Related[i], stat.Related[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Name < b.Name
+		})
+		out = append(out, *stat)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Counts returns the note count of each tag in stats, as Tree expects.
+func Counts(stats []Stat) map[string]int {
+	counts := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		counts[stat.Name] = stat.Count
+	}
+	return counts
+}
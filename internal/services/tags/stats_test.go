@@ -0,0 +1,37 @@
+package tags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/search"
+)
+
+func TestStatsCountsCoOccurrenceAndLastUse(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	stats := Stats([]search.Metadata{
+		{Path: "a.md", Tags: []string{"go", "cli"}, ModifiedAt: older},
+		{Path: "b.md", Tags: []string{"Go", "tui", "cli"}, ModifiedAt: newer},
+		{Path: "c.md", Tags: []string{"go", "go"}, ModifiedAt: older},
+	})
+
+	if len(stats) != 3 {
+		t.Fatalf("expected three tags, got %#v", stats)
+	}
+	goStat := stats[0]
+	if goStat.Name != "go" || goStat.Count != 3 || !goStat.LastUsed.Equal(newer) {
+		t.Fatalf("unexpected go stat %#v", goStat)
+	}
+	if len(goStat.Related) != 2 || goStat.Related[0] != (Related{Name: "cli", Count: 2}) ||
+		goStat.Related[1] != (Related{Name: "tui", Count: 1}) {
+		t.Fatalf("unexpected related tags %#v", goStat.Related)
+	}
+	if stats[1].Name != "cli" || stats[1].Count != 2 {
+		t.Fatalf("expected cli second, got %#v", stats[1])
+	}
+
+	if counts := Counts(stats); counts["go"] != 3 || counts["tui"] != 1 {
+		t.Fatalf("unexpected counts %#v", counts)
+	}
+}
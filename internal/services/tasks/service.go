@@ -124,7 +124,7 @@ func (s *Service) Toggle(path string, line int) (bool, error) {
 }
 
 func (s *Service) writeTaskFile(path string, lines []string) error {
-	if err := s.handler.WriteFile(path, []byte(strings.Join(lines, "\n"))); err != nil {
+	if err := s.handler.WriteFileOp("toggle", path, []byte(strings.Join(lines, "\n"))); err != nil {
 		return err
 	}
 	s.queueIndexUpdate(path)
@@ -0,0 +1,382 @@
+// Package snapshot keeps earlier versions of notes under
+// `<vault>/.an/history/`, so an overwrite made through an can be inspected
+// and reverted without git. Contents are stored once per SHA-256 in
+// objects/, and each note has a version list in notes/<path>.json.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/timeutil"
+)
+
+// ErrNoVersion is returned when a note has no snapshot with the requested
+// revision.
+var ErrNoVersion = errors.New("no such version")
+
+// Version is one snapshot of a note. Revisions count up from 1 per note and
+// are never reused, so a revision keeps its number after older ones are
+// pruned. Op names the operation that was about to change the note.
+type Version struct {
+	Rev  int       `json:"rev"`
+	Time time.Time `json:"time"`
+	Hash string    `json:"hash"`
+	Size int       `json:"size"`
+	Op   string    `json:"op,omitempty"`
+}
+
+// Options limit how many snapshots are kept. Zero values keep everything.
+type Options struct {
+	MaxVersions int
+	MaxAge      time.Duration
+}
+
+// Store is the snapshot store of one vault. A nil Store records nothing, so
+// callers can use it whether or not history is enabled.
+type Store struct {
+	vault string
+	dir   string
+	opts  Options
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+// Dir returns the snapshot directory of vault.
+func Dir(vault string) string {
+	return filepath.Join(vault, ".an", "history")
+}
+
+// WithExt adds the .md extension to a note name given without it, so
+// history commands accept "atoms/idea" as well as "atoms/idea.md".
+func WithExt(name string) string {
+	if filepath.Ext(name) != ".md" {
+		return name + ".md"
+	}
+	return name
+}
+
+// Open returns the store for vault.
+func Open(vault string, opts Options) *Store {
+	return &Store{
+		vault: filepath.Clean(vault),
+		dir:   Dir(vault),
+		opts:  opts,
+		now:   time.Now,
+	}
+}
+
+// FromConfig opens the store described by the workspace history settings,
+// or returns nil when history is disabled.
+func FromConfig(vault string, cfg config.HistoryConfig) (*Store, error) {
+	if !cfg.Enabled || strings.TrimSpace(vault) == "" {
+		return nil, nil
+	}
+	opts := Options{MaxVersions: cfg.MaxVersions}
+	if strings.TrimSpace(cfg.MaxAge) != "" {
+		age, err := timeutil.ParseAge(cfg.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("history.max_age: %w", err)
+		}
+		opts.MaxAge = age
+	}
+	return Open(vault, opts), nil
+}
+
+// Save records content as the newest version of path. Nothing is recorded
+// when content matches the newest version already kept.
+func (s *Store) Save(op, path string, content []byte) error {
+	if s == nil {
+		return nil
+	}
+	rel, err := s.rel(path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log, err := s.readLog(rel)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	if n := len(log.Versions); n > 0 && log.Versions[n-1].Hash == hash {
+		return nil
+	}
+
+	if err := s.writeObject(hash, content); err != nil {
+		return err
+	}
+	log.Next++
+	log.Versions = append(log.Versions, Version{
+		Rev:  log.Next,
+		Time: s.now().UTC(),
+		Hash: hash,
+		Size: len(content),
+		Op:   op,
+	})
+
+	dropped := s.prune(log)
+	if err := s.writeLog(rel, log); err != nil {
+		return err
+	}
+	if len(dropped) > 0 {
+		return s.collect(dropped)
+	}
+	return nil
+}
+
+// SaveFile records the current content of path, if it exists.
+func (s *Store) SaveFile(op, path string) error {
+	if s == nil {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	return s.Save(op, path, content)
+}
+
+// Versions lists the snapshots of path, oldest first.
+func (s *Store) Versions(path string) ([]Version, error) {
+	if s == nil {
+		return nil, nil
+	}
+	rel, err := s.rel(path)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log, err := s.readLog(rel)
+	if err != nil {
+		return nil, err
+	}
+	return log.Versions, nil
+}
+
+// Read returns the content of revision rev of path.
+func (s *Store) Read(path string, rev int) ([]byte, Version, error) {
+	versions, err := s.Versions(path)
+	if err != nil {
+		return nil, Version{}, err
+	}
+	for _, version := range versions {
+		if version.Rev == rev {
+			content, err := os.ReadFile(s.objectPath(version.Hash))
+			if err != nil {
+				return nil, Version{}, fmt.Errorf("read snapshot %d: %w", rev, err)
+			}
+			return content, version, nil
+		}
+	}
+	return nil, Version{}, fmt.Errorf("%w: %d", ErrNoVersion, rev)
+}
+
+// Rename moves the version list of a note that moved, so its history follows
+// it. When the destination already has a history, the moved versions are
+// appended to it under new revisions instead of replacing it.
+func (s *Store) Rename(from, to string) error {
+	if s == nil {
+		return nil
+	}
+	fromRel, err := s.rel(from)
+	if err != nil {
+		return err
+	}
+	toRel, err := s.rel(to)
+	if err != nil {
+		return err
+	}
+	if fromRel == toRel {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src := s.logPath(fromRel)
+	if _, err := os.Stat(src); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	log, err := s.readLog(toRel)
+	if err != nil {
+		return err
+	}
+	if len(log.Versions) == 0 {
+		dst := s.logPath(toRel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		return os.Rename(src, dst)
+	}
+
+	moved, err := s.readLog(fromRel)
+	if err != nil {
+		return err
+	}
+	for _, version := range moved.Versions {
+		log.Next++
+		version.Rev = log.Next
+		log.Versions = append(log.Versions, version)
+	}
+	dropped := s.prune(log)
+	if err := s.writeLog(toRel, log); err != nil {
+		return err
+	}
+	if err := os.Remove(src); err != nil {
+		return err
+	}
+	if len(dropped) > 0 {
+		return s.collect(dropped)
+	}
+	return nil
+}
+
+// noteLog is the on-disk version list of one note. Next is the last
+// revision handed out.
+type noteLog struct {
+	Next     int       `json:"next"`
+	Versions []Version `json:"versions"`
+}
+
+// prune drops versions beyond the configured limits, oldest first, and
+// returns their hashes. The newest version is always kept.
+func (s *Store) prune(log *noteLog) []string {
+	keep := log.Versions
+	if s.opts.MaxAge > 0 {
+		cutoff := s.now().Add(-s.opts.MaxAge)
+		for len(keep) > 1 && keep[0].Time.Before(cutoff) {
+			keep = keep[1:]
+		}
+	}
+	if s.opts.MaxVersions > 0 && len(keep) > s.opts.MaxVersions {
+		keep = keep[len(keep)-s.opts.MaxVersions:]
+	}
+
+	dropped := make([]string, 0, len(log.Versions)-len(keep))
+	for _, version := range log.Versions[:len(log.Versions)-len(keep)] {
+		dropped = append(dropped, version.Hash)
+	}
+	log.Versions = append([]Version(nil), keep...)
+	return dropped
+}
+
+// collect removes the objects of dropped versions that no note refers to
+// anymore.
+func (s *Store) collect(hashes []string) error {
+	unused := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		unused[hash] = true
+	}
+
+	root := filepath.Join(s.dir, "notes")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var log noteLog
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil
+		}
+		for _, version := range log.Versions {
+			delete(unused, version.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for hash := range unused {
+		if err := os.Remove(s.objectPath(hash)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) readLog(rel string) (*noteLog, error) {
+	data, err := os.ReadFile(s.logPath(rel))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &noteLog{}, nil
+		}
+		return nil, err
+	}
+	var log noteLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("parse history of %s: %w", rel, err)
+	}
+	return &log, nil
+}
+
+func (s *Store) writeLog(rel string, log *noteLog) error {
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.logPath(rel), append(data, '\n'))
+}
+
+func (s *Store) writeObject(hash string, content []byte) error {
+	path := s.objectPath(hash)
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	return writeAtomic(path, content)
+}
+
+func (s *Store) objectPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash)
+}
+
+func (s *Store) logPath(rel string) string {
+	return filepath.Join(s.dir, "notes", filepath.FromSlash(rel)+".json")
+}
+
+func (s *Store) rel(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.vault, path)
+	}
+	rel, err := filepath.Rel(s.vault, filepath.Clean(path))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside the vault", path)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,203 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+)
+
+func countObjects(t *testing.T, vault string) int {
+	t.Helper()
+	count := 0
+	err := filepath.WalkDir(filepath.Join(Dir(vault), "objects"), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk objects: %v", err)
+	}
+	return count
+}
+
+func TestSaveDeduplicatesContent(t *testing.T) {
+	vault := t.TempDir()
+	store := Open(vault, Options{})
+
+	for _, step := range []struct{ path, content string }{
+		{"a.md", "shared"},
+		{"a.md", "shared"},
+		{"b.md", "shared"},
+		{"a.md", "changed"},
+	} {
+		if err := store.Save("edit", filepath.Join(vault, step.path), []byte(step.content)); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+
+	versions, err := store.Versions(filepath.Join(vault, "a.md"))
+	if err != nil {
+		t.Fatalf("Versions returned error: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Rev != 1 || versions[1].Rev != 2 {
+		t.Fatalf("expected revisions 1 and 2, got %+v", versions)
+	}
+	if got := countObjects(t, vault); got != 2 {
+		t.Fatalf("expected 2 stored objects, got %d", got)
+	}
+
+	content, version, err := store.Read("a.md", 1)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if string(content) != "shared" || version.Size != len("shared") {
+		t.Fatalf("unexpected rev 1: %q %+v", content, version)
+	}
+	if _, _, err := store.Read("a.md", 9); !errors.Is(err, ErrNoVersion) {
+		t.Fatalf("expected ErrNoVersion, got %v", err)
+	}
+	if err := store.Save("edit", filepath.Join(vault, "..", "outside.md"), nil); err == nil {
+		t.Fatalf("expected a path outside the vault to be rejected")
+	}
+}
+
+func TestRetentionPrunesVersionsAndObjects(t *testing.T) {
+	vault := t.TempDir()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := Open(vault, Options{MaxVersions: 3, MaxAge: 48 * time.Hour})
+	store.now = func() time.Time { return now }
+
+	save := func(content string) {
+		t.Helper()
+		if err := store.Save("edit", "note.md", []byte(content)); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+	revs := func() []int {
+		t.Helper()
+		versions, err := store.Versions("note.md")
+		if err != nil {
+			t.Fatalf("Versions returned error: %v", err)
+		}
+		var out []int
+		for _, version := range versions {
+			out = append(out, version.Rev)
+		}
+		return out
+	}
+
+	for _, content := range []string{"v1", "v2", "v3", "v4"} {
+		save(content)
+	}
+	if got := revs(); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Fatalf("expected revisions 2-4, got %v", got)
+	}
+	if got := countObjects(t, vault); got != 3 {
+		t.Fatalf("expected the pruned object to be removed, got %d objects", got)
+	}
+
+	now = now.Add(72 * time.Hour)
+	save("v5")
+	if got := revs(); len(got) != 1 || got[0] != 5 {
+		t.Fatalf("expected only revision 5 after the age limit, got %v", got)
+	}
+	if got := countObjects(t, vault); got != 1 {
+		t.Fatalf("expected 1 object left, got %d", got)
+	}
+}
+
+func TestRenameMovesHistory(t *testing.T) {
+	vault := t.TempDir()
+	store := Open(vault, Options{})
+	if err := store.Save("edit", "inbox/a.md", []byte("draft")); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if err := store.Rename(filepath.Join(vault, "inbox/a.md"), filepath.Join(vault, "atoms/a.md")); err != nil {
+		t.Fatalf("Rename returned error: %v", err)
+	}
+	if versions, _ := store.Versions("inbox/a.md"); len(versions) != 0 {
+		t.Fatalf("expected no history at the old path, got %+v", versions)
+	}
+	if content, _, err := store.Read("atoms/a.md", 1); err != nil || string(content) != "draft" {
+		t.Fatalf("expected history at the new path, got %q, %v", content, err)
+	}
+}
+
+func TestRenameMergesExistingHistory(t *testing.T) {
+	vault := t.TempDir()
+	store := Open(vault, Options{MaxVersions: 3})
+	for _, step := range []struct{ path, content string }{
+		{"atoms/a.md", "old 1"},
+		{"atoms/a.md", "old 2"},
+		{"inbox/a.md", "new 1"},
+		{"inbox/a.md", "new 2"},
+	} {
+		if err := store.Save("edit", step.path, []byte(step.content)); err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+	}
+
+	if err := store.Rename("inbox/a.md", "atoms/a.md"); err != nil {
+		t.Fatalf("Rename returned error: %v", err)
+	}
+	versions, err := store.Versions("atoms/a.md")
+	if err != nil {
+		t.Fatalf("Versions returned error: %v", err)
+	}
+	if len(versions) != 3 || versions[0].Rev != 2 || versions[1].Rev != 3 || versions[2].Rev != 4 {
+		t.Fatalf("expected revisions 2-4 after the merge, got %+v", versions)
+	}
+	for rev, want := range map[int]string{2: "old 2", 3: "new 1", 4: "new 2"} {
+		if content, _, err := store.Read("atoms/a.md", rev); err != nil || string(content) != want {
+			t.Fatalf("expected revision %d to be %q, got %q, %v", rev, want, content, err)
+		}
+	}
+	if versions, _ := store.Versions("inbox/a.md"); len(versions) != 0 {
+		t.Fatalf("expected no history at the old path, got %+v", versions)
+	}
+	if got := countObjects(t, vault); got != 3 {
+		t.Fatalf("expected the pruned object to be removed, got %d objects", got)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	vault := t.TempDir()
+	store, err := FromConfig(vault, config.HistoryConfig{})
+	if err != nil || store != nil {
+		t.Fatalf("expected no store when disabled, got %v, %v", store, err)
+	}
+	if err := store.Save("edit", "note.md", []byte("x")); err != nil {
+		t.Fatalf("expected a nil store to ignore saves, got %v", err)
+	}
+
+	store, err = FromConfig(vault, config.HistoryConfig{Enabled: true, MaxVersions: 5, MaxAge: "4w"})
+	if err != nil {
+		t.Fatalf("FromConfig returned error: %v", err)
+	}
+	if store.opts.MaxVersions != 5 || store.opts.MaxAge != 28*24*time.Hour {
+		t.Fatalf("unexpected options %+v", store.opts)
+	}
+	if _, err := FromConfig(vault, config.HistoryConfig{Enabled: true, MaxAge: "soon"}); err == nil {
+		t.Fatalf("expected an invalid max_age to fail")
+	}
+}
+
+func TestWithExt(t *testing.T) {
+	for name, want := range map[string]string{
+		"atoms/idea":    "atoms/idea.md",
+		"atoms/idea.md": "atoms/idea.md",
+		"v1.2":          "v1.2.md",
+	} {
+		if got := WithExt(name); got != want {
+			t.Fatalf("WithExt(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -3,9 +3,12 @@ package state
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Paintersrp/an/internal/services/inbox"
 )
 
 // IndexStatsMsg notifies subscribers that the root status line was refreshed
@@ -14,16 +17,16 @@ type IndexStatsMsg struct {
 	Line string
 }
 
-// IndexHeartbeatCmd polls the index service for lightweight statistics,
-// updates the shared root status line, and returns a message that consumers can
-// use to trigger rerenders.
+// IndexHeartbeatCmd polls the index service for lightweight statistics and
+// counts the inbox, updates the shared root status line, and returns a message
+// that consumers can use to trigger rerenders.
 func (s *State) IndexHeartbeatCmd() tea.Cmd {
 	if s == nil {
 		return nil
 	}
 
 	return func() tea.Msg {
-		line := formatIndexStatus(s.Index)
+		line := joinStatus(formatIndexStatus(s.Index), s.formatInboxStatus())
 		if s.RootStatus != nil {
 			s.RootStatus.Set(line)
 		}
@@ -48,3 +51,59 @@ func formatIndexStatus(svc IndexService) string {
 func formatRebuildTime(t time.Time) string {
 	return t.Local().Format("15:04")
 }
+
+// formatInboxStatus reports how many notes are waiting in the inbox, or
+// nothing when the inbox is empty.
+func (s *State) formatInboxStatus() string {
+	if s.Handler == nil || s.Workspace == nil {
+		return ""
+	}
+	count, err := s.inboxCount.get(inbox.NewService(s.Handler, s.Workspace.Inbox).Count)
+	if err != nil || count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Inbox %d", count)
+}
+
+// inboxCounter caches the inbox count between heartbeats so they do not walk
+// the vault every time. The vault watcher invalidates it when a note in the
+// inbox changes.
+type inboxCounter struct {
+	mu    sync.Mutex
+	count int
+	valid bool
+}
+
+func (c *inboxCounter) invalidate() {
+	c.mu.Lock()
+	c.valid = false
+	c.mu.Unlock()
+}
+
+// get returns the cached count, calling count when there is none. Without a
+// cache every call counts.
+func (c *inboxCounter) get(count func() (int, error)) (int, error) {
+	if c == nil {
+		return count()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.valid {
+		n, err := count()
+		if err != nil {
+			return 0, err
+		}
+		c.count, c.valid = n, true
+	}
+	return c.count, nil
+}
+
+func joinStatus(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " · ")
+}
@@ -1,9 +1,13 @@
 package state
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/search"
 	indexsvc "github.com/Paintersrp/an/internal/services/index"
 )
@@ -95,3 +99,68 @@ func TestIndexHeartbeatUpdatesStatus(t *testing.T) {
 		t.Fatalf("expected root status %q, got %q", want, got)
 	}
 }
+
+func TestIndexHeartbeatIncludesInboxCount(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	for _, name := range []string{"one.md", "two.md"} {
+		path := filepath.Join(vault, "inbox", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create inbox: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to write note: %v", err)
+		}
+	}
+
+	st := &State{
+		RootStatus: &RootStatus{},
+		Index:      stubIndexService{stats: indexsvc.Stats{Pending: 1}},
+		Handler:    handler.NewFileHandler(vault),
+		Workspace:  &config.Workspace{VaultDir: vault},
+	}
+
+	msg := st.IndexHeartbeatCmd()()
+	want := "Idx: pending 1 · Inbox 2"
+	if got := msg.(IndexStatsMsg).Line; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestIndexHeartbeatCachesInboxCount(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	inboxDir := filepath.Join(vault, "inbox")
+	if err := os.MkdirAll(inboxDir, 0o755); err != nil {
+		t.Fatalf("failed to create inbox: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(inboxDir, "one.md"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	st := &State{
+		RootStatus: &RootStatus{},
+		Handler:    handler.NewFileHandler(vault),
+		Workspace:  &config.Workspace{VaultDir: vault},
+		inboxCount: &inboxCounter{},
+	}
+	line := func() string {
+		return st.IndexHeartbeatCmd()().(IndexStatsMsg).Line
+	}
+
+	if got := line(); got != "Inbox 1" {
+		t.Fatalf("expected %q, got %q", "Inbox 1", got)
+	}
+	if err := os.WriteFile(filepath.Join(inboxDir, "two.md"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+	if got := line(); got != "Inbox 1" {
+		t.Fatalf("expected the cached count until invalidated, got %q", got)
+	}
+	st.inboxCount.invalidate()
+	if got := line(); got != "Inbox 2" {
+		t.Fatalf("expected %q after invalidation, got %q", "Inbox 2", got)
+	}
+}
@@ -14,8 +14,10 @@ import (
 	"github.com/Paintersrp/an/internal/constants"
 	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/search"
+	"github.com/Paintersrp/an/internal/services/inbox"
 	indexsvc "github.com/Paintersrp/an/internal/services/index"
 	taskidx "github.com/Paintersrp/an/internal/services/tasks/index"
+	"github.com/Paintersrp/an/internal/snapshot"
 	"github.com/Paintersrp/an/internal/templater"
 	"github.com/Paintersrp/an/internal/views"
 )
@@ -35,6 +37,8 @@ type State struct {
 	Index         IndexService
 	Tasks         TaskIndexService
 	RootStatus    *RootStatus
+
+	inboxCount *inboxCounter
 }
 
 type RootStatus struct {
@@ -106,6 +110,11 @@ func NewState(workspaceOverride string) (*State, error) {
 	}
 
 	h := handler.NewFileHandler(ws.VaultDir)
+	history, err := snapshot.FromConfig(ws.VaultDir, ws.History)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure history: %w", err)
+	}
+	h.SetHistory(history)
 	vm, err := views.NewViewManager(h, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure views: %w", err)
@@ -122,7 +131,13 @@ func NewState(workspaceOverride string) (*State, error) {
 	}
 	indexService := indexsvc.NewService(ws.VaultDir, searchCfg)
 	taskIndex := taskidx.NewService(ws.VaultDir)
+	t.UseVaultSources(indexService, taskIndex)
+	inboxNotes := inbox.NewService(h, ws.Inbox)
+	inboxCount := &inboxCounter{}
 	watcher.OnChange(func(rel string) {
+		if inboxNotes.Contains(rel) {
+			inboxCount.invalidate()
+		}
 		if indexService != nil {
 			indexService.QueueUpdate(rel)
 		}
@@ -154,6 +169,7 @@ func NewState(workspaceOverride string) (*State, error) {
 		Index:         indexService,
 		Tasks:         taskIndex,
 		RootStatus:    &RootStatus{},
+		inboxCount:    inboxCount,
 	}
 
 	watcher.SetHeartbeat(func() tea.Cmd {
@@ -0,0 +1,294 @@
+package templater
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Template source layers, listed from highest to lowest precedence.
+const (
+	SourceWorkspace = "workspace"
+	SourceUser      = "user"
+	SourceEmbedded  = "embedded"
+)
+
+var manifestNamePattern = regexp.MustCompile(`(?m)^name:[ \t]*.*$`)
+
+// TemplateInfo describes a registered template and where it was loaded from.
+type TemplateInfo struct {
+	Name     string
+	Source   string
+	Path     string
+	Manifest TemplateManifest
+	// Chain lists the template followed by every ancestor reachable through
+	// `extends`, nearest first.
+	Chain []string
+}
+
+// List returns information about every registered template sorted by name.
+func (t *Templater) List() []TemplateInfo {
+	infos := make([]TemplateInfo, 0, len(t.templates))
+	for _, name := range t.Templates() {
+		info, err := t.Info(name)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Info returns the source layer, manifest, and extends chain for a template.
+func (t *Templater) Info(name string) (TemplateInfo, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return TemplateInfo{}, fmt.Errorf("template %q not found", name)
+	}
+	return TemplateInfo{
+		Name:     name,
+		Source:   t.sourceOf(tmpl),
+		Path:     tmpl.FilePath,
+		Manifest: tmpl.Manifest,
+		Chain:    t.extendsChain(name, make(map[string]bool)),
+	}, nil
+}
+
+// Raw returns the template file exactly as stored, including its manifest.
+func (t *Templater) Raw(name string) (string, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return "", fmt.Errorf("template %q not found", name)
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if t.sourceOf(tmpl) == SourceEmbedded {
+		data, err = fs.ReadFile(embeddedTemplates, tmpl.FilePath)
+	} else {
+		data, err = os.ReadFile(tmpl.FilePath)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Resolved returns the template content and manifest after merging every
+// template listed in `extends`.
+func (t *Templater) Resolved(name string) (string, TemplateManifest, error) {
+	if _, ok := t.templates[name]; !ok {
+		return "", TemplateManifest{}, fmt.Errorf("template %q not found", name)
+	}
+	resolved, err := t.resolveTemplate(name)
+	if err != nil {
+		return "", TemplateManifest{}, err
+	}
+	return resolved.Content, resolved.Manifest, nil
+}
+
+// WorkspaceTemplateDir returns the directory holding workspace templates, or
+// an empty string when no workspace is configured.
+func (t *Templater) WorkspaceTemplateDir() string {
+	if t.vault == "" {
+		return ""
+	}
+	return filepath.Join(t.vault, ".an", "templates")
+}
+
+// Validate checks a template's manifest fields and performs a dry render with
+// sample data built from the manifest.
+func (t *Templater) Validate(name string) error {
+	content, manifest, err := t.Resolved(name)
+	if err != nil {
+		return err
+	}
+
+	var problems []error
+	known, computed := manifest.exprVars(), manifest.computedKeys()
+
+	seen := make(map[string]bool, len(manifest.Fields))
+	for _, field := range manifest.Fields {
+		if seen[field.Key] {
+			problems = append(problems, fmt.Errorf("field %q is declared more than once", field.Key))
+		}
+		seen[field.Key] = true
+		if err := validateFieldDefinition(field); err != nil {
+			problems = append(problems, err)
+		}
+		if err := validateFieldExpressions(field, known, computed); err != nil {
+			problems = append(problems, err)
+		}
+		if field.Default != "" && len(field.Options) > 0 && !containsString(field.Options, field.Default) {
+			problems = append(problems, fmt.Errorf("field %q default %q is not one of its options", field.Key, field.Default))
+		}
+		for _, value := range field.Defaults {
+			if len(field.Options) > 0 && !containsString(field.Options, value) {
+				problems = append(problems, fmt.Errorf("field %q default %q is not one of its options", field.Key, value))
+			}
+		}
+	}
+
+	if len(manifest.Scaffold) > 0 {
+		if _, err := t.ScaffoldFiles(name, "Sample"); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	if strings.TrimSpace(content) != "" {
+		if _, err := t.Execute(name, SampleData(manifest)); err != nil {
+			problems = append(problems, fmt.Errorf("render: %w", err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// SampleData builds placeholder template data for previews and dry renders.
+// Manifest fields receive their parsed defaults, the first option, or a
+// placeholder matching the field type.
+func SampleData(manifest TemplateManifest) TemplateData {
+	metadata := make(map[string]interface{}, len(manifest.Fields))
+	for _, field := range manifest.Fields {
+		if field.IsComputed() {
+			continue
+		}
+		var values []string
+		switch {
+		case len(field.Defaults) > 0:
+			values = field.Defaults
+		case field.Default != "":
+			values = []string{field.Default}
+		case len(field.Options) > 0:
+			values = field.Options[:1]
+		default:
+			values = []string{sampleValue(field)}
+		}
+		if field.Multi || len(field.Defaults) > 0 {
+			if typed, err := ParseFieldValues(field, values); err == nil {
+				metadata[field.Key] = typed
+				continue
+			}
+		} else if typed, err := ParseFieldValue(field, values[0]); err == nil {
+			metadata[field.Key] = typed
+			continue
+		}
+		metadata[field.Key] = values[0]
+	}
+	// Placeholder answers may not satisfy every expression, so computed
+	// fields are best effort here.
+	_ = ApplyComputedFields(manifest, metadata)
+	return TemplateData{
+		Title:    "sample-note",
+		Date:     time.Now().Format("2006-01-02"),
+		Upstream: "sample-upstream",
+		Content:  "Sample content.",
+		Tags:     []string{"sample"},
+		Links:    []string{"sample-link"},
+		Metadata: metadata,
+	}
+}
+
+func sampleValue(field TemplateField) string {
+	switch field.Kind() {
+	case FieldTypeDate:
+		return "today"
+	case FieldTypeDateTime:
+		return time.Now().Format("2006-01-02 15:04")
+	case FieldTypeNumber:
+		switch {
+		case field.Min != nil:
+			return formatNumber(*field.Min)
+		case field.Max != nil:
+			return formatNumber(*field.Max)
+		}
+		return "1"
+	case FieldTypeBool:
+		return "yes"
+	case FieldTypeURL:
+		return "https://example.com"
+	}
+	return "sample"
+}
+
+// Scaffold returns the starting content for a new template. When from names
+// an existing template its raw content is copied and the manifest renamed;
+// otherwise a minimal template with an empty manifest is produced.
+func (t *Templater) Scaffold(name, from string) (string, error) {
+	if strings.TrimSpace(from) == "" {
+		return fmt.Sprintf(`{{/* an:manifest
+name: %s
+description: ""
+fields: []
+*/}}
+---
+title: {{.Title}}
+created: {{.Date}}
+tags:
+{{- range .Tags}}
+  - {{.}}
+{{- end}}
+---
+
+{{.Content}}
+`, name), nil
+	}
+
+	raw, err := t.Raw(from)
+	if err != nil {
+		return "", err
+	}
+	loc := manifestBlockPattern.FindStringSubmatchIndex(raw)
+	if loc == nil {
+		return fmt.Sprintf("{{/* an:manifest\nname: %s\n*/}}\n%s", name, raw), nil
+	}
+	block := raw[loc[2]:loc[3]]
+	if manifestNamePattern.MatchString(block) {
+		block = manifestNamePattern.ReplaceAllLiteralString(block, "name: "+name)
+	} else {
+		block = "name: " + name + "\n" + strings.TrimLeft(block, "\r\n")
+	}
+	return raw[:loc[2]] + block + raw[loc[3]:], nil
+}
+
+func (t *Templater) sourceOf(tmpl SingleTemplate) string {
+	if !filepath.IsAbs(tmpl.FilePath) {
+		return SourceEmbedded
+	}
+	if dir := t.WorkspaceTemplateDir(); dir != "" {
+		if rel, err := filepath.Rel(dir, tmpl.FilePath); err == nil && !strings.HasPrefix(rel, "..") {
+			return SourceWorkspace
+		}
+	}
+	return SourceUser
+}
+
+func (t *Templater) extendsChain(name string, visited map[string]bool) []string {
+	if visited[name] {
+		return nil
+	}
+	visited[name] = true
+	chain := []string{name}
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return chain
+	}
+	for _, parent := range tmpl.Manifest.Extends {
+		chain = append(chain, t.extendsChain(parent, visited)...)
+	}
+	return chain
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package templater
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/config"
+)
+
+func newWorkspaceTemplater(t *testing.T, files map[string]string) *Templater {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	vault := t.TempDir()
+	dir := filepath.Join(vault, ".an", "templates")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("failed to create template directory: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name+".tmpl"), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write template %s: %v", name, err)
+		}
+		if _, existed := AvailableTemplates[name]; !existed {
+			name := name
+			t.Cleanup(func() { delete(AvailableTemplates, name) })
+		}
+	}
+
+	tmpl, err := NewTemplater(&config.Workspace{VaultDir: vault})
+	if err != nil {
+		t.Fatalf("NewTemplater returned error: %v", err)
+	}
+	return tmpl
+}
+
+func TestListReportsSourceAndChain(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"meeting": "{{/* an:manifest\ndescription: Meeting notes\nextends:\n  - project-release\n*/}}\nAgenda\n",
+	})
+
+	infos := make(map[string]TemplateInfo)
+	for _, info := range tmpl.List() {
+		infos[info.Name] = info
+	}
+
+	meeting, ok := infos["meeting"]
+	if !ok {
+		t.Fatalf("expected meeting template in list")
+	}
+	if meeting.Source != SourceWorkspace {
+		t.Fatalf("expected workspace source, got %q", meeting.Source)
+	}
+	if meeting.Manifest.Description != "Meeting notes" {
+		t.Fatalf("unexpected description %q", meeting.Manifest.Description)
+	}
+	if got := strings.Join(meeting.Chain, ","); got != "meeting,project-release,project" {
+		t.Fatalf("unexpected extends chain %q", got)
+	}
+	if zet := infos["zet"]; zet.Source != SourceEmbedded {
+		t.Fatalf("expected embedded source for zet, got %q", zet.Source)
+	}
+}
+
+func TestScaffoldFromExistingRenamesManifest(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"meeting": "{{/* an:manifest\nname: meeting\ndescription: Meeting notes\n*/}}\nAgenda\n",
+	})
+
+	content, err := tmpl.Scaffold("standup", "meeting")
+	if err != nil {
+		t.Fatalf("Scaffold returned error: %v", err)
+	}
+	if !strings.Contains(content, "name: standup") || strings.Contains(content, "name: meeting") {
+		t.Fatalf("expected manifest to be renamed, got:\n%s", content)
+	}
+	if !strings.Contains(content, "description: Meeting notes") || !strings.HasSuffix(content, "Agenda\n") {
+		t.Fatalf("expected copied template content, got:\n%s", content)
+	}
+
+	blank, err := tmpl.Scaffold("blank", "")
+	if err != nil {
+		t.Fatalf("Scaffold returned error: %v", err)
+	}
+	manifest, _, err := parseManifest("blank", blank)
+	if err != nil || manifest.Name != "blank" {
+		t.Fatalf("expected parseable blank manifest, got %+v (%v)", manifest, err)
+	}
+}
+
+func TestValidateReportsFieldAndRenderProblems(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"good": "{{/* an:manifest\nfields:\n  - key: status\n    options: [open, closed]\n    default: open\n*/}}\nStatus: {{index .Metadata \"status\"}}\n",
+		"bad":  "{{/* an:manifest\nfields:\n  - key: status\n    options: [open, closed]\n    default: pending\n*/}}\n{{ .Missing }}\n",
+	})
+
+	if err := tmpl.Validate("good"); err != nil {
+		t.Fatalf("expected good template to validate, got %v", err)
+	}
+
+	err := tmpl.Validate("bad")
+	if err == nil {
+		t.Fatalf("expected bad template to fail validation")
+	}
+	message := err.Error()
+	if !strings.Contains(message, `default "pending"`) || !strings.Contains(message, "render:") {
+		t.Fatalf("expected default and render problems, got %q", message)
+	}
+
+	for _, name := range tmpl.Templates() {
+		if name == "good" || name == "bad" {
+			continue
+		}
+		if err := tmpl.Validate(name); err != nil {
+			t.Fatalf("expected built-in template %s to validate, got %v", name, err)
+		}
+	}
+}
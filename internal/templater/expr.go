@@ -0,0 +1,660 @@
+package templater
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Field expressions power the `when:` and `compute:` manifest keys. The
+// language is intentionally small:
+//
+//	risk == "high"                 comparisons: == != < <= > >=
+//	status in ["open", "blocked"]  membership in a literal list or list field
+//	billable && !internal          logic: && || ! (or and/or/not)
+//	created + effort-days          arithmetic: + - * /; adding a number to a
+//	                               date shifts it by that many days
+//
+// Identifiers refer to earlier field answers and may contain hyphens, so
+// `effort-days` names one field; a hyphen followed by a letter continues the
+// identifier, so subtracting one field from another needs spaces around `-`.
+// `today`, `now`, and `created` are always available. Missing answers
+// evaluate to nil, which is falsy and makes arithmetic produce nil.
+
+type exprNode interface {
+	eval(vars map[string]any) (any, error)
+}
+
+type (
+	literalNode struct{ value any }
+	identNode   struct{ name string }
+	listNode    struct{ items []exprNode }
+	notNode     struct{ operand exprNode }
+	negNode     struct{ operand exprNode }
+	binaryNode  struct {
+		op          string
+		left, right exprNode
+	}
+)
+
+// Expression is a parsed field expression.
+type Expression struct {
+	source string
+	root   exprNode
+	idents []string
+}
+
+// ParseExpression parses a `when:` or `compute:` expression.
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenizeExpr(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in expression %q", p.tokens[p.pos].text, source)
+	}
+	sort.Strings(p.idents)
+	return &Expression{source: source, root: root, idents: p.idents}, nil
+}
+
+// Identifiers returns the distinct variable names referenced by the expression.
+func (e *Expression) Identifiers() []string {
+	return append([]string(nil), e.idents...)
+}
+
+// Eval evaluates the expression against the provided variables.
+func (e *Expression) Eval(vars map[string]any) (any, error) {
+	value, err := e.root.eval(withBuiltins(vars))
+	if err != nil {
+		return nil, fmt.Errorf("evaluate %q: %w", e.source, err)
+	}
+	return value, nil
+}
+
+// Truthy evaluates the expression and reports whether the result is truthy.
+func (e *Expression) Truthy(vars map[string]any) (bool, error) {
+	value, err := e.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+// builtinExprVars lists the variables available to every expression.
+var builtinExprVars = []string{"created", "now", "today"}
+
+func withBuiltins(vars map[string]any) map[string]any {
+	now := nowFunc()
+	today := Date{truncateDay(now)}
+	merged := map[string]any{"today": today, "now": now, "created": today}
+	for key, value := range vars {
+		merged[key] = value
+	}
+	return merged
+}
+
+func (n literalNode) eval(map[string]any) (any, error) { return n.value, nil }
+
+func (n identNode) eval(vars map[string]any) (any, error) {
+	value := vars[n.name]
+	if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func (n listNode) eval(vars map[string]any) (any, error) {
+	items := make([]any, 0, len(n.items))
+	for _, item := range n.items {
+		value, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+	}
+	return items, nil
+}
+
+func (n notNode) eval(vars map[string]any) (any, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(value), nil
+}
+
+func (n negNode) eval(vars map[string]any) (any, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	number, ok := toNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %v", value)
+	}
+	return normalizeNumber(-number), nil
+}
+
+func (n binaryNode) eval(vars map[string]any) (any, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(vars)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(vars)
+		return truthy(right), err
+	}
+
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	case "in":
+		return contains(right, left), nil
+	case "<", "<=", ">", ">=":
+		if left == nil || right == nil {
+			return false, nil
+		}
+		cmp, err := compareValues(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	}
+	return arithmetic(n.op, left, right)
+}
+
+func arithmetic(op string, left, right any) (any, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	if ts, ok := toTime(left); ok {
+		if days, ok := toNumber(right); ok && (op == "+" || op == "-") {
+			if op == "-" {
+				days = -days
+			}
+			shifted := ts.AddDate(0, 0, int(days))
+			if _, isDate := left.(Date); isDate {
+				return Date{shifted}, nil
+			}
+			return shifted, nil
+		}
+		if other, ok := toTime(right); ok && op == "-" {
+			return int(math.Round(ts.Sub(other).Hours() / 24)), nil
+		}
+	}
+	if days, ok := toNumber(left); ok && op == "+" {
+		if date, isDate := right.(Date); isDate {
+			return Date{date.AddDate(0, 0, int(days))}, nil
+		}
+	}
+
+	a, okA := toNumber(left)
+	b, okB := toNumber(right)
+	if okA && okB {
+		switch op {
+		case "+":
+			return normalizeNumber(a + b), nil
+		case "-":
+			return normalizeNumber(a - b), nil
+		case "*":
+			return normalizeNumber(a * b), nil
+		case "/":
+			if b == 0 {
+				return nil, errors.New("division by zero")
+			}
+			return normalizeNumber(a / b), nil
+		}
+	}
+
+	if op == "+" {
+		if sa, ok := left.(string); ok {
+			return sa + fmt.Sprint(right), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot apply %s to %v and %v", op, left, right)
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return strings.TrimSpace(v) != ""
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	case []string:
+		return len(v) > 0
+	case []any:
+		return len(v) > 0
+	}
+	return true
+}
+
+func valuesEqual(left, right any) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	if a, ok := toNumber(left); ok {
+		if b, ok := toNumber(right); ok {
+			return a == b
+		}
+	}
+	if a, ok := toTime(left); ok {
+		if b, ok := toTime(right); ok {
+			return a.Equal(b)
+		}
+	}
+	return strings.EqualFold(fmt.Sprint(left), fmt.Sprint(right))
+}
+
+func contains(collection, value any) bool {
+	switch items := collection.(type) {
+	case []any:
+		for _, item := range items {
+			if valuesEqual(item, value) {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range items {
+			if valuesEqual(item, value) {
+				return true
+			}
+		}
+	case string:
+		return value != nil && strings.Contains(strings.ToLower(items), strings.ToLower(fmt.Sprint(value)))
+	}
+	return false
+}
+
+func compareValues(left, right any) (int, error) {
+	if a, ok := toNumber(left); ok {
+		if b, ok := toNumber(right); ok {
+			switch {
+			case a < b:
+				return -1, nil
+			case a > b:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if a, ok := toTime(left); ok {
+		if b, ok := toTime(right); ok {
+			return a.Compare(b), nil
+		}
+	}
+	if a, ok := left.(string); ok {
+		if b, ok := right.(string); ok {
+			return strings.Compare(a, b), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %v and %v", left, right)
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func toTime(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case Date:
+		return v.Time, true
+	case time.Time:
+		return v, true
+	case string:
+		if date, err := parseDate(strings.TrimSpace(v)); err == nil {
+			return date.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func normalizeNumber(number float64) any {
+	if number == math.Trunc(number) && math.Abs(number) < math.MaxInt32 {
+		return int(number)
+	}
+	return number
+}
+
+type exprToken struct {
+	kind string // "num", "str", "ident", "op"
+	text string
+}
+
+func tokenizeExpr(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in expression %q", source)
+			}
+			tokens = append(tokens, exprToken{kind: "str", text: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: "num", text: string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (isIdentRune(runes[end]) || runes[end] == '-' && end+1 < len(runes) && isIdentStart(runes[end+1])) {
+				end++
+			}
+			tokens = append(tokens, exprToken{kind: "ident", text: string(runes[i:end])})
+			i = end
+		default:
+			if i+1 < len(runes) {
+				switch pair := string(runes[i : i+2]); pair {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					tokens = append(tokens, exprToken{kind: "op", text: pair})
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>!+-*/()[],", r) {
+				return nil, fmt.Errorf("unexpected character %q in expression %q", r, source)
+			}
+			tokens = append(tokens, exprToken{kind: "op", text: string(r)})
+			i++
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+	return tokens, nil
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentRune(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+	idents []string
+}
+
+func (p *exprParser) peek() (exprToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return exprToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept consumes the next token when it is an operator or keyword in ops and
+// returns its canonical operator.
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	tok, ok := p.peek()
+	if !ok {
+		return "", false
+	}
+	text := tok.text
+	if tok.kind == "ident" {
+		switch strings.ToLower(text) {
+		case "and":
+			text = "&&"
+		case "or":
+			text = "||"
+		case "not":
+			text = "!"
+		case "in":
+			text = "in"
+		default:
+			return "", false
+		}
+	} else if tok.kind != "op" {
+		return "", false
+	}
+	for _, op := range ops {
+		if text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	if _, ok := p.accept("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	if _, ok := p.accept("["); ok {
+		var items []exprNode
+		for {
+			if _, ok := p.accept("]"); ok {
+				return listNode{items: items}, nil
+			}
+			if len(items) > 0 {
+				if _, ok := p.accept(","); !ok {
+					return nil, errors.New("expected , or ] in list")
+				}
+			}
+			item, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	}
+
+	p.pos++
+	switch tok.kind {
+	case "num":
+		number, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok.text)
+		}
+		return literalNode{value: normalizeNumber(number)}, nil
+	case "str":
+		return literalNode{value: tok.text}, nil
+	case "ident":
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "nil", "null":
+			return literalNode{value: nil}, nil
+		}
+		p.idents = appendIdent(p.idents, tok.text)
+		return identNode{name: tok.text}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func appendIdent(idents []string, name string) []string {
+	for _, existing := range idents {
+		if existing == name {
+			return idents
+		}
+	}
+	return append(idents, name)
+}
@@ -0,0 +1,166 @@
+package templater
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpressionEvaluation(t *testing.T) {
+	restore := nowFunc
+	nowFunc = func() time.Time { return time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { nowFunc = restore })
+
+	vars := map[string]any{
+		"risk":        "high",
+		"effort_days": 3,
+		"tags":        []string{"release", "infra"},
+		"billable":    false,
+		"notes":       "",
+	}
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{expr: `risk == "high"`, want: true},
+		{expr: `risk == 'HIGH'`, want: true},
+		{expr: `risk != "high" || effort_days >= 3`, want: true},
+		{expr: `risk in ["low", "medium"]`, want: false},
+		{expr: `"infra" in tags`, want: true},
+		{expr: `!billable and not notes`, want: true},
+		{expr: `missing == "x"`, want: false},
+		{expr: `effort_days * 2 + 1`, want: 7},
+		{expr: `(effort_days - 1) / 4`, want: 0.5},
+		{expr: `created + effort_days`, want: Date{time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)}},
+		{expr: `today - 1`, want: Date{time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)}},
+		{expr: `"2024-03-20" - today`, want: 6},
+		{expr: `missing + 1`, want: nil},
+	}
+
+	for _, tc := range tests {
+		expr, err := ParseExpression(tc.expr)
+		if err != nil {
+			t.Fatalf("ParseExpression(%q) returned error: %v", tc.expr, err)
+		}
+		got, err := expr.Eval(vars)
+		if err != nil {
+			t.Fatalf("Eval(%q) returned error: %v", tc.expr, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Eval(%q) = %#v, want %#v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	for _, src := range []string{``, `risk ==`, `(risk`, `"open`, `risk @ 1`, `[1, 2`} {
+		if _, err := ParseExpression(src); err == nil {
+			t.Fatalf("expected parse error for %q", src)
+		}
+	}
+
+	expr, err := ParseExpression(`risk == "high" && effort > 2 && risk != "low"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(expr.Identifiers(), ","); got != "effort,risk" {
+		t.Fatalf("unexpected identifiers %q", got)
+	}
+}
+
+func TestExpressionHyphenatedIdentifiers(t *testing.T) {
+	restore := nowFunc
+	nowFunc = func() time.Time { return time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { nowFunc = restore })
+
+	expr, err := ParseExpression(`created + effort-days`)
+	if err != nil {
+		t.Fatalf("ParseExpression returned error: %v", err)
+	}
+	if got := strings.Join(expr.Identifiers(), ","); got != "created,effort-days" {
+		t.Fatalf("unexpected identifiers %q", got)
+	}
+	got, err := expr.Eval(map[string]any{"effort-days": 3})
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if want := (Date{time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)}); got != want {
+		t.Fatalf("Eval = %#v, want %#v", got, want)
+	}
+
+	expr, err = ParseExpression(`today-1`)
+	if err != nil {
+		t.Fatalf("ParseExpression returned error: %v", err)
+	}
+	if got, _ := expr.Eval(nil); got != (Date{time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)}) {
+		t.Fatalf("expected a hyphen before a number to subtract, got %#v", got)
+	}
+
+	manifest := TemplateManifest{Fields: []TemplateField{
+		{Key: "effort-days", Type: "number"},
+		{Key: "due", Type: "date", Compute: "created + effort-dayz"},
+	}}
+	err = ApplyComputedFields(manifest, map[string]any{"effort-days": 3})
+	if err == nil || !strings.Contains(err.Error(), `unknown field "effort-dayz"`) {
+		t.Fatalf("expected an undeclared field to be reported, got %v", err)
+	}
+}
+
+func TestApplyComputedFields(t *testing.T) {
+	restore := nowFunc
+	nowFunc = func() time.Time { return time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { nowFunc = restore })
+
+	manifest := TemplateManifest{Fields: []TemplateField{
+		{Key: "risk"},
+		{Key: "effort_days", Type: "number"},
+		{Key: "due", Type: "date", Compute: "created + effort_days"},
+		{Key: "summary", Compute: `risk + " risk"`},
+		{Key: "escalate", Type: "bool", Compute: "true", When: `risk == "high"`},
+	}}
+
+	answers := map[string]any{"risk": "low", "effort_days": 2}
+	if err := ApplyComputedFields(manifest, answers); err != nil {
+		t.Fatalf("ApplyComputedFields returned error: %v", err)
+	}
+	if due, ok := answers["due"].(Date); !ok || due.String() != "2024-03-16" {
+		t.Fatalf("expected computed due date, got %#v", answers["due"])
+	}
+	if answers["summary"] != "low risk" {
+		t.Fatalf("expected computed summary, got %#v", answers["summary"])
+	}
+	if _, ok := answers["escalate"]; ok {
+		t.Fatalf("expected escalate to be skipped when risk is low")
+	}
+
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"cond": "{{/* an:manifest\nfields:\n  - key: risk\n  - key: rollback\n    when: risk == \"high\" && owner\n*/}}\nBody\n",
+	})
+	if err := tmpl.Validate("cond"); err == nil || !strings.Contains(err.Error(), `unknown field "owner"`) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestWhenCannotReferenceComputedFields(t *testing.T) {
+	manifest := TemplateManifest{Fields: []TemplateField{
+		{Key: "effort_days", Type: "number"},
+		{Key: "big", Type: "bool", Compute: "effort_days > 5"},
+		{Key: "owner", When: "big"},
+		{Key: "escalate", Type: "bool", Compute: "true", When: "big"},
+	}}
+	err := manifest.CheckExpressions()
+	if err == nil || !strings.Contains(err.Error(), `field "owner" when references computed field "big"`) {
+		t.Fatalf("expected a prompted field conditioned on a computed field to be rejected, got %v", err)
+	}
+	if strings.Contains(err.Error(), `field "escalate"`) {
+		t.Fatalf("expected computed fields to see earlier computed values, got %v", err)
+	}
+
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"cond": "{{/* an:manifest\nfields:\n  - key: effort_days\n    type: number\n  - key: big\n    type: bool\n    compute: effort_days > 5\n  - key: owner\n    when: big\n*/}}\nBody\n",
+	})
+	if err := tmpl.Validate("cond"); err == nil || !strings.Contains(err.Error(), `computed field "big"`) {
+		t.Fatalf("expected validate to report the computed reference, got %v", err)
+	}
+}
@@ -0,0 +1,495 @@
+package templater
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field types understood by template manifests. A type written as
+// "regex:<pattern>" is a text field validated against the pattern.
+const (
+	FieldTypeText     = "text"
+	FieldTypeSelect   = "select"
+	FieldTypeDate     = "date"
+	FieldTypeDateTime = "datetime"
+	FieldTypeNumber   = "number"
+	FieldTypeBool     = "bool"
+	FieldTypeURL      = "url"
+	FieldTypeNoteLink = "note-link"
+	FieldTypeTag      = "tag"
+	FieldTypeRegex    = "regex"
+)
+
+const regexTypePrefix = "regex:"
+
+var (
+	tagPattern      = regexp.MustCompile(`^[\p{L}\p{N}_/-]+$`)
+	dateLayouts     = []string{"2006-01-02", "20060102"}
+	dateTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02 15:04:05"}
+)
+
+// Date is a calendar date without a time of day. It renders as YYYY-MM-DD in
+// templates and is written to front matter as an unquoted YAML date.
+type Date struct {
+	time.Time
+}
+
+func (d Date) String() string {
+	return d.Format("2006-01-02")
+}
+
+// MarshalYAML writes the date as a YAML timestamp without a time component.
+func (d Date) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: d.String()}, nil
+}
+
+// Kind returns the normalized field type. Fields that declare options without
+// a type are treated as selects, and "regex:" types report FieldTypeRegex.
+func (f TemplateField) Kind() string {
+	raw := strings.TrimSpace(f.Type)
+	if strings.HasPrefix(strings.ToLower(raw), regexTypePrefix) {
+		return FieldTypeRegex
+	}
+	switch strings.ToLower(raw) {
+	case "date":
+		return FieldTypeDate
+	case "datetime", "date-time", "timestamp":
+		return FieldTypeDateTime
+	case "number", "int", "integer", "float":
+		return FieldTypeNumber
+	case "bool", "boolean":
+		return FieldTypeBool
+	case "url":
+		return FieldTypeURL
+	case "note-link", "wikilink":
+		return FieldTypeNoteLink
+	case "tag":
+		return FieldTypeTag
+	case "select":
+		return FieldTypeSelect
+	}
+	if len(f.Options) > 0 {
+		return FieldTypeSelect
+	}
+	return FieldTypeText
+}
+
+// Pattern compiles the expression of a "regex:" field. Other fields return nil.
+func (f TemplateField) Pattern() (*regexp.Regexp, error) {
+	if f.Kind() != FieldTypeRegex {
+		return nil, nil
+	}
+	expr := strings.TrimSpace(f.Type)[len(regexTypePrefix):]
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", expr, err)
+	}
+	return pattern, nil
+}
+
+// Hint describes the expected input format for typed fields, or returns an
+// empty string for free text.
+func (f TemplateField) Hint() string {
+	switch f.Kind() {
+	case FieldTypeDate:
+		return "YYYY-MM-DD or today/tomorrow/yesterday"
+	case FieldTypeDateTime:
+		return "YYYY-MM-DD HH:MM"
+	case FieldTypeNumber:
+		switch {
+		case f.Min != nil && f.Max != nil:
+			return fmt.Sprintf("number %s-%s", formatNumber(*f.Min), formatNumber(*f.Max))
+		case f.Min != nil:
+			return fmt.Sprintf("number ≥ %s", formatNumber(*f.Min))
+		case f.Max != nil:
+			return fmt.Sprintf("number ≤ %s", formatNumber(*f.Max))
+		}
+		return "number"
+	case FieldTypeBool:
+		return "y/n"
+	case FieldTypeURL:
+		return "URL"
+	case FieldTypeNoteLink:
+		return "note name"
+	case FieldTypeTag:
+		return "tag"
+	case FieldTypeRegex:
+		return "pattern " + strings.TrimSpace(f.Type)[len(regexTypePrefix):]
+	}
+	return ""
+}
+
+// ParseFieldValue validates a single value against the field type and returns
+// the typed value written to front matter: Date for dates, time.Time for
+// datetimes, int or float64 for numbers, bool for booleans, and strings for
+// everything else. Values that already carry the target type are accepted.
+func ParseFieldValue(field TemplateField, value any) (any, error) {
+	kind := field.Kind()
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case Date:
+		if kind == FieldTypeDate || kind == FieldTypeDateTime {
+			return v, nil
+		}
+	case time.Time:
+		switch kind {
+		case FieldTypeDate:
+			return Date{truncateDay(v)}, nil
+		case FieldTypeDateTime:
+			return v, nil
+		}
+	case bool:
+		if kind == FieldTypeBool {
+			return v, nil
+		}
+	case int, int64, float64:
+		if kind == FieldTypeNumber {
+			return checkRange(field, toFloat(v))
+		}
+	}
+
+	raw := strings.TrimSpace(fmt.Sprint(value))
+	if raw == "" {
+		return "", nil
+	}
+
+	switch kind {
+	case FieldTypeDate:
+		return parseDate(raw)
+	case FieldTypeDateTime:
+		for _, layout := range dateTimeLayouts {
+			if ts, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+				return ts, nil
+			}
+		}
+		if date, err := parseDate(raw); err == nil {
+			return date.Time, nil
+		}
+		return nil, fmt.Errorf("value %q is not a date and time (YYYY-MM-DD HH:MM)", raw)
+	case FieldTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a number", raw)
+		}
+		return checkRange(field, number)
+	case FieldTypeBool:
+		switch strings.ToLower(raw) {
+		case "y", "yes", "true", "1", "on":
+			return true, nil
+		case "n", "no", "false", "0", "off":
+			return false, nil
+		}
+		return nil, fmt.Errorf("value %q is not yes or no", raw)
+	case FieldTypeURL:
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("value %q is not an absolute URL", raw)
+		}
+		return raw, nil
+	case FieldTypeNoteLink:
+		name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(raw, "[["), "]]"))
+		if name == "" || strings.ContainsAny(name, "[]") {
+			return nil, fmt.Errorf("value %q is not a note name", raw)
+		}
+		return "[[" + name + "]]", nil
+	case FieldTypeTag:
+		tag := strings.TrimPrefix(raw, "#")
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("value %q is not a valid tag (letters, numbers, _, - and /)", raw)
+		}
+		return tag, nil
+	case FieldTypeRegex:
+		pattern, err := field.Pattern()
+		if err != nil {
+			return nil, err
+		}
+		if !pattern.MatchString(raw) {
+			return nil, fmt.Errorf("value %q does not match %s", raw, pattern)
+		}
+		return raw, nil
+	}
+	return raw, nil
+}
+
+// ParseFieldValues parses each entry of a multi-value field. Entries of text
+// fields stay strings so existing callers keep receiving []string.
+func ParseFieldValues(field TemplateField, values []string) (any, error) {
+	switch field.Kind() {
+	case FieldTypeText, FieldTypeSelect, FieldTypeURL, FieldTypeNoteLink, FieldTypeTag, FieldTypeRegex:
+		parsed := make([]string, 0, len(values))
+		for _, value := range values {
+			typed, err := ParseFieldValue(field, value)
+			if err != nil {
+				return nil, err
+			}
+			if str := typed.(string); str != "" {
+				parsed = append(parsed, str)
+			}
+		}
+		return parsed, nil
+	}
+
+	parsed := make([]any, 0, len(values))
+	for _, value := range values {
+		typed, err := ParseFieldValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		if typed != "" {
+			parsed = append(parsed, typed)
+		}
+	}
+	return parsed, nil
+}
+
+// Applies reports whether the field's `when:` condition holds for the answers
+// collected so far. Fields without a condition always apply.
+func (f TemplateField) Applies(answers map[string]any) (bool, error) {
+	if strings.TrimSpace(f.When) == "" {
+		return true, nil
+	}
+	expr, err := ParseExpression(f.When)
+	if err != nil {
+		return false, fmt.Errorf("field %q when: %w", f.Key, err)
+	}
+	ok, err := expr.Truthy(answers)
+	if err != nil {
+		return false, fmt.Errorf("field %q when: %w", f.Key, err)
+	}
+	return ok, nil
+}
+
+// IsComputed reports whether the field is derived from a `compute:` expression
+// instead of being prompted for.
+func (f TemplateField) IsComputed() bool {
+	return strings.TrimSpace(f.Compute) != ""
+}
+
+// ApplyComputedFields evaluates the manifest's `compute:` fields in order and
+// stores the results in answers. Values that were already provided, for
+// example through prefills, are kept. Fields whose `when:` condition fails are
+// removed, and results are parsed according to the field type.
+func ApplyComputedFields(manifest TemplateManifest, answers map[string]any) error {
+	if err := manifest.CheckExpressions(); err != nil {
+		return err
+	}
+	for _, field := range manifest.Fields {
+		if !field.IsComputed() {
+			continue
+		}
+		applies, err := field.Applies(answers)
+		if err != nil {
+			return err
+		}
+		if !applies {
+			delete(answers, field.Key)
+			continue
+		}
+		if existing, ok := answers[field.Key]; ok && existing != "" && existing != nil {
+			continue
+		}
+
+		expr, err := ParseExpression(field.Compute)
+		if err != nil {
+			return fmt.Errorf("field %q compute: %w", field.Key, err)
+		}
+		value, err := expr.Eval(answers)
+		if err != nil {
+			return fmt.Errorf("field %q compute: %w", field.Key, err)
+		}
+		if value == nil {
+			answers[field.Key] = ""
+			continue
+		}
+		if field.Kind() == FieldTypeText {
+			answers[field.Key] = fmt.Sprint(value)
+			continue
+		}
+		typed, err := ParseFieldValue(field, value)
+		if err != nil {
+			return fmt.Errorf("field %q compute: %w", field.Key, err)
+		}
+		answers[field.Key] = typed
+	}
+	return nil
+}
+
+// CheckExpressions reports `when:` and `compute:` expressions that do not
+// parse or reference a field the manifest does not declare. Evaluation
+// treats unknown names like unanswered fields, so a typo would otherwise
+// silently produce an empty value.
+func (m TemplateManifest) CheckExpressions() error {
+	known, computed := m.exprVars(), m.computedKeys()
+	var problems []error
+	for _, field := range m.Fields {
+		if err := validateFieldExpressions(field, known, computed); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// exprVars returns the names expressions in the manifest may reference.
+func (m TemplateManifest) exprVars() map[string]bool {
+	known := make(map[string]bool, len(m.Fields)+len(builtinExprVars))
+	for _, name := range builtinExprVars {
+		known[name] = true
+	}
+	for _, field := range m.Fields {
+		known[field.Key] = true
+	}
+	return known
+}
+
+// computedKeys returns the keys of the manifest's `compute:` fields.
+func (m TemplateManifest) computedKeys() map[string]bool {
+	computed := make(map[string]bool)
+	for _, field := range m.Fields {
+		if field.IsComputed() {
+			computed[field.Key] = true
+		}
+	}
+	return computed
+}
+
+// validateFieldExpressions checks that `when:` and `compute:` expressions
+// parse and only reference declared fields or built-in variables. Computed
+// fields are evaluated after every prompt is answered, so the `when:` of a
+// prompted field may not reference them.
+func validateFieldExpressions(field TemplateField, known, computed map[string]bool) error {
+	var problems []error
+	for _, entry := range []struct{ name, source string }{
+		{name: "when", source: field.When},
+		{name: "compute", source: field.Compute},
+	} {
+		if strings.TrimSpace(entry.source) == "" {
+			continue
+		}
+		expr, err := ParseExpression(entry.source)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("field %q %s: %w", field.Key, entry.name, err))
+			continue
+		}
+		for _, ident := range expr.Identifiers() {
+			if !known[ident] {
+				problems = append(problems, fmt.Errorf("field %q %s references unknown field %q", field.Key, entry.name, ident))
+			} else if entry.name == "when" && computed[ident] && !field.IsComputed() {
+				problems = append(problems, fmt.Errorf("field %q when references computed field %q, which is only set after prompting", field.Key, ident))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// NoteLinkCandidates returns vault note names containing the query, with an
+// exact match first. It is used to complete note-link fields.
+func (t *Templater) NoteLinkCandidates(query string, limit int) []string {
+	query = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(query, "[["), "]]")))
+	idx, err := t.indexSnapshot()
+	if err != nil || idx == nil {
+		return nil
+	}
+
+	var exact, partial []string
+	for _, doc := range idx.Documents() {
+		name := t.noteName(doc.Path)
+		lower := strings.ToLower(name)
+		base := lower[strings.LastIndex(lower, "/")+1:]
+		switch {
+		case lower == query || base == query:
+			exact = append(exact, name)
+		case strings.Contains(lower, query):
+			partial = append(partial, name)
+		}
+	}
+	sort.Strings(partial)
+	candidates := append(exact, partial...)
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// validateFieldDefinition reports manifest mistakes in a typed field, such as
+// an invalid pattern, inverted bounds, or a default that does not parse.
+func validateFieldDefinition(field TemplateField) error {
+	var problems []error
+	if _, err := field.Pattern(); err != nil {
+		problems = append(problems, fmt.Errorf("field %q: %w", field.Key, err))
+	}
+	if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+		problems = append(problems, fmt.Errorf("field %q: min %s is greater than max %s",
+			field.Key, formatNumber(*field.Min), formatNumber(*field.Max)))
+	}
+	defaults := append([]string(nil), field.Defaults...)
+	if field.Default != "" {
+		defaults = append(defaults, field.Default)
+	}
+	for _, value := range defaults {
+		if _, err := ParseFieldValue(field, value); err != nil {
+			problems = append(problems, fmt.Errorf("field %q default: %w", field.Key, err))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func parseDate(raw string) (Date, error) {
+	today := truncateDay(nowFunc())
+	switch strings.ToLower(raw) {
+	case "today":
+		return Date{today}, nil
+	case "tomorrow":
+		return Date{today.AddDate(0, 0, 1)}, nil
+	case "yesterday":
+		return Date{today.AddDate(0, 0, -1)}, nil
+	}
+	for _, layout := range dateLayouts {
+		if ts, err := time.Parse(layout, raw); err == nil {
+			return Date{ts}, nil
+		}
+	}
+	return Date{}, fmt.Errorf("value %q is not a date (YYYY-MM-DD)", raw)
+}
+
+func truncateDay(ts time.Time) time.Time {
+	return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func checkRange(field TemplateField, number float64) (any, error) {
+	if field.Min != nil && number < *field.Min {
+		return nil, fmt.Errorf("value %s is below the minimum %s", formatNumber(number), formatNumber(*field.Min))
+	}
+	if field.Max != nil && number > *field.Max {
+		return nil, fmt.Errorf("value %s is above the maximum %s", formatNumber(number), formatNumber(*field.Max))
+	}
+	if number == math.Trunc(number) && math.Abs(number) < math.MaxInt32 {
+		return int(number), nil
+	}
+	return number, nil
+}
+
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
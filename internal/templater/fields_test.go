@@ -0,0 +1,106 @@
+package templater
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFieldValueTypes(t *testing.T) {
+	restore := nowFunc
+	nowFunc = func() time.Time { return time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { nowFunc = restore })
+
+	low, high := 1.0, 10.0
+	tests := []struct {
+		name    string
+		field   TemplateField
+		input   any
+		want    any
+		wantErr string
+	}{
+		{name: "date", field: TemplateField{Type: "date"}, input: "2024-05-01", want: Date{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		{name: "date keyword", field: TemplateField{Type: "date"}, input: "tomorrow", want: Date{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)}},
+		{name: "bad date", field: TemplateField{Type: "date"}, input: "soon", wantErr: "not a date"},
+		{name: "number", field: TemplateField{Type: "number", Min: &low, Max: &high}, input: "3", want: 3},
+		{name: "fraction", field: TemplateField{Type: "number"}, input: "2.5", want: 2.5},
+		{name: "typed number", field: TemplateField{Type: "number"}, input: 4, want: 4},
+		{name: "number above max", field: TemplateField{Type: "number", Max: &high}, input: "11", wantErr: "above the maximum 10"},
+		{name: "bool", field: TemplateField{Type: "bool"}, input: "Yes", want: true},
+		{name: "bad bool", field: TemplateField{Type: "bool"}, input: "maybe", wantErr: "not yes or no"},
+		{name: "url", field: TemplateField{Type: "url"}, input: "https://example.com/a", want: "https://example.com/a"},
+		{name: "relative url", field: TemplateField{Type: "url"}, input: "example.com", wantErr: "absolute URL"},
+		{name: "note link", field: TemplateField{Type: "note-link"}, input: "atoms/apollo", want: "[[atoms/apollo]]"},
+		{name: "tag", field: TemplateField{Type: "tag"}, input: "#area/work", want: "area/work"},
+		{name: "bad tag", field: TemplateField{Type: "tag"}, input: "two words", wantErr: "not a valid tag"},
+		{name: "regex", field: TemplateField{Type: `regex:^[A-Z]+-\d+$`}, input: "AN-42", want: "AN-42"},
+		{name: "regex mismatch", field: TemplateField{Type: `regex:^[A-Z]+-\d+$`}, input: "an-42", wantErr: "does not match"},
+		{name: "text", field: TemplateField{Type: "text"}, input: " hello ", want: "hello"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseFieldValue(tc.field, tc.input)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExecuteWritesTypedFrontMatter(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"typed": "---\ntitle: {{.Title}}\ncreated: 2024-01-02\n---\nDue {{index .Metadata \"due\"}}\n",
+	})
+
+	rendered, err := tmpl.Execute("typed", TemplateData{
+		Title: "typed",
+		Metadata: map[string]interface{}{
+			"due":      Date{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+			"effort":   3,
+			"billable": true,
+			"link":     "https://example.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		"billable: true\n",
+		"created: 2024-01-02\n",
+		"due: 2024-02-01\n",
+		"effort: 3\n",
+		"link: https://example.com\n",
+		"Due 2024-02-01",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in rendered output:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestValidateRejectsBadTypedFields(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"broken": "{{/* an:manifest\nfields:\n  - key: ticket\n    type: \"regex:[\"\n  - key: effort\n    type: number\n    min: 5\n    max: 1\n  - key: due\n    type: date\n    default: someday\n*/}}\nBody\n",
+	})
+
+	err := tmpl.Validate("broken")
+	if err == nil {
+		t.Fatalf("expected validation errors")
+	}
+	for _, want := range []string{"invalid pattern", "min 5 is greater than max 1", `field "due" default`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err.Error())
+		}
+	}
+}
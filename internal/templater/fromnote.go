@@ -0,0 +1,189 @@
+package templater
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FromNote converts an existing note into template source. The note title,
+// created date, upstream link, tags, and fulfilled flag become template
+// placeholders, every other front matter key becomes a manifest field, and
+// the body is kept with template delimiters escaped. source is recorded in
+// the manifest description.
+func FromNote(name, source, content string) (string, error) {
+	frontMatter, body, hasFrontMatter := splitNoteFrontMatter(content)
+
+	var (
+		title  string
+		lines  []string
+		fields []string
+	)
+
+	if hasFrontMatter {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(frontMatter), &doc); err != nil {
+			return "", fmt.Errorf("parse front matter: %w", err)
+		}
+		if len(doc.Content) > 0 {
+			mapping := doc.Content[0]
+			if mapping.Kind != yaml.MappingNode {
+				return "", fmt.Errorf("front matter is not a mapping")
+			}
+			seen := make(map[string]bool, len(mapping.Content)/2)
+			for i := 0; i+1 < len(mapping.Content); i += 2 {
+				key := mapping.Content[i].Value
+				value := mapping.Content[i+1]
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				switch strings.ToLower(key) {
+				case "title":
+					title = value.Value
+					lines = append(lines, key+": {{.Title}}")
+				case "created", "date":
+					lines = append(lines, key+": {{.Date}}")
+				case "modified":
+					lines = append(lines, key+":")
+				case "fulfilled":
+					lines = append(lines, key+": {{.Fulfilled}}")
+				case "up", "upstream":
+					if strings.HasPrefix(value.Value, "[[") {
+						lines = append(lines, key+`: "[[{{.Upstream}}]]"`)
+					} else {
+						lines = append(lines, key+": {{.Upstream}}")
+					}
+				case "tags":
+					lines = append(lines, key+":", "{{- range .Tags}}", "  - {{.}}", "{{- end}}")
+				default:
+					fields = append(fields, manifestField(key, value))
+				}
+			}
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("{{/* an:manifest\n")
+	fmt.Fprintf(&builder, "name: %s\n", name)
+	fmt.Fprintf(&builder, "description: %s\n", strconv.Quote("Created from "+source))
+	if len(fields) == 0 {
+		builder.WriteString("fields: []\n")
+	} else {
+		builder.WriteString("fields:\n")
+		for _, field := range fields {
+			builder.WriteString(field)
+		}
+	}
+	builder.WriteString("*/}}\n")
+
+	if len(lines) > 0 {
+		builder.WriteString("---\n")
+		builder.WriteString(strings.Join(lines, "\n"))
+		builder.WriteString("\n---\n")
+	}
+	builder.WriteString(templatizeBody(body, title))
+
+	return builder.String(), nil
+}
+
+// manifestField renders a manifest field entry for a front matter key,
+// inferring its type from the note's value and recording that value as an
+// example comment.
+func manifestField(key string, value *yaml.Node) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "  - key: %s\n", yamlKey(key))
+	fmt.Fprintf(&builder, "    prompt: %s\n", strconv.Quote(titleCase(strings.NewReplacer("_", " ", "-", " ").Replace(key))))
+
+	example := value.Value
+	switch value.Kind {
+	case yaml.SequenceNode:
+		builder.WriteString("    multi: true\n")
+		items := make([]string, 0, len(value.Content))
+		for _, item := range value.Content {
+			items = append(items, item.Value)
+		}
+		example = strings.Join(items, ", ")
+	case yaml.ScalarNode:
+		switch value.Tag {
+		case "!!bool":
+			builder.WriteString("    type: bool\n")
+		case "!!int", "!!float":
+			builder.WriteString("    type: number\n")
+		case "!!timestamp":
+			if _, err := time.Parse("2006-01-02", value.Value); err == nil {
+				builder.WriteString("    type: date\n")
+			} else {
+				builder.WriteString("    type: datetime\n")
+			}
+		default:
+			if strings.HasPrefix(value.Value, "http://") || strings.HasPrefix(value.Value, "https://") {
+				builder.WriteString("    type: url\n")
+			} else if strings.HasPrefix(value.Value, "[[") && strings.HasSuffix(value.Value, "]]") {
+				builder.WriteString("    type: note-link\n")
+			}
+		}
+	}
+	if example = strings.TrimSpace(example); example != "" && !strings.Contains(example, "\n") {
+		fmt.Fprintf(&builder, "    # example: %s\n", example)
+	}
+	return builder.String()
+}
+
+var plainKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+// yamlKey quotes keys that cannot be written as plain YAML scalars.
+func yamlKey(key string) string {
+	if plainKeyPattern.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func splitNoteFrontMatter(content string) (string, string, bool) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", normalized, false
+	}
+	rest := normalized[len("---\n"):]
+	if strings.HasPrefix(rest, "---\n") {
+		return "", rest[len("---\n"):], true
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end == -1 {
+		if strings.HasSuffix(rest, "\n---") {
+			return rest[:len(rest)-len("\n---")], "", true
+		}
+		return "", normalized, false
+	}
+	return rest[:end], rest[end+len("\n---\n"):], true
+}
+
+// templatizeBody escapes template delimiters in the note body and replaces
+// a heading matching the note title with the title placeholder.
+func templatizeBody(body, title string) string {
+	escaped := strings.ReplaceAll(body, "{{", `{{"{{"}}`)
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return escaped
+	}
+	lines := strings.Split(escaped, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		heading := strings.TrimLeft(trimmed, "#")
+		if strings.TrimSpace(heading) == title {
+			prefix := trimmed[:len(trimmed)-len(heading)]
+			lines[i] = prefix + " {{.Title}}"
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
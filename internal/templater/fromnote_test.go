@@ -0,0 +1,77 @@
+package templater
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromNoteBuildsTemplate(t *testing.T) {
+	note := `---
+title: apollo-kickoff
+created: 2024-03-01
+up: "[[apollo]]"
+tags:
+  - meeting
+  - apollo
+status: open
+attendees:
+  - ana
+  - bo
+billable: true
+budget: 1200
+due: 2024-04-01
+élan_score: 3
+---
+
+# apollo-kickoff
+
+Agenda uses {{ braces }} literally.
+`
+
+	content, err := FromNote("kickoff", "atoms/apollo-kickoff.md", note)
+	if err != nil {
+		t.Fatalf("FromNote returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		"name: kickoff\n",
+		`description: "Created from atoms/apollo-kickoff.md"`,
+		"title: {{.Title}}\n",
+		"created: {{.Date}}\n",
+		"up: \"[[{{.Upstream}}]]\"\n",
+		"tags:\n{{- range .Tags}}\n  - {{.}}\n{{- end}}\n",
+		"# {{.Title}}\n",
+		`prompt: "Élan Score"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in template:\n%s", want, content)
+		}
+	}
+
+	manifest, body, err := parseManifest("kickoff", content)
+	if err != nil {
+		t.Fatalf("generated manifest does not parse: %v", err)
+	}
+	types := make(map[string]string)
+	for _, field := range manifest.Fields {
+		types[field.Key] = field.Type
+		if field.Key == "attendees" && !field.Multi {
+			t.Fatalf("expected attendees to be a multi field")
+		}
+	}
+	expected := map[string]string{"status": "text", "attendees": "text", "billable": "bool", "budget": "number", "due": "date"}
+	for key, typ := range expected {
+		if types[key] != typ {
+			t.Fatalf("expected field %s of type %s, got %q (%v)", key, typ, types[key], types)
+		}
+	}
+
+	tmpl := &Templater{templates: TemplateMap{"kickoff": {Manifest: manifest, Content: body}}}
+	rendered, err := tmpl.Execute("kickoff", TemplateData{Title: "beta-kickoff", Date: "20240501", Upstream: "beta", Tags: []string{"meeting"}})
+	if err != nil {
+		t.Fatalf("generated template does not render: %v", err)
+	}
+	if !strings.Contains(rendered, "# beta-kickoff") || !strings.Contains(rendered, "{{ braces }}") {
+		t.Fatalf("unexpected rendered output:\n%s", rendered)
+	}
+}
@@ -0,0 +1,427 @@
+package templater
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+
+	"github.com/Paintersrp/an/internal/search"
+	indexsvc "github.com/Paintersrp/an/internal/services/index"
+	taskidx "github.com/Paintersrp/an/internal/services/tasks/index"
+)
+
+var (
+	readClipboard = clipboard.ReadAll
+	nowFunc       = time.Now
+	slugPattern   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// IndexSource supplies search index snapshots to vault-aware template helpers.
+type IndexSource interface {
+	AcquireSnapshot() (*search.Index, error)
+}
+
+// TaskSource supplies task snapshots to vault-aware template helpers.
+type TaskSource interface {
+	AcquireSnapshot() (*taskidx.Snapshot, error)
+}
+
+// TaskRef is an open task returned by the openTasks template helper. It
+// renders as its content when printed directly.
+type TaskRef struct {
+	Content string
+	Path    string
+	Line    int
+	Project string
+}
+
+func (t TaskRef) String() string {
+	return t.Content
+}
+
+// UseVaultSources wires the shared index and task services into the
+// vault-aware template helpers. Without sources the helpers scan the vault on
+// demand.
+func (t *Templater) UseVaultSources(index IndexSource, tasks TaskSource) {
+	t.index = index
+	t.tasks = tasks
+}
+
+// funcMap returns the functions available to every template:
+//
+//	now                      current local time
+//	date "2006-01-02" [t]    format t (default now) with a Go layout
+//	dateAdd "1d" [t]         shift t (default now) by a duration; accepts Go
+//	                         durations plus d, w, mo, and y suffixes
+//	weekStart [t]            Monday 00:00 of the week containing t
+//	slug s                   lowercase, dash-separated form of s
+//	upper/lower/title s      change case
+//	default fallback v       v unless it is empty, otherwise fallback
+//	env "NAME"               environment variable value
+//	clipboard                current clipboard text, empty on failure
+//	link "note"              wiki link to the note
+//	prevJournal "day"        name of the latest earlier journal entry
+//	openTasks "project"      unchecked tasks tagged with the project
+//	backlinks "note"         names of notes linking to the note
+//	search "tag:x term"      names of notes matching the query
+//	partial "name" [data]    render templates/partials/name.tmpl with data
+func (t *Templater) funcMap() template.FuncMap {
+	return template.FuncMap{
+		"join":        strings.Join,
+		"now":         func() time.Time { return nowFunc() },
+		"date":        formatDate,
+		"dateAdd":     addDate,
+		"weekStart":   weekStart,
+		"slug":        slugify,
+		"upper":       strings.ToUpper,
+		"lower":       strings.ToLower,
+		"title":       titleCase,
+		"default":     defaultValue,
+		"env":         os.Getenv,
+		"clipboard":   clipboardText,
+		"link":        wikiLink,
+		"prevJournal": t.prevJournal,
+		"openTasks":   t.openTasks,
+		"backlinks":   t.backlinks,
+		"search":      t.search,
+		"partial":     t.partialFunc(make(map[string]bool)),
+	}
+}
+
+func formatDate(layout string, value ...any) (string, error) {
+	ts, err := optionalTime(value)
+	if err != nil {
+		return "", err
+	}
+	return ts.Format(layout), nil
+}
+
+func addDate(spec string, value ...any) (time.Time, error) {
+	ts, err := optionalTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	spec = strings.TrimSpace(spec)
+	for _, unit := range []struct {
+		suffix             string
+		years, months, day int
+	}{
+		{suffix: "mo", months: 1},
+		{suffix: "y", years: 1},
+		{suffix: "w", day: 7},
+		{suffix: "d", day: 1},
+	} {
+		if !strings.HasSuffix(spec, unit.suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSuffix(spec, unit.suffix))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("dateAdd: invalid offset %q", spec)
+		}
+		return ts.AddDate(count*unit.years, count*unit.months, count*unit.day), nil
+	}
+
+	duration, err := time.ParseDuration(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("dateAdd: invalid offset %q", spec)
+	}
+	return ts.Add(duration), nil
+}
+
+func weekStart(value ...any) (time.Time, error) {
+	ts, err := optionalTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+}
+
+// optionalTime resolves the trailing time argument accepted by the date
+// helpers. Strings are parsed as RFC 3339 timestamps or YYYY-MM-DD dates.
+func optionalTime(value []any) (time.Time, error) {
+	if len(value) == 0 || value[0] == nil {
+		return nowFunc(), nil
+	}
+	switch v := value[0].(type) {
+	case time.Time:
+		return v, nil
+	case Date:
+		return v.Time, nil
+	case *time.Time:
+		if v == nil {
+			return nowFunc(), nil
+		}
+		return *v, nil
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return nowFunc(), nil
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02", "20060102"} {
+			if ts, err := time.ParseInLocation(layout, trimmed, time.Local); err == nil {
+				return ts, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a date", v)
+	default:
+		return time.Time{}, fmt.Errorf("expected a time value, got %T", value[0])
+	}
+}
+
+func slugify(value string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
+
+func titleCase(value string) string {
+	words := strings.Fields(value)
+	for i, word := range words {
+		lower := strings.ToLower(word)
+		first, size := utf8.DecodeRuneInString(lower)
+		words[i] = string(unicode.ToTitle(first)) + lower[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func defaultValue(fallback any, value ...any) any {
+	if len(value) == 0 || isEmptyValue(value[0]) {
+		return fallback
+	}
+	return value[0]
+}
+
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []string:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	}
+	return false
+}
+
+func clipboardText() string {
+	text, err := readClipboard()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+func wikiLink(note string) string {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return ""
+	}
+	return "[[" + strings.TrimSuffix(note, ".md") + "]]"
+}
+
+// prevJournal returns the name of the most recent journal entry of the given
+// kind (day, week, month, or year) dated before the current period.
+func (t *Templater) prevJournal(kind string) (string, error) {
+	if t.vault == "" {
+		return "", nil
+	}
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	layout, current := journalPeriod(kind, nowFunc())
+	if layout == "" {
+		return "", fmt.Errorf("prevJournal: unknown journal kind %q", kind)
+	}
+
+	prefix := kind + "-"
+	best := ""
+	var bestDate time.Time
+	err := filepath.WalkDir(filepath.Join(t.vault, "atoms"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(d.Name(), ".md")
+		if d.IsDir() || name == d.Name() || !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		date, err := time.ParseInLocation(layout, strings.TrimPrefix(name, prefix), time.Local)
+		if err != nil || !date.Before(current) {
+			return nil
+		}
+		if best == "" || date.After(bestDate) {
+			best, bestDate = name, date
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return best, nil
+}
+
+func journalPeriod(kind string, now time.Time) (string, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch kind {
+	case "day":
+		return "20060102", day
+	case "week":
+		// Weekly entries are named after the Sunday that starts the week.
+		return "20060102", day.AddDate(0, 0, -int(day.Weekday()))
+	case "month":
+		return "200601", time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	case "year":
+		return "2006", time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.Local)
+	}
+	return "", time.Time{}
+}
+
+// openTasks returns the unchecked tasks whose project matches the argument.
+// An empty project returns every open task.
+func (t *Templater) openTasks(project string) ([]TaskRef, error) {
+	snapshot, err := t.taskSnapshot()
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	project = strings.TrimSpace(project)
+	var refs []TaskRef
+	for _, task := range snapshot.Tasks() {
+		if strings.EqualFold(task.Status, "checked") {
+			continue
+		}
+		if project != "" && !strings.EqualFold(task.Metadata.Project, project) {
+			continue
+		}
+		refs = append(refs, TaskRef{
+			Content: task.Content,
+			Path:    t.noteName(task.Path),
+			Line:    task.Line,
+			Project: task.Metadata.Project,
+		})
+	}
+	return refs, nil
+}
+
+// backlinks returns the names of notes linking to the provided note.
+func (t *Templater) backlinks(note string) ([]string, error) {
+	idx, err := t.indexSnapshot()
+	if err != nil || idx == nil {
+		return nil, err
+	}
+	target := idx.Canonical(strings.TrimSpace(note))
+	if target == "" {
+		target = idx.Canonical(strings.TrimSpace(note) + ".md")
+	}
+	if target == "" {
+		return nil, nil
+	}
+	related := idx.Related(target)
+	names := make([]string, 0, len(related.Backlinks))
+	for _, path := range related.Backlinks {
+		names = append(names, t.noteName(path))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// search returns the names of notes matching the query. Terms of the form
+// tag:x filter by tag, key:value filters by front matter, and the remaining
+// words are matched as free text.
+func (t *Templater) search(query string) ([]string, error) {
+	idx, err := t.indexSnapshot()
+	if err != nil || idx == nil {
+		return nil, err
+	}
+
+	q := search.ParseQuery(query)
+	var paths []string
+	if q.Term == "" {
+		for _, doc := range idx.FilteredDocuments(q) {
+			paths = append(paths, doc.Path)
+		}
+	} else {
+		for _, result := range idx.Search(q) {
+			paths = append(paths, result.Path)
+		}
+	}
+
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, t.noteName(path))
+	}
+	return names, nil
+}
+
+func (t *Templater) indexSnapshot() (*search.Index, error) {
+	if t.index == nil && t.vault == "" {
+		return nil, nil
+	}
+	return indexsvc.Snapshot(t.index, t.vault, search.Config{EnableBody: true}, t.vaultNotes)
+}
+
+// vaultNotes lists the Markdown files of the vault outside hidden folders,
+// for building an index while the shared one is still warming up.
+func (t *Templater) vaultNotes() ([]string, error) {
+	if t.vault == "" {
+		return nil, nil
+	}
+	var paths []string
+	err := filepath.WalkDir(t.vault, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != t.vault && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".md" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (t *Templater) taskSnapshot() (*taskidx.Snapshot, error) {
+	if t.tasks != nil {
+		if snapshot, err := t.tasks.AcquireSnapshot(); err == nil && snapshot != nil {
+			return snapshot, nil
+		}
+	}
+	if t.vault == "" {
+		return nil, nil
+	}
+	service := taskidx.NewService(t.vault)
+	defer service.Close()
+	return service.AcquireSnapshot()
+}
+
+// noteName converts a note path to its vault-relative name without the
+// Markdown extension so it can be used inside wiki links.
+func (t *Templater) noteName(path string) string {
+	name := path
+	if t.vault != "" {
+		if rel, err := filepath.Rel(t.vault, path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+	}
+	return strings.TrimSuffix(filepath.ToSlash(name), ".md")
+}
@@ -0,0 +1,139 @@
+package templater
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paintersrp/an/internal/config"
+)
+
+func TestExecuteTemplateFunctions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AN_TEMPLATE_TEST", "from-env")
+
+	fixed := time.Date(2024, 5, 16, 10, 0, 0, 0, time.Local) // Thursday
+	prevNow, prevClipboard := nowFunc, readClipboard
+	nowFunc = func() time.Time { return fixed }
+	readClipboard = func() (string, error) { return " copied text \n", nil }
+	t.Cleanup(func() { nowFunc, readClipboard = prevNow, prevClipboard })
+
+	tmpl, err := NewTemplater(nil)
+	if err != nil {
+		t.Fatalf("NewTemplater returned error: %v", err)
+	}
+	tmpl.templates["funcs"] = SingleTemplate{Content: strings.Join([]string{
+		`{{ date "2006-01-02" }}`,
+		`{{ now | dateAdd "-1d" | date "2006-01-02" }}`,
+		`{{ dateAdd "2w" "2024-01-01" | date "Jan 2" }}`,
+		`{{ weekStart | date "Mon 2006-01-02" }}`,
+		`{{ slug "Project Apollo: Launch!" }}`,
+		`{{ upper "a" }}{{ lower "B" }} {{ title "hello wide world" }}`,
+		`{{ default "fallback" .Upstream }} {{ .Title | default "unused" }}`,
+		`{{ env "AN_TEMPLATE_TEST" }}`,
+		`{{ clipboard }}`,
+		`{{ link "atoms/apollo.md" }}`,
+	}, "\n")}
+
+	out, err := tmpl.Execute("funcs", TemplateData{Title: "Apollo"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"2024-05-16",
+		"2024-05-15",
+		"Jan 15",
+		"Mon 2024-05-13",
+		"project-apollo-launch",
+		"Ab Hello Wide World",
+		"fallback Apollo",
+		"from-env",
+		"copied text",
+		"[[atoms/apollo]]",
+	}, "\n")
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+
+	readClipboard = func() (string, error) { return "", errors.New("no clipboard") }
+	tmpl.templates["clip"] = SingleTemplate{Content: `[{{ clipboard }}]`}
+	if out, err := tmpl.Execute("clip", nil); err != nil || out != "[]" {
+		t.Fatalf("expected clipboard failures to render empty, got %q, %v", out, err)
+	}
+
+	tmpl.templates["bad"] = SingleTemplate{Content: `{{ dateAdd "soon" }}`}
+	if _, err := tmpl.Execute("bad", nil); err == nil {
+		t.Fatalf("expected invalid dateAdd offset to fail")
+	}
+}
+
+func TestTitleCaseHandlesNonASCII(t *testing.T) {
+	for input, want := range map[string]string{
+		"élan vital": "Élan Vital",
+		"ñu":         "Ñu",
+		"ÉCOLE":      "École",
+		"":           "",
+	} {
+		if got := titleCase(input); got != want {
+			t.Fatalf("titleCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestExecuteVaultAwareFunctions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	fixed := time.Date(2024, 5, 16, 10, 0, 0, 0, time.Local)
+	prevNow := nowFunc
+	nowFunc = func() time.Time { return fixed }
+	t.Cleanup(func() { nowFunc = prevNow })
+
+	vault := t.TempDir()
+	files := map[string]string{
+		"atoms/day-20240510.md": "# old day\n",
+		"atoms/day-20240515.md": "# yesterday\n",
+		"atoms/day-20240516.md": "# today\n",
+		"atoms/apollo.md":       "---\ntags:\n  - project\n---\n# Apollo\n\n- [ ] draft plan @project(apollo)\n- [x] kickoff @project(apollo)\n- [ ] unrelated @project(zeus)\n",
+		"atoms/notes.md":        "---\ntags:\n  - project\nstatus: active\n---\nSee [[apollo]].\n",
+		"atoms/other.md":        "---\ntags:\n  - misc\n---\nNothing here.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(vault, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	tmpl, err := NewTemplater(&config.Workspace{VaultDir: vault})
+	if err != nil {
+		t.Fatalf("NewTemplater returned error: %v", err)
+	}
+	tmpl.templates["vault"] = SingleTemplate{Content: strings.Join([]string{
+		`{{ prevJournal "day" | link }}`,
+		`{{ range openTasks "apollo" }}- [ ] {{ . }} ({{ .Path }}){{ end }}`,
+		`{{ join (backlinks .Title) ", " }}`,
+		`{{ join (search "tag:project status:active") ", " }}`,
+	}, "\n")}
+
+	out, err := tmpl.Execute("vault", TemplateData{Title: "apollo"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"[[day-20240515]]",
+		"- [ ] draft plan (atoms/apollo)",
+		"atoms/notes",
+		"atoms/notes",
+	}, "\n")
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
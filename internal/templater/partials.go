@@ -0,0 +1,200 @@
+package templater
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// partialsDir is the subdirectory of each template layer that holds partials.
+// Partials are not templates on their own; they are included with
+// `{{ partial "name" . }}`.
+const partialsDir = "partials"
+
+// Partials returns the names of the registered partials.
+func (t *Templater) Partials() []string {
+	names := make([]string, 0, len(t.partials))
+	for name := range t.partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m TemplateMap) loadPartials(dirPath string) error {
+	return filepath.Walk(
+		dirPath,
+		func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || filepath.Ext(filePath) != ".tmpl" {
+				return nil
+			}
+
+			rel, err := filepath.Rel(dirPath, filePath)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(filepath.ToSlash(rel), ".tmpl")
+			if _, exists := m[name]; exists {
+				return nil
+			}
+
+			contents, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			m.addPartial(name, filePath, string(contents))
+			return nil
+		},
+	)
+}
+
+func (m TemplateMap) loadEmbeddedPartials(embeddedFS embed.FS) error {
+	root := "templates/" + partialsDir
+	err := fs.WalkDir(
+		embeddedFS,
+		root,
+		func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || path.Ext(filePath) != ".tmpl" {
+				return nil
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(filePath, root+"/"), ".tmpl")
+			if _, exists := m[name]; exists {
+				return nil
+			}
+
+			data, err := fs.ReadFile(embeddedFS, filePath)
+			if err != nil {
+				return err
+			}
+			m.addPartial(name, filePath, string(data))
+			return nil
+		},
+	)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (m TemplateMap) addPartial(name, filePath, content string) {
+	manifest, body, err := parseManifest(name, content)
+	m[name] = SingleTemplate{FilePath: filePath, Manifest: manifest, Content: body, Err: err}
+}
+
+// partialFunc returns the `partial` template helper. The stack tracks the
+// partials currently being rendered so that a partial including itself,
+// directly or through others, is reported instead of recursing forever.
+func (t *Templater) partialFunc(stack map[string]bool) func(string, ...any) (string, error) {
+	var render func(string, ...any) (string, error)
+	render = func(name string, data ...any) (string, error) {
+		partial, ok := t.partials[name]
+		if !ok {
+			return "", fmt.Errorf("partial %q not found", name)
+		}
+		if partial.Err != nil {
+			return "", partial.Err
+		}
+		if stack[name] {
+			return "", fmt.Errorf("detected circular partial inclusion involving %s", name)
+		}
+		stack[name] = true
+		defer delete(stack, name)
+
+		funcs := t.funcMap()
+		funcs["partial"] = render
+		tmpl, err := template.New(partialsDir + "/" + name).Funcs(funcs).Parse(partial.Content)
+		if err != nil {
+			return "", err
+		}
+
+		var ctx any
+		if len(data) > 0 {
+			ctx = data[0]
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, ctx); err != nil {
+			return "", err
+		}
+		return out.String(), nil
+	}
+	return render
+}
+
+// parseLayers parses each layer of an inheritance chain separately and joins
+// them into one template. Layer bodies render in order, ancestors first, while
+// a `{{define}}` in a later layer replaces the same-named `{{block}}` or
+// `{{define}}` from an earlier one, letting a child override a single section
+// of its parent.
+func parseLayers(name string, layers []string, funcs template.FuncMap) (*template.Template, error) {
+	root := template.New(name).Funcs(funcs)
+
+	var body *parse.Tree
+	defined := make(map[string]*parse.Tree)
+	var order []string
+
+	for _, layer := range layers {
+		parsed, err := template.New(name).Funcs(funcs).Parse(layer)
+		if err != nil {
+			return nil, err
+		}
+		for _, tmpl := range parsed.Templates() {
+			if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+				continue
+			}
+			if tmpl.Name() != name {
+				if _, seen := defined[tmpl.Name()]; !seen {
+					order = append(order, tmpl.Name())
+				}
+				defined[tmpl.Name()] = tmpl.Tree
+				continue
+			}
+			switch {
+			case body == nil:
+				body = tmpl.Tree.Copy()
+			case !isBlankTree(tmpl.Tree):
+				body.Root.Nodes = append(body.Root.Nodes, tmpl.Tree.Copy().Root.Nodes...)
+			}
+		}
+	}
+
+	if body == nil {
+		return root.Parse("")
+	}
+	if _, err := root.AddParseTree(name, body); err != nil {
+		return nil, err
+	}
+	for _, defName := range order {
+		if _, err := root.AddParseTree(defName, defined[defName]); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// isBlankTree reports whether a template body only contains whitespace, as is
+// the case for a child template that consists solely of `{{define}}` blocks.
+func isBlankTree(tree *parse.Tree) bool {
+	for _, node := range tree.Root.Nodes {
+		text, ok := node.(*parse.TextNode)
+		if !ok || strings.TrimSpace(string(text.Text)) != "" {
+			return false
+		}
+	}
+	return true
+}
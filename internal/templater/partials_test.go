@@ -0,0 +1,92 @@
+package templater
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/config"
+)
+
+func writePartial(t *testing.T, tmpl *Templater, name, content string) {
+	t.Helper()
+	path := filepath.Join(tmpl.WorkspaceTemplateDir(), partialsDir, name+".tmpl")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create partial directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write partial: %v", err)
+	}
+}
+
+func TestPartialsRenderAndDetectCycles(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"standup": "# {{.Title}}\n{{ partial \"checklist\" . }}",
+		"loop":    "{{ partial \"ping\" . }}",
+	})
+	writePartial(t, tmpl, "checklist", "- [ ] {{ .Title }}\n{{ partial \"shared/footer\" . }}")
+	writePartial(t, tmpl, "shared/footer", "-- {{ upper .Title }}\n")
+	writePartial(t, tmpl, "ping", "{{ partial \"pong\" . }}")
+	writePartial(t, tmpl, "pong", "{{ partial \"ping\" . }}")
+
+	reloaded, err := NewTemplater(&config.Workspace{VaultDir: tmpl.vault})
+	if err != nil {
+		t.Fatalf("NewTemplater returned error: %v", err)
+	}
+	if _, ok := reloaded.templates["checklist"]; ok {
+		t.Fatalf("partials must not be registered as templates")
+	}
+
+	rendered, err := reloaded.Execute("standup", TemplateData{Title: "sync"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if rendered != "# sync\n- [ ] sync\n-- SYNC\n" {
+		t.Fatalf("unexpected rendered output %q", rendered)
+	}
+
+	if _, err := reloaded.Execute("loop", TemplateData{}); err == nil || !strings.Contains(err.Error(), "circular partial inclusion") {
+		t.Fatalf("expected circular partial error, got %v", err)
+	}
+}
+
+func TestChildTemplateOverridesParentBlock(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"base":   "# {{.Title}}\n{{block \"tasks\" .}}default tasks\n{{end}}{{block \"notes\" .}}default notes\n{{end}}",
+		"sprint": "{{/* an:manifest\nextends: [base]\n*/}}\n{{define \"tasks\"}}sprint tasks for {{.Title}}\n{{end}}",
+		"retro":  "{{/* an:manifest\nextends: [base]\n*/}}\n{{define \"notes\"}}retro notes\n{{end}}\nFooter\n",
+	})
+
+	sprint, err := tmpl.Execute("sprint", TemplateData{Title: "s1"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if sprint != "# s1\nsprint tasks for s1\ndefault notes\n" {
+		t.Fatalf("unexpected sprint output %q", sprint)
+	}
+
+	retro, err := tmpl.Execute("retro", TemplateData{Title: "r1"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if retro != "# r1\ndefault tasks\nretro notes\n\nFooter\n" {
+		t.Fatalf("unexpected retro output %q", retro)
+	}
+}
+
+func TestEmbeddedDayUsesTasksPartial(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, nil)
+
+	rendered, err := tmpl.Execute("day", TemplateData{Title: "today", Date: "20240314"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	want := "## Task Management\n\n### Task Queue\n- [ ]\n- [ ]\n- [ ]\n\n### Ongoing Efforts\n-\n\n### Roadblocks\n-\n\n---\n\n## Reflections"
+	if !strings.Contains(rendered, want) {
+		t.Fatalf("expected tasks section in day template:\n%s", rendered)
+	}
+	if names := tmpl.Partials(); len(names) == 0 || names[len(names)-1] != "tasks" {
+		t.Fatalf("expected embedded tasks partial, got %v", names)
+	}
+}
@@ -0,0 +1,157 @@
+package templater
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ScaffoldEntry is one note listed under a template's `scaffold` manifest key.
+// Filename and Subdir are template strings rendered with the scaffold title
+// and the entry key, Up names the entry the note links upstream to, and Links
+// names entries linked from the note body.
+type ScaffoldEntry struct {
+	Key      string   `yaml:"key"`
+	Template string   `yaml:"template"`
+	Subdir   string   `yaml:"subdir,omitempty"`
+	Filename string   `yaml:"filename,omitempty"`
+	Up       string   `yaml:"up,omitempty"`
+	Links    []string `yaml:"links,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+}
+
+// ScaffoldFile is a scaffold entry resolved for a specific title.
+type ScaffoldFile struct {
+	Key      string
+	Template string
+	Subdir   string
+	Filename string
+	Upstream string
+	Links    []string
+	Tags     []string
+}
+
+type scaffoldNameData struct {
+	Title string
+	Key   string
+}
+
+// ScaffoldFiles resolves the scaffold declared by the named template for the
+// given title. The first entry is the root of the scaffold; an empty Subdir
+// means the caller's default subdirectory.
+func (t *Templater) ScaffoldFiles(name, title string) ([]ScaffoldFile, error) {
+	manifest, err := t.Manifest(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Scaffold) == 0 {
+		return nil, fmt.Errorf("template %s does not define a scaffold", name)
+	}
+	if err := t.validateScaffold(manifest.Scaffold); err != nil {
+		return nil, err
+	}
+
+	files := make([]ScaffoldFile, len(manifest.Scaffold))
+	filenames := make(map[string]string, len(manifest.Scaffold))
+	for i, entry := range manifest.Scaffold {
+		data := scaffoldNameData{Title: title, Key: entry.Key}
+
+		pattern := entry.Filename
+		if pattern == "" {
+			pattern = "{{.Title}}-{{.Key}}"
+			if i == 0 {
+				pattern = "{{.Title}}"
+			}
+		}
+		filename, err := t.renderScaffoldName(pattern, data)
+		if err != nil {
+			return nil, fmt.Errorf("scaffold note %q filename: %w", entry.Key, err)
+		}
+		if filename == "" || strings.ContainsAny(filename, `/\`) {
+			return nil, fmt.Errorf("scaffold note %q has invalid filename %q", entry.Key, filename)
+		}
+
+		subdir, err := t.renderScaffoldName(entry.Subdir, data)
+		if err != nil {
+			return nil, fmt.Errorf("scaffold note %q subdir: %w", entry.Key, err)
+		}
+		if subdir != "" {
+			subdir = filepath.Clean(subdir)
+			if filepath.IsAbs(subdir) || subdir == ".." || strings.HasPrefix(subdir, ".."+string(filepath.Separator)) {
+				return nil, fmt.Errorf("scaffold note %q subdir %q is outside the vault", entry.Key, subdir)
+			}
+		}
+
+		filenames[entry.Key] = filename
+		files[i] = ScaffoldFile{
+			Key:      entry.Key,
+			Template: entry.Template,
+			Subdir:   subdir,
+			Filename: filename,
+			Tags:     append([]string(nil), entry.Tags...),
+		}
+	}
+
+	for i, entry := range manifest.Scaffold {
+		if entry.Up != "" {
+			files[i].Upstream = filenames[entry.Up]
+		}
+		for _, link := range entry.Links {
+			files[i].Links = append(files[i].Links, filenames[link])
+		}
+	}
+	return files, nil
+}
+
+// validateScaffold checks that scaffold keys are unique and that every
+// template and cross-reference exists.
+func (t *Templater) validateScaffold(entries []ScaffoldEntry) error {
+	var problems []error
+	keys := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		switch {
+		case entry.Key == "":
+			problems = append(problems, errors.New("scaffold note is missing a key"))
+		case keys[entry.Key]:
+			problems = append(problems, fmt.Errorf("scaffold note %q is declared more than once", entry.Key))
+		}
+		keys[entry.Key] = true
+
+		if _, ok := t.templates[entry.Template]; !ok {
+			problems = append(problems, fmt.Errorf("scaffold note %q uses unknown template %q", entry.Key, entry.Template))
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Up != "" && !keys[entry.Up] {
+			problems = append(problems, fmt.Errorf("scaffold note %q links up to unknown note %q", entry.Key, entry.Up))
+		}
+		if entry.Up != "" && entry.Up == entry.Key {
+			problems = append(problems, fmt.Errorf("scaffold note %q links up to itself", entry.Key))
+		}
+		for _, link := range entry.Links {
+			if !keys[link] {
+				problems = append(problems, fmt.Errorf("scaffold note %q links to unknown note %q", entry.Key, link))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func (t *Templater) renderScaffoldName(pattern string, data scaffoldNameData) (string, error) {
+	if pattern == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("scaffold").Funcs(t.funcMap()).Parse(pattern)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
@@ -0,0 +1,58 @@
+package templater
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScaffoldFilesResolvesNamesAndLinks(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"launch": "{{/* an:manifest\nscaffold:\n  - key: hub\n    template: zet\n    subdir: \"projects/{{slug .Title}}\"\n    links: [plan]\n  - key: plan\n    template: roadmap\n    subdir: \"projects/{{slug .Title}}\"\n    up: hub\n*/}}\n",
+	})
+
+	files, err := tmpl.ScaffoldFiles("launch", "Big Launch")
+	if err != nil {
+		t.Fatalf("ScaffoldFiles returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if files[0].Filename != "Big Launch" || files[1].Filename != "Big Launch-plan" {
+		t.Fatalf("unexpected filenames %q and %q", files[0].Filename, files[1].Filename)
+	}
+	if files[0].Subdir != "projects/big-launch" {
+		t.Fatalf("unexpected subdir %q", files[0].Subdir)
+	}
+	if files[1].Upstream != "Big Launch" {
+		t.Fatalf("expected plan to link up to the hub, got %q", files[1].Upstream)
+	}
+	if len(files[0].Links) != 1 || files[0].Links[0] != "Big Launch-plan" {
+		t.Fatalf("expected hub to link to the plan, got %v", files[0].Links)
+	}
+}
+
+func TestScaffoldValidationReportsBadReferences(t *testing.T) {
+	tmpl := newWorkspaceTemplater(t, map[string]string{
+		"broken": "{{/* an:manifest\nscaffold:\n  - key: hub\n    template: missing\n  - key: child\n    template: zet\n    up: nowhere\n*/}}\n",
+	})
+
+	if _, err := tmpl.ScaffoldFiles("broken", "x"); err == nil {
+		t.Fatalf("expected scaffold errors")
+	}
+	err := tmpl.Validate("broken")
+	if err == nil {
+		t.Fatalf("expected validation to fail")
+	}
+	for _, want := range []string{`unknown template "missing"`, `unknown note "nowhere"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := tmpl.ScaffoldFiles("zet", "x"); err == nil {
+		t.Fatalf("expected error for template without a scaffold")
+	}
+	if err := tmpl.Validate("project"); err != nil {
+		t.Fatalf("expected built-in project scaffold to validate: %v", err)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"regexp"
 	"sort"
 	"strings"
-	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -44,10 +43,17 @@ var AvailableTemplates = map[string]bool{
 	"review-retro":    true,
 }
 
+// manifestBlockPattern matches the optional manifest comment that opens a template.
+var manifestBlockPattern = regexp.MustCompile(`(?s)^\s*\{\{/\*\s*an:manifest\s*(.*?)\*/\}\}`)
+
 type SingleTemplate struct {
 	FilePath string
 	Content  string
 	Manifest TemplateManifest
+	// Err records why the manifest could not be parsed. The template is still
+	// registered so it can be listed, shown, edited and validated, but it
+	// refuses to render.
+	Err error
 }
 
 type TemplateMap map[string]SingleTemplate
@@ -55,7 +61,11 @@ type TemplateMap map[string]SingleTemplate
 // Templater manages a collection of templates.
 type Templater struct {
 	templates TemplateMap
+	partials  TemplateMap
 	resolved  map[string]resolvedTemplate
+	vault     string
+	index     IndexSource
+	tasks     TaskSource
 }
 
 // TemplateData defines the structure for data that will be passed to templates during rendering.
@@ -76,6 +86,7 @@ type TemplateManifest struct {
 	Preview     string          `yaml:"preview"`
 	Extends     []string        `yaml:"extends"`
 	Fields      []TemplateField `yaml:"fields"`
+	Scaffold    []ScaffoldEntry `yaml:"scaffold,omitempty"`
 }
 
 type TemplateField struct {
@@ -88,11 +99,18 @@ type TemplateField struct {
 	Default  string   `yaml:"default"`
 	Defaults []string `yaml:"defaults"`
 	Multi    bool     `yaml:"multi"`
+	Min      *float64 `yaml:"min,omitempty"`
+	Max      *float64 `yaml:"max,omitempty"`
+	When     string   `yaml:"when,omitempty"`
+	Compute  string   `yaml:"compute,omitempty"`
 }
 
 type resolvedTemplate struct {
 	Content  string
 	Manifest TemplateManifest
+	// Layers holds the body of every template in the inheritance chain,
+	// ancestors first, so each can be parsed on its own.
+	Layers []string
 }
 
 // Templates returns the list of template names known to the templater.
@@ -107,12 +125,16 @@ func (t *Templater) Templates() []string {
 
 func NewTemplater(workspace *config.Workspace) (*Templater, error) {
 	tmplMap := make(TemplateMap)
+	partials := make(TemplateMap)
 
 	if workspace != nil {
 		workspaceTemplateDir := filepath.Join(workspace.VaultDir, ".an", "templates")
 		if err := tmplMap.loadTemplates(workspaceTemplateDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return nil, err
 		}
+		if err := partials.loadPartials(filepath.Join(workspaceTemplateDir, partialsDir)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
 	}
 
 	// Load user templates first to give them precedence.
@@ -125,6 +147,9 @@ func NewTemplater(workspace *config.Workspace) (*Templater, error) {
 	if err := tmplMap.loadTemplates(userTemplateDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return nil, err
 	}
+	if err := partials.loadPartials(filepath.Join(userTemplateDir, partialsDir)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
 
 	for templateName := range tmplMap {
 		AvailableTemplates[templateName] = true
@@ -134,8 +159,15 @@ func NewTemplater(workspace *config.Workspace) (*Templater, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := partials.loadEmbeddedPartials(embeddedTemplates); err != nil {
+		return nil, err
+	}
 
-	return &Templater{templates: tmplMap, resolved: make(map[string]resolvedTemplate)}, nil
+	tmpl := &Templater{templates: tmplMap, partials: partials, resolved: make(map[string]resolvedTemplate)}
+	if workspace != nil {
+		tmpl.vault = workspace.VaultDir
+	}
+	return tmpl, nil
 }
 
 // Execute finds the template by name, validates the data against the expected struct, and renders the template.
@@ -145,9 +177,7 @@ func (t *Templater) Execute(templateName string, data interface{}) (string, erro
 		return "", err
 	}
 
-	tmpl, err := template.New(templateName).Funcs(template.FuncMap{
-		"join": strings.Join,
-	}).Parse(resolved.Content)
+	tmpl, err := parseLayers(templateName, resolved.Layers, t.funcMap())
 	if err != nil {
 		return "", err
 	}
@@ -195,6 +225,10 @@ func (m TemplateMap) loadEmbeddedTemplates(embeddedFS embed.FS) error {
 				return err
 			}
 
+			if d.IsDir() && path == "templates/"+partialsDir {
+				return fs.SkipDir
+			}
+
 			if !d.IsDir() {
 				name := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
 				if _, exists := m[name]; !exists {
@@ -204,14 +238,11 @@ func (m TemplateMap) loadEmbeddedTemplates(embeddedFS embed.FS) error {
 					}
 
 					manifest, body, err := parseManifest(name, string(data))
-					if err != nil {
-						return err
-					}
-
 					m[name] = SingleTemplate{
 						FilePath: path,
 						Manifest: manifest,
 						Content:  body,
+						Err:      err,
 					}
 				}
 			}
@@ -230,6 +261,10 @@ func (m TemplateMap) loadTemplates(dirPath string) error {
 				return err
 			}
 
+			if info.IsDir() && path != dirPath && info.Name() == partialsDir {
+				return filepath.SkipDir
+			}
+
 			if !info.IsDir() && filepath.Ext(path) == ".tmpl" {
 				name := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
 
@@ -240,14 +275,11 @@ func (m TemplateMap) loadTemplates(dirPath string) error {
 					}
 
 					manifest, body, err := parseManifest(name, string(contents))
-					if err != nil {
-						return err
-					}
-
 					m[name] = SingleTemplate{
 						FilePath: path,
 						Manifest: manifest,
 						Content:  body,
+						Err:      err,
 					}
 				}
 			}
@@ -260,11 +292,11 @@ func parseManifest(name, content string) (TemplateManifest, string, error) {
 	manifest := TemplateManifest{Name: name}
 	cleaned := content
 
-	manifestPattern := regexp.MustCompile(`(?s)^\s*\{\{/\*\s*an:manifest\s*(.*?)\*/\}\}`)
-	if loc := manifestPattern.FindStringSubmatchIndex(content); loc != nil {
+	if loc := manifestBlockPattern.FindStringSubmatchIndex(content); loc != nil {
 		raw := content[loc[2]:loc[3]]
 		if err := yaml.Unmarshal([]byte(raw), &manifest); err != nil {
-			return manifest, "", fmt.Errorf("failed to parse manifest for template %s: %w", name, err)
+			body := strings.TrimLeft(content[loc[1]:], "\r\n")
+			return TemplateManifest{Name: name}, body, fmt.Errorf("failed to parse manifest for template %s: %w", name, err)
 		}
 		if manifest.Name == "" {
 			manifest.Name = name
@@ -321,12 +353,16 @@ func (t *Templater) resolveTemplateWithVisited(name string, visited map[string]b
 	if !ok {
 		return resolvedTemplate{}, errors.New("template not found")
 	}
+	if tmplData.Err != nil {
+		return resolvedTemplate{}, tmplData.Err
+	}
 
 	combined := tmplData.Manifest
 	combined.Fields = nil
 
 	var builder strings.Builder
 	var collectedFields []TemplateField
+	var layers []string
 	previews := make([]string, 0)
 
 	for _, parent := range tmplData.Manifest.Extends {
@@ -335,6 +371,7 @@ func (t *Templater) resolveTemplateWithVisited(name string, visited map[string]b
 			return resolvedTemplate{}, err
 		}
 		builder.WriteString(parentResolved.Content)
+		layers = append(layers, parentResolved.Layers...)
 		collectedFields = append(collectedFields, parentResolved.Manifest.Fields...)
 		if parentResolved.Manifest.Preview != "" {
 			previews = append(previews, parentResolved.Manifest.Preview)
@@ -342,6 +379,7 @@ func (t *Templater) resolveTemplateWithVisited(name string, visited map[string]b
 	}
 
 	builder.WriteString(tmplData.Content)
+	layers = append(layers, tmplData.Content)
 
 	collectedFields = mergeFields(collectedFields, tmplData.Manifest.Fields)
 	combined.Fields = collectedFields
@@ -353,6 +391,7 @@ func (t *Templater) resolveTemplateWithVisited(name string, visited map[string]b
 	resolved := resolvedTemplate{
 		Content:  builder.String(),
 		Manifest: combined,
+		Layers:   layers,
 	}
 
 	if t.resolved == nil {
@@ -437,7 +476,7 @@ func marshalFrontMatter(data map[string]interface{}) (string, error) {
 	}
 	sort.Strings(keys)
 	for _, key := range keys {
-		ordered[key] = data[key]
+		ordered[key] = frontMatterValue(data[key])
 	}
 
 	buf, err := yaml.Marshal(ordered)
@@ -448,6 +487,25 @@ func marshalFrontMatter(data map[string]interface{}) (string, error) {
 	return string(buf), nil
 }
 
+// frontMatterValue keeps typed values intact for YAML encoding. Timestamps at
+// midnight UTC, which is how YAML dates decode, are written back as plain
+// dates rather than full timestamps.
+func frontMatterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		if v.Location() == time.UTC && v.Equal(truncateDay(v)) {
+			return Date{v}
+		}
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, entry := range v {
+			converted[i] = frontMatterValue(entry)
+		}
+		return converted
+	}
+	return value
+}
+
 // Manifest returns the resolved manifest for the provided template, applying inheritance rules.
 func (t *Templater) Manifest(name string) (TemplateManifest, error) {
 	resolved, err := t.resolveTemplate(name)
@@ -0,0 +1,197 @@
+// Package textdiff computes line diffs between two versions of a note and
+// renders them in unified format.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op says whether a line is kept, removed, or added.
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxCells bounds the table used to align the changed middle of two texts.
+// Larger changes are reported as a removal followed by an addition.
+const maxCells = 4_000_000
+
+// Lines returns the line diff that turns a into b.
+func Lines(a, b string) []Line {
+	from, to := split(a), split(b)
+
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix &&
+		from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]Line, 0, len(from)+len(to))
+	for _, text := range from[:prefix] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	diff = append(diff, align(from[prefix:len(from)-suffix], to[prefix:len(to)-suffix])...)
+	for _, text := range from[len(from)-suffix:] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	return diff
+}
+
+// align diffs the changed middle of two texts by longest common
+// subsequence.
+func align(from, to []string) []Line {
+	n, m := len(from), len(to)
+	if n*m > maxCells {
+		diff := make([]Line, 0, n+m)
+		for _, text := range from {
+			diff = append(diff, Line{Op: Delete, Text: text})
+		}
+		for _, text := range to {
+			diff = append(diff, Line{Op: Insert, Text: text})
+		}
+		return diff
+	}
+
+	// lcs[i][j] is the common subsequence length of from[i:] and to[j:].
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case from[i] == to[j]:
+			diff = append(diff, Line{Op: Equal, Text: from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, Line{Op: Delete, Text: from[i]})
+			i++
+		default:
+			diff = append(diff, Line{Op: Insert, Text: to[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		diff = append(diff, Line{Op: Delete, Text: from[i]})
+	}
+	for ; j < m; j++ {
+		diff = append(diff, Line{Op: Insert, Text: to[j]})
+	}
+	return diff
+}
+
+// Unified renders the diff from a to b in unified format with the given
+// number of context lines. It returns an empty string when the texts are
+// equal.
+func Unified(fromName, toName, a, b string, context int) string {
+	diff := Lines(a, b)
+	if context < 0 {
+		context = 0
+	}
+
+	// fromLine and toLine are the 1-based line numbers at each diff entry.
+	fromLine := make([]int, len(diff)+1)
+	toLine := make([]int, len(diff)+1)
+	fromLine[0], toLine[0] = 1, 1
+	for i, line := range diff {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if line.Op != Insert {
+			fromLine[i+1]++
+		}
+		if line.Op != Delete {
+			toLine[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(diff); {
+		if diff[i].Op == Equal {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		for end < len(diff) {
+			if diff[end].Op != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(diff) && diff[run].Op == Equal {
+				run++
+			}
+			if run == len(diff) || run-end > 2*context {
+				end = min(end+context, len(diff))
+				break
+			}
+			end = run
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[start], fromLine[end]-fromLine[start]),
+			hunkRange(toLine[start], toLine[end]-toLine[start]))
+		for _, line := range diff[start:end] {
+			switch line.Op {
+			case Equal:
+				out.WriteString(" ")
+			case Delete:
+				out.WriteString("-")
+			case Insert:
+				out.WriteString("+")
+			}
+			out.WriteString(line.Text)
+			out.WriteString("\n")
+		}
+		i = end
+	}
+	return out.String()
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,29 @@
+package textdiff
+
+import "testing"
+
+func TestUnifiedRendersHunks(t *testing.T) {
+	a := "title: a\nstatus: building\none\ntwo\nthree\nfour\nfive\nsix\nseven\nend\n"
+	b := "title: a\nstatus: shipped\none\ntwo\nthree\nfour\nfive\nsix\nseven\nend\nextra\n"
+
+	got := Unified("a/note.md", "b/note.md", a, b, 1)
+	want := "--- a/note.md\n+++ b/note.md\n" +
+		"@@ -1,3 +1,3 @@\n title: a\n-status: building\n+status: shipped\n one\n" +
+		"@@ -10 +10,2 @@\n end\n+extra\n"
+	if got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedEqualTexts(t *testing.T) {
+	if got := Unified("a", "b", "same\n", "same\n", 3); got != "" {
+		t.Fatalf("expected no diff, got %q", got)
+	}
+}
+
+func TestLinesFromEmpty(t *testing.T) {
+	diff := Lines("", "new\n")
+	if len(diff) != 1 || diff[0].Op != Insert || diff[0].Text != "new" {
+		t.Fatalf("unexpected diff %#v", diff)
+	}
+}
@@ -0,0 +1,42 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// ParseAge parses the durations used across the configuration, such as
+// review ages, trash retention and history limits. In addition to Go
+// duration strings (for example "36h"), it accepts whole-day ("3d") and
+// whole-week ("2w") suffixes.
+func ParseAge(value string) (time.Duration, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(value))
+	if trimmed == "" {
+		return 0, fmt.Errorf("duration is empty")
+	}
+
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(trimmed, "d"):
+		unit = day
+	case strings.HasSuffix(trimmed, "w"):
+		unit = 7 * day
+	}
+	if unit > 0 {
+		count, err := strconv.Atoi(strings.TrimSpace(trimmed[:len(trimmed)-1]))
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(count) * unit, nil
+	}
+
+	parsed, err := time.ParseDuration(trimmed)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return parsed, nil
+}
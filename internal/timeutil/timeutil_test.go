@@ -0,0 +1,28 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]time.Duration{
+		"36h": 36 * time.Hour,
+		"3d":  3 * 24 * time.Hour,
+		"2W":  14 * 24 * time.Hour,
+		" 0d": 0,
+	}
+	for input, want := range cases {
+		got, err := ParseAge(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseAge(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "soon", "-1d", "1.5d"} {
+		if _, err := ParseAge(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
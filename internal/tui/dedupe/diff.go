@@ -0,0 +1,63 @@
+package dedupe
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/Paintersrp/an/internal/textdiff"
+)
+
+// sideBySide renders the line diff from left to right as two columns of the
+// given width. Removed and added lines in the same change are paired on one
+// row so the columns stay aligned.
+func sideBySide(left, right string, width int) []string {
+	if width < 8 {
+		width = 8
+	}
+	diff := textdiff.Lines(left, right)
+
+	var out []string
+	for i := 0; i < len(diff); {
+		if diff[i].Op == textdiff.Equal {
+			out = append(out, row(cell(" ", diff[i].Text, width, equalStyle), cell(" ", diff[i].Text, width, equalStyle)))
+			i++
+			continue
+		}
+
+		var removed, added []string
+		for ; i < len(diff) && diff[i].Op != textdiff.Equal; i++ {
+			if diff[i].Op == textdiff.Delete {
+				removed = append(removed, diff[i].Text)
+			} else {
+				added = append(added, diff[i].Text)
+			}
+		}
+		for j := 0; j < max(len(removed), len(added)); j++ {
+			l, r := blank(width), blank(width)
+			if j < len(removed) {
+				l = cell("-", removed[j], width, deleteStyle)
+			}
+			if j < len(added) {
+				r = cell("+", added[j], width, insertStyle)
+			}
+			out = append(out, row(l, r))
+		}
+	}
+	return out
+}
+
+func row(left, right string) string {
+	return left + separatorStyle.Render(" │ ") + right
+}
+
+func cell(marker, text string, width int, style lipgloss.Style) string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	text = truncate.StringWithTail(marker+" "+text, uint(width), "…")
+	return style.Width(width).Render(text)
+}
+
+func blank(width int) string {
+	return strings.Repeat(" ", width)
+}
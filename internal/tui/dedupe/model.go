@@ -0,0 +1,329 @@
+// Package dedupe reviews clusters of likely duplicate notes one pair at a
+// time, with a side-by-side diff, a merge action, and a "not duplicate"
+// mark that later runs remember.
+package dedupe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	dedupesvc "github.com/Paintersrp/an/internal/dedupe"
+	"github.com/Paintersrp/an/internal/relocate"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+type keyMap struct {
+	next     key.Binding
+	prev     key.Binding
+	down     key.Binding
+	up       key.Binding
+	swap     key.Binding
+	merge    key.Binding
+	distinct key.Binding
+	confirm  key.Binding
+	cancel   key.Binding
+	quit     key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		next:     key.NewBinding(key.WithKeys("n", "right", "l")),
+		prev:     key.NewBinding(key.WithKeys("p", "left", "h")),
+		down:     key.NewBinding(key.WithKeys("j", "down")),
+		up:       key.NewBinding(key.WithKeys("k", "up")),
+		swap:     key.NewBinding(key.WithKeys("s")),
+		merge:    key.NewBinding(key.WithKeys("m")),
+		distinct: key.NewBinding(key.WithKeys("x")),
+		confirm:  key.NewBinding(key.WithKeys("y")),
+		cancel:   key.NewBinding(key.WithKeys("n", "esc")),
+		quit:     key.NewBinding(key.WithKeys("q", "esc", "ctrl+c")),
+	}
+}
+
+// Result summarizes a review session.
+type Result struct {
+	Merged int
+	Marked int
+}
+
+// item is one pair under review.
+type item struct {
+	cluster int
+	pair    dedupesvc.Pair
+}
+
+// Model steps through the pairs of every cluster. The left note is the one
+// kept by a merge.
+type Model struct {
+	state      *state.State
+	clusters   []dedupesvc.Cluster
+	decisions  *dedupesvc.Decisions
+	keys       keyMap
+	pos        int
+	swapped    bool
+	offset     int
+	confirming bool
+	removed    map[string]bool
+	contents   map[string]string
+	status     string
+	result     Result
+	width      int
+	height     int
+}
+
+type mergedMsg struct {
+	into   string
+	source string
+	err    error
+}
+
+// NewModel reviews clusters found in the vault of st. Decisions hold the
+// "not duplicate" marks and are saved whenever one is added.
+func NewModel(st *state.State, clusters []dedupesvc.Cluster, decisions *dedupesvc.Decisions) (*Model, error) {
+	if st == nil || st.Handler == nil || decisions == nil {
+		return nil, fmt.Errorf("dedupe review requires configured state dependencies")
+	}
+	return &Model{
+		state:     st,
+		clusters:  clusters,
+		decisions: decisions,
+		keys:      newKeyMap(),
+		removed:   make(map[string]bool),
+		contents:  make(map[string]string),
+		width:     120,
+		height:    30,
+	}, nil
+}
+
+// Run reviews the clusters and reports what was merged and marked.
+func Run(st *state.State, clusters []dedupesvc.Cluster, decisions *dedupesvc.Decisions) (Result, error) {
+	model, err := NewModel(st, clusters, decisions)
+	if err != nil {
+		return Result{}, err
+	}
+	final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if err != nil {
+		return Result{}, err
+	}
+	if m, ok := final.(*Model); ok {
+		return m.result, nil
+	}
+	return Result{}, nil
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case mergedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Merge failed: %v", msg.err)
+			return m, nil
+		}
+		m.removed[msg.source] = true
+		delete(m.contents, msg.into)
+		m.result.Merged++
+		m.status = fmt.Sprintf("Merged %s into %s.", m.rel(msg.source), m.rel(msg.into))
+		return m, m.settle()
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if m.confirming {
+		switch {
+		case key.Matches(msg, m.keys.confirm):
+			m.confirming = false
+			m.status = "Merging..."
+			return m.merge()
+		case key.Matches(msg, m.keys.cancel):
+			m.confirming = false
+			m.status = "Merge cancelled."
+		}
+		return nil
+	}
+
+	current, ok := m.current()
+	switch {
+	case key.Matches(msg, m.keys.quit):
+		return tea.Quit
+	case !ok:
+		return nil
+	case key.Matches(msg, m.keys.next):
+		m.move(1)
+	case key.Matches(msg, m.keys.prev):
+		m.move(-1)
+	case key.Matches(msg, m.keys.down):
+		m.offset++
+	case key.Matches(msg, m.keys.up):
+		m.offset = max(m.offset-1, 0)
+	case key.Matches(msg, m.keys.swap):
+		m.swapped = !m.swapped
+	case key.Matches(msg, m.keys.merge):
+		m.confirming = true
+		left, right := m.sides(current)
+		m.status = fmt.Sprintf("Merge %s into %s and trash it? y/n", m.rel(right), m.rel(left))
+	case key.Matches(msg, m.keys.distinct):
+		m.decisions.MarkDistinct(current.pair.A, current.pair.B)
+		if err := m.decisions.Save(); err != nil {
+			m.status = fmt.Sprintf("Failed to save decision: %v", err)
+			return nil
+		}
+		m.result.Marked++
+		m.status = fmt.Sprintf("Marked %s and %s as not duplicates.", m.rel(current.pair.A), m.rel(current.pair.B))
+		return m.settle()
+	}
+	return nil
+}
+
+func (m *Model) View() string {
+	current, ok := m.current()
+	if !ok {
+		done := "No likely duplicates left to review."
+		if m.status != "" {
+			done = m.status + "\n" + done
+		}
+		return appStyle.Render(done + "\n" + helpStyle.Render("q quit"))
+	}
+
+	items := m.items()
+	number, total := 0, 0
+	order := make(map[int]int)
+	for i, it := range items {
+		if _, ok := order[it.cluster]; !ok {
+			order[it.cluster] = len(order) + 1
+		}
+		if it.cluster == current.cluster {
+			total++
+			if i <= m.pos {
+				number++
+			}
+		}
+	}
+
+	header := headerStyle.Render(fmt.Sprintf(
+		"Cluster %d of %d · pair %d of %d · %.0f%% similar",
+		order[current.cluster], len(order), number, total, current.pair.Similarity*100,
+	))
+
+	left, right := m.sides(current)
+	column := max((m.width-8)/2, 8)
+	titles := lipgloss.NewStyle().Width(column).Render(titleStyle.Render(m.rel(left)+" (kept)")) +
+		"   " + titleStyle.Render(m.rel(right))
+
+	lines := sideBySide(m.content(left), m.content(right), column)
+	visible := max(m.height-10, 5)
+	m.offset = min(m.offset, max(len(lines)-visible, 0))
+	end := min(m.offset+visible, len(lines))
+
+	sections := []string{header, titles, strings.Join(lines[m.offset:end], "\n")}
+	if m.status != "" {
+		sections = append(sections, statusStyle.Render(m.status))
+	}
+	sections = append(sections, helpStyle.Render("n/p next/previous · j/k scroll · s swap · m merge right into left · x not duplicate · q quit"))
+	return appStyle.Render(strings.Join(sections, "\n\n"))
+}
+
+// items lists the pairs still open: both notes exist and the pair has not
+// been marked as distinct.
+func (m *Model) items() []item {
+	var out []item
+	for i, cluster := range m.clusters {
+		for _, pair := range cluster.Pairs {
+			if m.removed[pair.A] || m.removed[pair.B] || m.decisions.Distinct(pair.A, pair.B) {
+				continue
+			}
+			out = append(out, item{cluster: i, pair: pair})
+		}
+	}
+	return out
+}
+
+func (m *Model) current() (item, bool) {
+	items := m.items()
+	if len(items) == 0 {
+		return item{}, false
+	}
+	m.pos = min(max(m.pos, 0), len(items)-1)
+	return items[m.pos], true
+}
+
+func (m *Model) move(delta int) {
+	items := m.items()
+	if len(items) == 0 {
+		return
+	}
+	m.pos = (m.pos + delta + len(items)) % len(items)
+	m.offset = 0
+	m.swapped = false
+	m.status = ""
+}
+
+// settle keeps the cursor in range after a pair was resolved and quits once
+// nothing is left.
+func (m *Model) settle() tea.Cmd {
+	m.offset = 0
+	m.swapped = false
+	if _, ok := m.current(); !ok {
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *Model) sides(it item) (string, string) {
+	if m.swapped {
+		return it.pair.B, it.pair.A
+	}
+	return it.pair.A, it.pair.B
+}
+
+func (m *Model) merge() tea.Cmd {
+	current, ok := m.current()
+	if !ok {
+		return nil
+	}
+	into, source := m.sides(current)
+	h := m.state.Handler
+	return func() tea.Msg {
+		files, err := h.WalkFiles(nil, nil, "")
+		if err != nil {
+			return mergedMsg{into: into, source: source, err: err}
+		}
+		plan, err := relocate.Merge(h.VaultDir(), []string{source}, into, files)
+		if err != nil {
+			return mergedMsg{into: into, source: source, err: err}
+		}
+		return mergedMsg{into: into, source: source, err: plan.Apply(h)}
+	}
+}
+
+func (m *Model) content(path string) string {
+	if content, ok := m.contents[path]; ok {
+		return content
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("(unreadable: %v)", err)
+	}
+	m.contents[path] = string(data)
+	return string(data)
+}
+
+func (m *Model) rel(path string) string {
+	if rel, err := filepath.Rel(m.state.Handler.VaultDir(), path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
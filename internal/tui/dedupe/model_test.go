@@ -0,0 +1,122 @@
+package dedupe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	dedupesvc "github.com/Paintersrp/an/internal/dedupe"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+const body = "Spaced repetition works because each review interrupts forgetting " +
+	"just before the memory fades, and every successful recall makes the next interval longer.\n"
+
+func newDedupeModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	vault := t.TempDir()
+	notes := map[string]string{
+		"a.md":   "---\ntitle: a\n---\n\n" + body,
+		"b.md":   "---\ntitle: b\n---\n\n" + body,
+		"c.md":   "---\ntitle: c\n---\n\n" + body + "Plus one more line.\n",
+		"hub.md": "See [[b]].\n",
+	}
+	var docs []dedupesvc.Note
+	for name, content := range notes {
+		path := filepath.Join(vault, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		if name != "hub.md" {
+			docs = append(docs, dedupesvc.Note{Path: path, Body: body})
+		}
+	}
+
+	decisions, err := dedupesvc.LoadDecisions(vault)
+	if err != nil {
+		t.Fatalf("LoadDecisions returned error: %v", err)
+	}
+	st := &state.State{Handler: handler.NewFileHandler(vault), Vault: vault}
+	m, err := NewModel(st, dedupesvc.Find(docs, dedupesvc.DefaultOptions()), decisions)
+	if err != nil {
+		t.Fatalf("NewModel returned error: %v", err)
+	}
+	return m, vault
+}
+
+func press(m *Model, keys string) tea.Cmd {
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(keys)})
+	return cmd
+}
+
+func TestModelMarksPairsAsNotDuplicates(t *testing.T) {
+	m, vault := newDedupeModel(t)
+	if got := len(m.items()); got != 3 {
+		t.Fatalf("expected three pairs to review, got %d", got)
+	}
+
+	current, _ := m.current()
+	press(m, "x")
+	if len(m.items()) != 2 || m.result.Marked != 1 {
+		t.Fatalf("expected the marked pair to be resolved")
+	}
+
+	decisions, err := dedupesvc.LoadDecisions(vault)
+	if err != nil || !decisions.Distinct(current.pair.A, current.pair.B) {
+		t.Fatalf("expected the mark to be saved, got %v", err)
+	}
+}
+
+func TestModelMergesRightIntoLeft(t *testing.T) {
+	m, vault := newDedupeModel(t)
+	a, b := filepath.Join(vault, "a.md"), filepath.Join(vault, "b.md")
+	for {
+		current, _ := m.current()
+		if current.pair.A == a && current.pair.B == b {
+			break
+		}
+		press(m, "n")
+	}
+
+	if cmd := press(m, "m"); cmd != nil || !strings.Contains(m.status, "Merge b.md into a.md") {
+		t.Fatalf("expected a confirmation prompt, got %q", m.status)
+	}
+	cmd := press(m, "y")
+	if cmd == nil {
+		t.Fatalf("expected confirming to start the merge")
+	}
+	m.Update(cmd())
+
+	if m.result.Merged != 1 {
+		t.Fatalf("expected the merge to succeed, status %q", m.status)
+	}
+	if _, err := os.Stat(b); !os.IsNotExist(err) {
+		t.Fatalf("expected the right note to be trashed")
+	}
+	data, err := os.ReadFile(filepath.Join(vault, "hub.md"))
+	if err != nil || string(data) != "See [[a]].\n" {
+		t.Fatalf("expected backlinks to follow the merge, got %q (%v)", data, err)
+	}
+	for _, it := range m.items() {
+		if it.pair.A == b || it.pair.B == b {
+			t.Fatalf("expected pairs with the merged note to be dropped")
+		}
+	}
+}
+
+func TestSideBySideAlignsChanges(t *testing.T) {
+	lines := sideBySide("same\nold\nend\n", "same\nnew\nextra\nend\n", 12)
+	if len(lines) != 4 {
+		t.Fatalf("expected four rows, got %d: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[1], "- old") || !strings.Contains(lines[1], "+ new") {
+		t.Fatalf("expected the change on one row, got %q", lines[1])
+	}
+	if !strings.HasSuffix(strings.TrimRight(lines[2], " "), "+ extra") {
+		t.Fatalf("expected the extra line on the right only, got %q", lines[2])
+	}
+}
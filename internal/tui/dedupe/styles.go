@@ -0,0 +1,23 @@
+package dedupe
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	appStyle = lipgloss.NewStyle().Padding(1, 2)
+
+	headerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#0AF")).
+			Bold(true)
+
+	titleStyle = lipgloss.NewStyle().Bold(true)
+
+	statusStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#0AF", Dark: "#0AF"})
+
+	helpStyle = lipgloss.NewStyle().Faint(true)
+
+	equalStyle     = lipgloss.NewStyle()
+	deleteStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#E06C75"))
+	insertStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#98C379"))
+	separatorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
@@ -0,0 +1,75 @@
+package inbox
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type keyMap struct {
+	move     key.Binding
+	tag      key.Binding
+	upstream key.Binding
+	merge    key.Binding
+	task     key.Binding
+	archive  key.Binding
+	trash    key.Binding
+	skip     key.Binding
+	open     key.Binding
+	refresh  key.Binding
+	quit     key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		move: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "move"),
+		),
+		tag: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "tag"),
+		),
+		upstream: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "upstream"),
+		),
+		merge: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "merge into"),
+		),
+		task: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "to task"),
+		),
+		archive: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "archive"),
+		),
+		trash: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "trash"),
+		),
+		skip: key.NewBinding(
+			key.WithKeys("s", "right", "l"),
+			key.WithHelp("s", "skip"),
+		),
+		open: key.NewBinding(
+			key.WithKeys("enter", "o"),
+			key.WithHelp("↵", "open"),
+		),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+func (k keyMap) bindings() []key.Binding {
+	return []key.Binding{
+		k.move, k.tag, k.upstream, k.merge, k.task,
+		k.archive, k.trash, k.skip, k.open, k.quit,
+	}
+}
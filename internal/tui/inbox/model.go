@@ -0,0 +1,440 @@
+// Package inbox implements the one-note-at-a-time inbox triage TUI.
+package inbox
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/note"
+	svc "github.com/Paintersrp/an/internal/services/inbox"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/utils"
+)
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeMove
+	modeTag
+	modeUpstream
+	modeMerge
+	modeTask
+)
+
+const defaultTaskPin = "default"
+
+type Model struct {
+	service   *svc.Service
+	state     *state.State
+	workspace *config.Workspace
+	items     []svc.Item
+	index     int
+	mode      mode
+	picker    picker
+	input     textinput.Model
+	keys      keyMap
+	preview   string
+	status    string
+	triaged   int
+	width     int
+	height    int
+}
+
+type editorFinishedMsg struct {
+	err error
+}
+
+func NewModel(s *state.State) (*Model, error) {
+	if s == nil || s.Handler == nil || s.Config == nil {
+		return nil, fmt.Errorf("inbox model requires configured state dependencies")
+	}
+	ws := s.Workspace
+	if ws == nil {
+		active, err := s.Config.ActiveWorkspace()
+		if err != nil {
+			return nil, err
+		}
+		ws = active
+	}
+
+	service := svc.NewService(s.Handler, ws.Inbox)
+	items, err := service.List()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{
+		service:   service,
+		state:     s,
+		workspace: ws,
+		items:     items,
+		keys:      newKeyMap(),
+	}
+	m.loadPreview()
+	return m, nil
+}
+
+// Run starts the inbox triage TUI.
+func Run(s *state.State) error {
+	model, err := NewModel(s)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Editor error: %v", msg.err)
+		}
+		m.loadPreview()
+		return m, nil
+	case tea.KeyMsg:
+		if m.mode != modeBrowse {
+			return m.updateInput(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.quit) {
+		return m, tea.Quit
+	}
+	if key.Matches(msg, m.keys.refresh) {
+		return m, m.reload("Inbox refreshed")
+	}
+
+	item, ok := m.current()
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.skip):
+		m.index = (m.index + 1) % len(m.items)
+		m.status = ""
+		m.loadPreview()
+	case key.Matches(msg, m.keys.open):
+		return m, m.openEditor(item.Path)
+	case key.Matches(msg, m.keys.move):
+		m.startPicker(modeMove, "Move to", m.subdirs(), m.workspace.FileSystemMode == "free")
+	case key.Matches(msg, m.keys.tag):
+		m.startTagInput()
+	case key.Matches(msg, m.keys.upstream):
+		m.startNotePicker(modeUpstream, "Upstream")
+	case key.Matches(msg, m.keys.merge):
+		m.startNotePicker(modeMerge, "Merge into")
+	case key.Matches(msg, m.keys.task):
+		pins := m.taskPins()
+		if len(pins) == 0 {
+			m.status = "No task pins configured"
+			return m, nil
+		}
+		m.startPicker(modeTask, "Task pin", pins, false)
+	case key.Matches(msg, m.keys.archive):
+		return m, m.resolve(m.service.Archive(item.Path), "Archived "+item.Title)
+	case key.Matches(msg, m.keys.trash):
+		return m, m.resolve(m.service.Trash(item.Path), "Trashed "+item.Title)
+	}
+	return m, nil
+}
+
+func (m *Model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" || msg.String() == "ctrl+c" {
+		m.mode = modeBrowse
+		m.status = ""
+		return m, nil
+	}
+
+	if m.mode == modeTag {
+		if msg.String() == "enter" {
+			return m, m.applyTags(m.input.Value())
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	value, done, cmd := m.picker.update(msg)
+	if !done {
+		return m, cmd
+	}
+	return m, m.applyPick(value)
+}
+
+func (m *Model) applyPick(value string) tea.Cmd {
+	item, ok := m.current()
+	if !ok {
+		m.mode = modeBrowse
+		return nil
+	}
+	picked := m.mode
+	m.mode = modeBrowse
+
+	switch picked {
+	case modeMove:
+		if !m.workspace.HasSubdir(value) {
+			if err := m.state.Config.AddSubdir(value); err != nil {
+				m.status = fmt.Sprintf("Move failed: %v", err)
+				return nil
+			}
+		}
+		_, err := m.service.Move(item.Path, value)
+		return m.resolve(err, fmt.Sprintf("Moved %s to %s", item.Title, value))
+	case modeUpstream:
+		if err := m.service.SetUpstream(item.Path, value); err != nil {
+			m.status = fmt.Sprintf("Upstream failed: %v", err)
+			return nil
+		}
+		m.status = fmt.Sprintf("Upstream set to %s", strings.TrimSuffix(filepath.Base(value), ".md"))
+		m.loadPreview()
+	case modeMerge:
+		target := filepath.Join(m.state.Handler.VaultDir(), filepath.FromSlash(value))
+		return m.resolve(m.service.Merge(item.Path, target), fmt.Sprintf("Merged %s into %s", item.Title, value))
+	case modeTask:
+		path := m.taskPinPath(value)
+		return m.resolve(m.service.ToTask(item.Path, path), fmt.Sprintf("Added %s as a task on %s", item.Title, value))
+	}
+	return nil
+}
+
+func (m *Model) applyTags(raw string) tea.Cmd {
+	m.mode = modeBrowse
+	item, ok := m.current()
+	if !ok || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	fields := strings.Fields(strings.ReplaceAll(raw, ",", " "))
+	tags, err := utils.ValidateTags(strings.Join(fields, " "))
+	if err != nil {
+		m.status = fmt.Sprintf("Invalid tags: %v", err)
+		return nil
+	}
+	if err := m.service.AddTags(item.Path, tags); err != nil {
+		m.status = fmt.Sprintf("Tagging failed: %v", err)
+		return nil
+	}
+	m.status = fmt.Sprintf("Tagged %s: %s", item.Title, strings.Join(tags, ", "))
+	m.loadPreview()
+	return nil
+}
+
+// resolve reports the outcome of an action that takes the current note out of
+// the inbox and moves on to the next note.
+func (m *Model) resolve(err error, success string) tea.Cmd {
+	if err != nil {
+		m.status = fmt.Sprintf("Action failed: %v", err)
+		return nil
+	}
+	m.triaged++
+	return m.reload(success)
+}
+
+func (m *Model) reload(status string) tea.Cmd {
+	items, err := m.service.List()
+	if err != nil {
+		m.status = fmt.Sprintf("Refresh failed: %v", err)
+		return nil
+	}
+	m.items = items
+	if m.index >= len(m.items) {
+		m.index = 0
+	}
+	m.status = status
+	m.loadPreview()
+	return m.state.IndexHeartbeatCmd()
+}
+
+func (m *Model) current() (svc.Item, bool) {
+	if m.index < 0 || m.index >= len(m.items) {
+		return svc.Item{}, false
+	}
+	return m.items[m.index], true
+}
+
+func (m *Model) loadPreview() {
+	item, ok := m.current()
+	if !ok {
+		m.preview = ""
+		return
+	}
+	data, err := m.state.Handler.ReadFile(item.Path)
+	if err != nil {
+		m.preview = fmt.Sprintf("Unable to read note: %v", err)
+		return
+	}
+	m.preview = strings.TrimRight(string(data), "\n")
+}
+
+func (m *Model) startPicker(next mode, prompt string, options []string, allowNew bool) {
+	m.mode = next
+	m.picker = newPicker(prompt, options, allowNew)
+	m.status = ""
+}
+
+func (m *Model) startNotePicker(next mode, prompt string) {
+	notes, err := m.service.Notes()
+	if err != nil {
+		m.status = fmt.Sprintf("Unable to list notes: %v", err)
+		return
+	}
+	if item, ok := m.current(); ok {
+		notes = removeString(notes, item.Rel)
+	}
+	m.startPicker(next, prompt, notes, false)
+}
+
+func (m *Model) startTagInput() {
+	input := textinput.New()
+	input.Prompt = "Tags: "
+	input.Placeholder = "space separated"
+	input.Focus()
+	m.input = input
+	m.mode = modeTag
+	m.status = ""
+}
+
+func (m *Model) subdirs() []string {
+	dirs := make([]string, 0, len(m.workspace.SubDirs))
+	for _, dir := range m.workspace.SubDirs {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func (m *Model) taskPins() []string {
+	var pins []string
+	if m.workspace.PinnedTaskFile != "" {
+		pins = append(pins, defaultTaskPin)
+	}
+	named := make([]string, 0, len(m.workspace.NamedTaskPins))
+	for name, path := range m.workspace.NamedTaskPins {
+		if path != "" {
+			named = append(named, name)
+		}
+	}
+	sort.Strings(named)
+	return append(pins, named...)
+}
+
+func (m *Model) taskPinPath(name string) string {
+	if name == defaultTaskPin {
+		return m.workspace.PinnedTaskFile
+	}
+	return m.workspace.NamedTaskPins[name]
+}
+
+func (m *Model) openEditor(path string) tea.Cmd {
+	launch, err := note.EditorLaunchForPath(path, false)
+	if err != nil {
+		m.status = fmt.Sprintf("Open error: %v", err)
+		return nil
+	}
+	if !launch.Wait {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: launch.Cmd.Start()}
+		}
+	}
+	return tea.ExecProcess(launch.Cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+func (m *Model) View() string {
+	header := titleStyle.Render("Inbox")
+	if len(m.items) > 0 {
+		header += mutedStyle.Render(fmt.Sprintf("  %d of %d", m.index+1, len(m.items)))
+	}
+	if m.triaged > 0 {
+		header += mutedStyle.Render(fmt.Sprintf(" · %d triaged", m.triaged))
+	}
+
+	item, ok := m.current()
+	if !ok {
+		body := "Inbox is empty."
+		if m.status != "" {
+			body = statusStyle.Render(m.status) + "\n\n" + body
+		}
+		return appStyle.Render(header + "\n\n" + body + "\n\n" + mutedStyle.Render("q quit"))
+	}
+
+	sections := []string{header, selectedStyle.Render(item.Rel), m.renderPreview()}
+
+	switch m.mode {
+	case modeBrowse:
+		sections = append(sections, m.helpView())
+	case modeTag:
+		sections = append(sections, m.input.View())
+	default:
+		sections = append(sections, m.picker.view())
+	}
+	if m.status != "" {
+		sections = append(sections, statusStyle.Render(m.status))
+	}
+	return appStyle.Render(strings.Join(sections, "\n\n"))
+}
+
+func (m *Model) renderPreview() string {
+	lines := strings.Split(m.preview, "\n")
+	limit := m.height - 18
+	if m.mode != modeBrowse && m.mode != modeTag {
+		limit -= pickerRows
+	}
+	if limit < 5 {
+		limit = 5
+	}
+	if len(lines) > limit {
+		lines = append(lines[:limit], mutedStyle.Render("…"))
+	}
+
+	style := previewStyle
+	if m.width > 8 {
+		style = style.Width(m.width - 8)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) helpView() string {
+	parts := make([]string, 0, len(m.keys.bindings()))
+	for _, binding := range m.keys.bindings() {
+		help := binding.Help()
+		parts = append(parts, lipgloss.JoinHorizontal(lipgloss.Top, selectedStyle.Render(help.Key), " "+help.Desc))
+	}
+	return strings.Join(parts, mutedStyle.Render(" • "))
+}
+
+func removeString(values []string, target string) []string {
+	out := values[:0]
+	for _, value := range values {
+		if value != target {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+func newTestModel(t *testing.T, files map[string]string) (*Model, string, *config.Workspace) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+
+	ws := &config.Workspace{
+		VaultDir:       dir,
+		SubDirs:        []string{"projects"},
+		PinnedTaskFile: filepath.Join(dir, "tasks.md"),
+		NamedTaskPins:  config.PinMap{},
+	}
+	cfg := &config.Config{Workspaces: map[string]*config.Workspace{"default": ws}, CurrentWorkspace: "default"}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+
+	st := &state.State{Handler: handler.NewFileHandler(dir), Config: cfg, Workspace: ws}
+	model, err := NewModel(st)
+	if err != nil {
+		t.Fatalf("failed to create inbox model: %v", err)
+	}
+	model.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	return model, dir, ws
+}
+
+func press(m *Model, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestTrashRemovesCurrentNote(t *testing.T) {
+	model, dir, _ := newTestModel(t, map[string]string{
+		"inbox/first.md":  "first\n",
+		"inbox/second.md": "second\n",
+	})
+	if len(model.items) != 2 {
+		t.Fatalf("expected 2 inbox notes, got %d", len(model.items))
+	}
+	current := model.items[model.index]
+
+	press(model, "d")
+
+	if len(model.items) != 1 {
+		t.Fatalf("expected 1 inbox note after trashing, got %d", len(model.items))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "trash", "inbox", filepath.Base(current.Path))); err != nil {
+		t.Fatalf("expected note in trash: %v", err)
+	}
+	if !strings.Contains(model.View(), "1 triaged") {
+		t.Fatalf("expected triaged count in view, got %q", model.View())
+	}
+}
+
+func TestMoveUsesSubdirPicker(t *testing.T) {
+	model, dir, _ := newTestModel(t, map[string]string{
+		"inbox/idea.md": "an idea\n",
+	})
+
+	press(model, "m", "proj", "enter")
+
+	if _, err := os.Stat(filepath.Join(dir, "projects", "idea.md")); err != nil {
+		t.Fatalf("expected note moved to projects: %v", err)
+	}
+	if !strings.Contains(model.View(), "Inbox is empty.") {
+		t.Fatalf("expected empty inbox view, got %q", model.View())
+	}
+}
+
+func TestTagKeepsNoteInInbox(t *testing.T) {
+	model, dir, _ := newTestModel(t, map[string]string{
+		"inbox/idea.md": "---\ntitle: idea\n---\nbody\n",
+	})
+
+	press(model, "t", "go, cli", "enter")
+
+	if len(model.items) != 1 {
+		t.Fatalf("expected tagged note to stay in the inbox, got %d items", len(model.items))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "inbox", "idea.md"))
+	if err != nil {
+		t.Fatalf("failed to read note: %v", err)
+	}
+	if !strings.Contains(string(data), "tags:\n  - go\n  - cli\n") {
+		t.Fatalf("expected tags in front matter, got %q", string(data))
+	}
+}
+
+func TestToTaskAppendsToPinnedTaskFile(t *testing.T) {
+	model, dir, ws := newTestModel(t, map[string]string{
+		"inbox/call.md": "Call the plumber\n",
+		"tasks.md":      "- [ ] existing\n",
+	})
+
+	press(model, "x", "enter")
+
+	data, err := os.ReadFile(ws.PinnedTaskFile)
+	if err != nil {
+		t.Fatalf("failed to read task file: %v", err)
+	}
+	if string(data) != "- [ ] existing\n- [ ] Call the plumber\n" {
+		t.Fatalf("unexpected task file contents: %q", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "inbox", "call.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected inbox note to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package inbox
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const pickerRows = 8
+
+// picker is a fuzzy selector over a fixed set of options. When allowNew is
+// set, text that matches no option is accepted as typed.
+type picker struct {
+	input    textinput.Model
+	options  []string
+	matches  []string
+	cursor   int
+	allowNew bool
+}
+
+func newPicker(prompt string, options []string, allowNew bool) picker {
+	input := textinput.New()
+	input.Prompt = prompt + ": "
+	input.Focus()
+
+	p := picker{input: input, options: options, allowNew: allowNew}
+	p.filter()
+	return p
+}
+
+func (p *picker) filter() {
+	term := strings.TrimSpace(p.input.Value())
+	if term == "" {
+		p.matches = append([]string(nil), p.options...)
+	} else {
+		ranks := list.DefaultFilter(term, p.options)
+		p.matches = make([]string, 0, len(ranks))
+		for _, rank := range ranks {
+			p.matches = append(p.matches, p.options[rank.Index])
+		}
+	}
+	if p.cursor >= len(p.matches) {
+		p.cursor = 0
+	}
+}
+
+// update handles a key press and reports the picked value once enter is
+// pressed on a match, or on typed text when new values are allowed.
+func (p *picker) update(msg tea.KeyMsg) (string, bool, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if len(p.matches) > 0 {
+			return p.matches[p.cursor], true, nil
+		}
+		if value := strings.TrimSpace(p.input.Value()); p.allowNew && value != "" {
+			return value, true, nil
+		}
+		return "", false, nil
+	case "up", "ctrl+p", "ctrl+k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return "", false, nil
+	case "down", "ctrl+n", "ctrl+j":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return "", false, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return "", false, cmd
+}
+
+func (p picker) view() string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+
+	if len(p.matches) == 0 {
+		if p.allowNew && strings.TrimSpace(p.input.Value()) != "" {
+			b.WriteString(mutedStyle.Render("  no matches, enter creates it"))
+		} else {
+			b.WriteString(mutedStyle.Render("  no matches"))
+		}
+		return b.String()
+	}
+
+	start := 0
+	if p.cursor >= pickerRows {
+		start = p.cursor - pickerRows + 1
+	}
+	end := start + pickerRows
+	if end > len(p.matches) {
+		end = len(p.matches)
+	}
+	for i := start; i < end; i++ {
+		if i == p.cursor {
+			b.WriteString(selectedStyle.Render("> " + p.matches[i]))
+		} else {
+			b.WriteString("  " + p.matches[i])
+		}
+		b.WriteString("\n")
+	}
+	if len(p.matches) > end {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("  … %d more", len(p.matches)-end)))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
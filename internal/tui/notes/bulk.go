@@ -0,0 +1,644 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/frontmatter"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/relocate"
+	"github.com/Paintersrp/an/utils"
+)
+
+const markedPrefix = "● "
+
+// markStore holds the paths marked for a bulk operation. List items share
+// it so their titles can show the marker.
+type markStore struct {
+	mu    sync.RWMutex
+	paths map[string]struct{}
+}
+
+func newMarkStore() *markStore {
+	return &markStore{paths: make(map[string]struct{})}
+}
+
+func (s *markStore) has(path string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.paths[path]
+	return ok
+}
+
+func (s *markStore) len() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.paths)
+}
+
+func (s *markStore) set(path string, marked bool) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if marked {
+		s.paths[path] = struct{}{}
+	} else {
+		delete(s.paths, path)
+	}
+}
+
+func (s *markStore) snapshot() map[string]struct{} {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]struct{}, len(s.paths))
+	for path := range s.paths {
+		out[path] = struct{}{}
+	}
+	return out
+}
+
+func (s *markStore) replace(paths map[string]struct{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = paths
+}
+
+func (s *markStore) clear() {
+	s.replace(make(map[string]struct{}))
+}
+
+func attachMarkStore(items []list.Item, store *markStore) {
+	if store == nil {
+		return
+	}
+	for i, item := range items {
+		if listItem, ok := item.(ListItem); ok {
+			listItem.marks = store
+			items[i] = listItem
+		}
+	}
+}
+
+type bulkAction int
+
+const (
+	bulkArchive bulkAction = iota
+	bulkTrash
+	bulkDelete
+	bulkRestore
+	bulkMove
+	bulkAddTag
+	bulkRemoveTag
+	bulkSetMeta
+	bulkExport
+)
+
+// prompt is the question asked for actions that need a value.
+func (a bulkAction) prompt() string {
+	switch a {
+	case bulkMove:
+		return "Move to subdirectory"
+	case bulkAddTag:
+		return "Add tags"
+	case bulkRemoveTag:
+		return "Remove tags"
+	case bulkSetMeta:
+		return "Set front matter (key=value, empty value removes the key)"
+	case bulkExport:
+		return "Export to directory"
+	}
+	return ""
+}
+
+func (a bulkAction) needsInput() bool {
+	return a.prompt() != ""
+}
+
+func (a bulkAction) describe(count int, value string) string {
+	notes := pluralNotes(count)
+	switch a {
+	case bulkArchive:
+		return "Archive " + notes
+	case bulkTrash:
+		return "Move " + notes + " to trash"
+	case bulkDelete:
+		return "Permanently delete " + notes
+	case bulkRestore:
+		return "Restore " + notes
+	case bulkMove:
+		return fmt.Sprintf("Move %s to %s", notes, value)
+	case bulkAddTag:
+		return fmt.Sprintf("Add %s to %s", value, notes)
+	case bulkRemoveTag:
+		return fmt.Sprintf("Remove %s from %s", value, notes)
+	case bulkSetMeta:
+		return fmt.Sprintf("Set %s on %s", value, notes)
+	case bulkExport:
+		return fmt.Sprintf("Export %s to %s", notes, value)
+	}
+	return ""
+}
+
+func pluralNotes(count int) string {
+	if count == 1 {
+		return "1 note"
+	}
+	return fmt.Sprintf("%d notes", count)
+}
+
+// bulkPrompt collects the value for a bulk action, if it needs one, and then
+// asks for a single confirmation before it runs on every selected note.
+type bulkPrompt struct {
+	action   bulkAction
+	paths    []string
+	input    textinput.Model
+	value    string
+	editing  bool
+	errorMsg string
+	plan     *relocate.Plan
+}
+
+// selectedPaths returns the marked notes in list order, or the highlighted
+// note when nothing is marked.
+func (m *NoteListModel) selectedPaths() []string {
+	if m.marks.len() == 0 {
+		if path := m.currentSelectionPath(); path != "" {
+			return []string{path}
+		}
+		return nil
+	}
+
+	var paths []string
+	for _, item := range m.allItems {
+		if li, ok := item.(ListItem); ok && m.marks.has(li.path) {
+			paths = append(paths, li.path)
+		}
+	}
+	return paths
+}
+
+func (m *NoteListModel) toggleMark() tea.Cmd {
+	path := m.currentSelectionPath()
+	if path == "" {
+		return nil
+	}
+	m.marks.set(path, !m.marks.has(path))
+	m.list.CursorDown()
+	return m.handlePreview(false)
+}
+
+// markAllFiltered marks every visible note, or unmarks them when they are
+// all marked already.
+func (m *NoteListModel) markAllFiltered() tea.Cmd {
+	visible := m.list.VisibleItems()
+	allMarked := len(visible) > 0
+	for _, item := range visible {
+		if li, ok := item.(ListItem); ok && !m.marks.has(li.path) {
+			allMarked = false
+			break
+		}
+	}
+	for _, item := range visible {
+		if li, ok := item.(ListItem); ok {
+			m.marks.set(li.path, !allMarked)
+		}
+	}
+	if allMarked {
+		return m.list.NewStatusMessage(statusStyle("Unmarked " + pluralNotes(len(visible))))
+	}
+	return m.list.NewStatusMessage(statusStyle(fmt.Sprintf("%d marked", m.marks.len())))
+}
+
+// toggleRangeMark starts marking a range at the highlighted note; moving the
+// cursor extends it. Pressing the key again keeps the range marked.
+func (m *NoteListModel) toggleRangeMark() tea.Cmd {
+	if m.rangeAnchor >= 0 {
+		m.rangeAnchor = -1
+		m.rangeBase = nil
+		return m.list.NewStatusMessage(statusStyle(fmt.Sprintf("%d marked", m.marks.len())))
+	}
+	if m.currentSelectionPath() == "" {
+		return nil
+	}
+	m.rangeAnchor = m.list.Index()
+	m.rangeBase = m.marks.snapshot()
+	m.extendRangeMark()
+	return m.list.NewStatusMessage(statusStyle("Marking range, press v to finish"))
+}
+
+func (m *NoteListModel) extendRangeMark() {
+	if m.rangeAnchor < 0 || m.rangeBase == nil {
+		return
+	}
+	visible := m.list.VisibleItems()
+	lo, hi := m.rangeAnchor, m.list.Index()
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	marked := make(map[string]struct{}, len(m.rangeBase)+hi-lo+1)
+	for path := range m.rangeBase {
+		marked[path] = struct{}{}
+	}
+	for i := lo; i <= hi && i < len(visible); i++ {
+		if li, ok := visible[i].(ListItem); ok {
+			marked[li.path] = struct{}{}
+		}
+	}
+	m.marks.replace(marked)
+}
+
+func (m *NoteListModel) clearMarks() {
+	m.marks.clear()
+	m.rangeAnchor = -1
+	m.rangeBase = nil
+}
+
+// markedDelegateAction maps the single-note delegate keys onto bulk actions
+// while notes are marked.
+func (m *NoteListModel) markedDelegateAction(msg tea.KeyMsg) (bulkAction, bool) {
+	if m.marks.len() == 0 || m.delegateKeys == nil {
+		return 0, false
+	}
+	switch {
+	case key.Matches(msg, m.delegateKeys.archive):
+		return bulkArchive, true
+	case key.Matches(msg, m.delegateKeys.trash):
+		return bulkTrash, true
+	case key.Matches(msg, m.delegateKeys.delete):
+		return bulkDelete, true
+	case key.Matches(msg, m.delegateKeys.undo):
+		return bulkRestore, true
+	case key.Matches(msg, m.delegateKeys.keypadDelete):
+		if m.viewName == "trash" {
+			return bulkDelete, true
+		}
+		return bulkTrash, true
+	}
+	return 0, false
+}
+
+func (m *NoteListModel) bulkAllowed(action bulkAction) error {
+	switch action {
+	case bulkArchive:
+		if m.viewName == "archive" || m.viewName == "trash" {
+			return fmt.Errorf("cannot archive from the %s view", m.viewName)
+		}
+	case bulkTrash:
+		if m.viewName == "trash" {
+			return errors.New("notes are already in the trash")
+		}
+	case bulkDelete:
+		if m.viewName != "trash" {
+			return errors.New("only notes in the trash can be deleted")
+		}
+	case bulkRestore:
+		if m.viewName != "archive" && m.viewName != "trash" {
+			return errors.New("only archived or trashed notes can be restored")
+		}
+	}
+	return nil
+}
+
+func (m *NoteListModel) startBulk(action bulkAction) tea.Cmd {
+	paths := m.selectedPaths()
+	if len(paths) == 0 {
+		return m.list.NewStatusMessage(statusStyle("No notes selected"))
+	}
+	if err := m.bulkAllowed(action); err != nil {
+		return m.list.NewStatusMessage(statusStyle(err.Error()))
+	}
+
+	m.rangeAnchor = -1
+	m.rangeBase = nil
+	prompt := &bulkPrompt{action: action, paths: paths}
+	if action.needsInput() {
+		input := textinput.New()
+		input.Prompt = "> "
+		if action == bulkExport {
+			input.SetValue(filepath.Join("~", "an-export"))
+		}
+		input.Focus()
+		prompt.input = input
+		prompt.editing = true
+	}
+	m.bulk = prompt
+	return textinput.Blink
+}
+
+func (m *NoteListModel) handleBulkUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.bulk
+	if key.Matches(msg, m.keys.exitAltView) {
+		m.bulk = nil
+		return m, m.list.NewStatusMessage(statusStyle("Cancelled"))
+	}
+
+	if p.editing {
+		if key.Matches(msg, m.keys.submitAltView) {
+			value, err := m.bulkValue(p.action, p.input.Value())
+			if err == nil && p.action == bulkMove {
+				p.plan, err = m.planMove(p.paths, value)
+			}
+			if err != nil {
+				p.errorMsg = err.Error()
+				return m, nil
+			}
+			p.value = value
+			p.editing = false
+			p.errorMsg = ""
+			p.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "y", "Y", "enter":
+		return m, m.applyBulk()
+	case "n", "N":
+		m.bulk = nil
+		return m, m.list.NewStatusMessage(statusStyle("Cancelled"))
+	}
+	return m, nil
+}
+
+// bulkValue validates and normalises the value entered for action.
+func (m *NoteListModel) bulkValue(action bulkAction, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	switch action {
+	case bulkMove:
+		value = strings.Trim(filepath.ToSlash(filepath.Clean(value)), "/")
+		if value == "" || value == "." || value == ".." || strings.HasPrefix(value, "../") {
+			return "", errors.New("enter a subdirectory inside the vault")
+		}
+		if ws := m.workspace(); ws != nil && !ws.HasSubdir(value) && ws.FileSystemMode == "strict" {
+			return "", fmt.Errorf("unknown subdirectory %q (fsmode is strict)", value)
+		}
+	case bulkAddTag, bulkRemoveTag:
+		tags, err := utils.ValidateTags(strings.Join(strings.Fields(strings.ReplaceAll(value, ",", " ")), " "))
+		if err != nil {
+			return "", err
+		}
+		if len(tags) == 0 {
+			return "", errors.New("enter at least one tag")
+		}
+		value = strings.Join(tags, " ")
+	case bulkSetMeta:
+		key, _, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return "", errors.New("use key=value")
+		}
+	case bulkExport:
+		if value == "" {
+			return "", errors.New("enter a directory")
+		}
+		dest, err := expandHome(value)
+		if err != nil {
+			return "", err
+		}
+		vault := m.state.Handler.VaultDir()
+		if rel, err := filepath.Rel(vault, dest); err == nil && !strings.HasPrefix(rel, "..") {
+			return "", errors.New("export directory must be outside the vault")
+		}
+		value = dest
+	}
+	return value, nil
+}
+
+// applyBulk runs the confirmed action on every selected note. Vault changes
+// are journaled as one group, so a single undo reverts the whole batch.
+func (m *NoteListModel) applyBulk() tea.Cmd {
+	p := m.bulk
+	m.bulk = nil
+
+	if p.action == bulkMove {
+		return m.applyMove(p)
+	}
+
+	h := m.state.Handler
+	done := 0
+	err := h.Journal().Group(func() error {
+		for _, path := range p.paths {
+			if err := m.applyBulkTo(h, p.action, path, p.value); err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(path), err)
+			}
+			done++
+		}
+		return nil
+	})
+
+	status := fmt.Sprintf("%s: done", p.action.describe(len(p.paths), p.value))
+	if err != nil {
+		status = fmt.Sprintf("%s: %d done, stopped at %v", p.action.describe(len(p.paths), p.value), done, err)
+	}
+
+	m.clearMarks()
+	return batchCmds(
+		m.refresh(),
+		m.list.NewStatusMessage(statusStyle(status)),
+		m.state.IndexHeartbeatCmd(),
+	)
+}
+
+// planMove plans a link-safe move of paths into subdir, checking every note
+// in the vault for links that need rewriting.
+func (m *NoteListModel) planMove(paths []string, subdir string) (*relocate.Plan, error) {
+	h := m.state.Handler
+	files, err := h.WalkFiles(nil, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	plan, err := relocate.Build(h.VaultDir(), paths, subdir, files)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Moves) == 0 {
+		return nil, fmt.Errorf("the notes are already in %s", plan.Subdir)
+	}
+	return plan, nil
+}
+
+// applyMove adds the destination subdirectory if needed and applies the
+// relocation plan. The confirmation already covered confirm mode.
+func (m *NoteListModel) applyMove(p *bulkPrompt) tea.Cmd {
+	status := fmt.Sprintf("%s: done", p.action.describe(len(p.plan.Moves), p.value))
+	if n := len(p.plan.Rewrites); n > 0 {
+		status = fmt.Sprintf("%s, updated links in %s", status, pluralNotes(n))
+	}
+
+	err := m.state.Config.EnsureSubdir(p.plan.Subdir, func() bool { return true })
+	if err == nil {
+		err = p.plan.Apply(m.state.Handler)
+	}
+	if err != nil {
+		status = fmt.Sprintf("Move failed: %v", err)
+	}
+
+	m.clearMarks()
+	return batchCmds(
+		m.refresh(),
+		m.list.NewStatusMessage(statusStyle(status)),
+		m.state.IndexHeartbeatCmd(),
+	)
+}
+
+func (m *NoteListModel) applyBulkTo(h *handler.FileHandler, action bulkAction, path, value string) error {
+	switch action {
+	case bulkArchive:
+		return h.Archive(path)
+	case bulkTrash:
+		return h.Trash(path)
+	case bulkDelete:
+		return h.Delete(path)
+	case bulkRestore:
+		if m.viewName == "archive" {
+			return h.Unarchive(path)
+		}
+		return h.Untrash(path)
+	case bulkAddTag:
+		return editFrontMatter(h, "tag", path, func(doc *frontmatter.Document) error {
+			doc.AddValues("tags", strings.Fields(value)...)
+			return nil
+		})
+	case bulkRemoveTag:
+		return editFrontMatter(h, "untag", path, func(doc *frontmatter.Document) error {
+			doc.RemoveValues("tags", strings.Fields(value)...)
+			return nil
+		})
+	case bulkSetMeta:
+		key, val, _ := strings.Cut(value, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		return editFrontMatter(h, "meta", path, func(doc *frontmatter.Document) error {
+			if val == "" {
+				doc.Delete(key)
+				return nil
+			}
+			return doc.Set(key, val)
+		})
+	case bulkExport:
+		return exportNote(h.VaultDir(), path, value)
+	}
+	return nil
+}
+
+func editFrontMatter(h *handler.FileHandler, op, path string, apply func(*frontmatter.Document) error) error {
+	data, err := h.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := frontmatter.Parse(data)
+	if err != nil {
+		return err
+	}
+	if err := apply(doc); err != nil {
+		return err
+	}
+	updated, err := doc.Bytes()
+	if err != nil {
+		return err
+	}
+	return h.WriteFileOp(op, path, updated)
+}
+
+// exportNote copies the note into dest, keeping its path relative to the
+// vault.
+func exportNote(vault, path, dest string) error {
+	rel, err := filepath.Rel(vault, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(dest, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0o644)
+}
+
+func expandHome(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return filepath.Abs(path)
+}
+
+func (m *NoteListModel) bulkView() string {
+	p := m.bulk
+	if p.editing {
+		header := titleStyle.Render(p.action.prompt())
+		body := fmt.Sprintf("%s\n\n%s\n\n%s", header, p.input.View(), helpStyle.Render(pluralNotes(len(p.paths))+" selected · enter to continue · esc to cancel"))
+		if p.errorMsg != "" {
+			body += "\n\n" + statusStyle(p.errorMsg)
+		}
+		return body
+	}
+
+	var names []string
+	for i, path := range p.paths {
+		if i == 10 {
+			names = append(names, fmt.Sprintf("… and %d more", len(p.paths)-i))
+			break
+		}
+		names = append(names, "  "+strings.TrimSuffix(filepath.Base(path), ".md"))
+	}
+	header := titleStyle.Render(p.action.describe(len(p.paths), p.value) + "?")
+	if p.plan != nil {
+		var notes []string
+		if ws := m.workspace(); ws != nil && !ws.HasSubdir(p.plan.Subdir) {
+			notes = append(notes, p.plan.Subdir+" will be added as a subdirectory")
+		}
+		if n := len(p.plan.Rewrites); n > 0 {
+			notes = append(notes, "links will be updated in "+pluralNotes(n))
+		}
+		if len(notes) > 0 {
+			names = append(names, "", helpStyle.Render(strings.Join(notes, " · ")))
+		}
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n%s", header, strings.Join(names, "\n"), helpStyle.Render("y to confirm · n or esc to cancel"))
+}
+
+func (m *NoteListModel) workspace() *config.Workspace {
+	if m.state == nil || m.state.Config == nil {
+		return nil
+	}
+	if m.state.Workspace != nil {
+		return m.state.Workspace
+	}
+	ws, err := m.state.Config.ActiveWorkspace()
+	if err != nil {
+		return nil
+	}
+	return ws
+}
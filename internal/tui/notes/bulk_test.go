@@ -0,0 +1,240 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/views"
+)
+
+func newBulkTestModel(t *testing.T, names ...string) (*NoteListModel, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	tempDir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(tempDir, "atoms", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		content := "---\ntitle: " + strings.TrimSuffix(name, ".md") + "\ntags:\n  - seed\n---\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	fileHandler := handler.NewFileHandler(tempDir)
+	ws := &config.Workspace{VaultDir: tempDir, SubDirs: []string{"atoms", "projects"}, FileSystemMode: "strict"}
+	cfg := &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": ws},
+		CurrentWorkspace: "default",
+	}
+	activateWorkspace(t, cfg, "default")
+
+	viewManager, err := views.NewViewManager(fileHandler, cfg)
+	if err != nil {
+		t.Fatalf("NewViewManager returned error: %v", err)
+	}
+
+	s := &state.State{
+		Config:        cfg,
+		Workspace:     ws,
+		WorkspaceName: cfg.CurrentWorkspace,
+		Handler:       fileHandler,
+		ViewManager:   viewManager,
+		Vault:         tempDir,
+	}
+
+	model, err := NewNoteListModel(s, "default")
+	if err != nil {
+		t.Fatalf("NewNoteListModel returned error: %v", err)
+	}
+	model.list.SetSize(80, 40)
+	return model, tempDir
+}
+
+func pressKeys(m *NoteListModel, keys ...string) {
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestBulkAddTagAppliesToMarkedNotesWithOneUndo(t *testing.T) {
+	model, vault := newBulkTestModel(t, "alpha.md", "beta.md", "gamma.md")
+
+	pressKeys(model, " ", " ")
+	if got := model.marks.len(); got != 2 {
+		t.Fatalf("expected 2 marked notes, got %d", got)
+	}
+	marked := model.selectedPaths()
+
+	pressKeys(model, "+")
+	if model.bulk == nil || !model.bulk.editing {
+		t.Fatalf("expected the tag prompt to open")
+	}
+	pressKeys(model, "r", "e", "v", "enter")
+	if model.bulk == nil || model.bulk.editing {
+		t.Fatalf("expected a confirmation after entering the tag")
+	}
+	if !strings.Contains(model.bulkView(), "Add rev to 2 notes?") {
+		t.Fatalf("unexpected confirmation %q", model.bulkView())
+	}
+	pressKeys(model, "y")
+
+	if model.bulk != nil || model.marks.len() != 0 {
+		t.Fatalf("expected the prompt and marks to be cleared")
+	}
+	for _, path := range marked {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		if !strings.Contains(string(data), "- rev") {
+			t.Fatalf("expected %s to be tagged, got:\n%s", path, data)
+		}
+	}
+	for _, name := range []string{"alpha.md", "beta.md", "gamma.md"} {
+		path := filepath.Join(vault, "atoms", name)
+		if containsPath(marked, path) {
+			continue
+		}
+		if data, _ := os.ReadFile(path); strings.Contains(string(data), "rev") {
+			t.Fatalf("expected unmarked %s to be left alone", name)
+		}
+	}
+
+	pressKeys(model, "u")
+	for _, path := range marked {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "rev") {
+			t.Fatalf("expected one undo to revert the whole batch, %s still tagged", path)
+		}
+	}
+}
+
+func TestBulkMarkAllAndTrash(t *testing.T) {
+	model, vault := newBulkTestModel(t, "alpha.md", "beta.md")
+
+	pressKeys(model, "*")
+	if got := model.marks.len(); got != 2 {
+		t.Fatalf("expected every visible note to be marked, got %d", got)
+	}
+	pressKeys(model, "T")
+	if model.bulk == nil || model.bulk.action != bulkTrash {
+		t.Fatalf("expected the delegate trash key to start a bulk trash")
+	}
+	pressKeys(model, "y")
+
+	for _, name := range []string{"alpha.md", "beta.md"} {
+		if _, err := os.Stat(filepath.Join(vault, "trash", "atoms", name)); err != nil {
+			t.Fatalf("expected %s in the trash: %v", name, err)
+		}
+	}
+
+	pressKeys(model, "u")
+	for _, name := range []string{"alpha.md", "beta.md"} {
+		if _, err := os.Stat(filepath.Join(vault, "atoms", name)); err != nil {
+			t.Fatalf("expected %s restored by a single undo: %v", name, err)
+		}
+	}
+}
+
+func TestBulkRangeMarkAndMove(t *testing.T) {
+	model, vault := newBulkTestModel(t, "alpha.md", "beta.md", "gamma.md")
+
+	pressKeys(model, "v", "j", "v")
+	if got := model.marks.len(); got != 2 {
+		t.Fatalf("expected the range to mark 2 notes, got %d", got)
+	}
+	marked := model.selectedPaths()
+
+	pressKeys(model, "M", "n", "o", "p", "e", "enter")
+	if model.bulk == nil || !model.bulk.editing || model.bulk.errorMsg == "" {
+		t.Fatalf("expected an unknown subdirectory to be rejected in strict mode")
+	}
+	model.bulk.input.SetValue("projects")
+	pressKeys(model, "enter", "y")
+
+	for _, path := range marked {
+		if _, err := os.Stat(filepath.Join(vault, "projects", filepath.Base(path))); err != nil {
+			t.Fatalf("expected %s moved to projects: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestBulkMoveRewritesBacklinks(t *testing.T) {
+	model, vault := newBulkTestModel(t, "alpha.md")
+	model.state.Workspace.FileSystemMode = "confirm"
+
+	hub := filepath.Join(vault, "hubs", "index.md")
+	if err := os.MkdirAll(filepath.Dir(hub), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(hub, []byte("- [alpha](../atoms/alpha.md)\n"), 0o644); err != nil {
+		t.Fatalf("failed to write hub: %v", err)
+	}
+
+	pressKeys(model, "M")
+	model.bulk.input.SetValue("drafts")
+	pressKeys(model, "enter")
+	if model.bulk == nil || model.bulk.editing {
+		t.Fatalf("expected confirm mode to accept a new subdirectory, got %q", model.bulk.errorMsg)
+	}
+	if view := model.bulkView(); !strings.Contains(view, "links will be updated in 1 note") {
+		t.Fatalf("expected the confirmation to mention link updates, got %q", view)
+	}
+	pressKeys(model, "y")
+
+	if _, err := os.Stat(filepath.Join(vault, "drafts", "alpha.md")); err != nil {
+		t.Fatalf("expected alpha moved to drafts: %v", err)
+	}
+	if data, _ := os.ReadFile(hub); string(data) != "- [alpha](../drafts/alpha.md)\n" {
+		t.Fatalf("expected the backlink rewritten, got %q", data)
+	}
+	if !model.state.Workspace.HasSubdir("drafts") {
+		t.Fatalf("expected drafts to be added as a subdirectory")
+	}
+}
+
+func TestBulkCancelKeepsMarks(t *testing.T) {
+	model, _ := newBulkTestModel(t, "alpha.md", "beta.md")
+
+	pressKeys(model, " ", "K", "esc")
+	if model.bulk != nil {
+		t.Fatalf("expected esc to cancel the prompt")
+	}
+	if model.marks.len() != 1 {
+		t.Fatalf("expected marks to survive a cancelled prompt")
+	}
+	pressKeys(model, "esc")
+	if model.marks.len() != 0 {
+		t.Fatalf("expected esc to clear marks")
+	}
+}
+
+func containsPath(paths []string, target string) bool {
+	for _, path := range paths {
+		if path == target {
+			return true
+		}
+	}
+	return false
+}
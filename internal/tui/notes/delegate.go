@@ -1,8 +1,6 @@
 package notes
 
 import (
-	"os"
-
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -51,7 +49,7 @@ func newItemDelegate(
 
 			case key.Matches(msg, keys.delete):
 				if currView == "trash" { // Ensure we're in trash view
-					if err := os.Remove(p); err != nil {
+					if err := h.Delete(p); err != nil {
 						return m.NewStatusMessage(statusStyle("Failed to delete " + n))
 					}
 					return batchStatusWithRemoval(m, p, statusStyle("Deleted "+n))
@@ -87,7 +85,7 @@ func newItemDelegate(
 					return batchStatusWithRemoval(m, p, statusStyle("Moved "+n+" to trash"))
 
 				case "trash":
-					if err := os.Remove(p); err != nil {
+					if err := h.Delete(p); err != nil {
 						return m.NewStatusMessage(statusStyle("Failed to delete " + n))
 					}
 					return batchStatusWithRemoval(m, p, statusStyle("Deleted "+n))
@@ -17,9 +17,18 @@ type ListItem struct {
 	size         int64
 	showFullPath bool
 	highlights   *highlightStore
+	marks        *markStore
 }
 
 func (i ListItem) Title() string {
+	if i.marks.has(i.path) {
+		return markedPrefix + i.plainTitle()
+	}
+	return i.plainTitle()
+}
+
+// plainTitle is the title without the multi-select marker.
+func (i ListItem) plainTitle() string {
 	if i.showFullPath {
 		return i.path
 	}
@@ -61,7 +70,7 @@ func (i ListItem) Description() string {
 
 func (i ListItem) FilterValue() string {
 	str := strings.Join(i.tags, " ")
-	parts := []string{i.Title(), "[" + str + "]", "[" + i.subdirectory + "]"}
+	parts := []string{i.plainTitle(), "[" + str + "]", "[" + i.subdirectory + "]"}
 	if snippet := i.highlightSnippet(); snippet != "" {
 		parts = append(parts, snippet)
 	}
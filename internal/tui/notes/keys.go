@@ -17,6 +17,16 @@ type listKeyMap struct {
 	rename                key.Binding
 	create                key.Binding
 	copy                  key.Binding
+	undoLast              key.Binding
+	toggleMark            key.Binding
+	markAll               key.Binding
+	markRange             key.Binding
+	clearMarks            key.Binding
+	bulkMove              key.Binding
+	bulkAddTag            key.Binding
+	bulkRemoveTag         key.Binding
+	bulkSetMeta           key.Binding
+	bulkExport            key.Binding
 	editInline            key.Binding
 	quickCapture          key.Binding
 	link                  key.Binding
@@ -99,6 +109,46 @@ func newListKeyMap() *listKeyMap {
 			key.WithKeys("Y"),
 			key.WithHelp("Y", "copy"),
 		),
+		undoLast: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo last operation"),
+		),
+		toggleMark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark"),
+		),
+		markAll: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "mark all filtered"),
+		),
+		markRange: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "mark range"),
+		),
+		clearMarks: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear marks"),
+		),
+		bulkMove: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "move to subdir"),
+		),
+		bulkAddTag: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "add tag"),
+		),
+		bulkRemoveTag: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "remove tag"),
+		),
+		bulkSetMeta: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "set front matter"),
+		),
+		bulkExport: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "export"),
+		),
 		editInline: key.NewBinding(
 			key.WithKeys("E"),
 			key.WithHelp("E", "inline edit"),
@@ -178,6 +228,15 @@ func (m listKeyMap) fullHelp() []key.Binding {
 		m.toggleGraphPane,
 		m.rename,
 		m.copy,
+		m.undoLast,
+		m.toggleMark,
+		m.markAll,
+		m.markRange,
+		m.bulkMove,
+		m.bulkAddTag,
+		m.bulkRemoveTag,
+		m.bulkSetMeta,
+		m.bulkExport,
 		m.changeView,
 		m.switchToDefaultView,
 		m.switchToArchiveView,
@@ -23,10 +23,12 @@ import (
 
 	"github.com/Paintersrp/an/internal/cache"
 	"github.com/Paintersrp/an/internal/note"
+	"github.com/Paintersrp/an/internal/oplog"
 	"github.com/Paintersrp/an/internal/pathutil"
 	"github.com/Paintersrp/an/internal/review"
 	"github.com/Paintersrp/an/internal/search"
 	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/timeutil"
 	journaltui "github.com/Paintersrp/an/internal/tui/journal"
 	"github.com/Paintersrp/an/internal/tui/notes/submodels"
 	reviewtui "github.com/Paintersrp/an/internal/tui/review"
@@ -83,6 +85,10 @@ type NoteListModel struct {
 	graphPaneCursor      int
 	graphPaneRows        []graphPaneRow
 	graphPaneGraph       review.Graph
+	marks                *markStore
+	rangeAnchor          int
+	rangeBase            map[string]struct{}
+	bulk                 *bulkPrompt
 }
 
 type previewLoadedMsg struct {
@@ -178,6 +184,8 @@ func NewNoteListModel(
 
 	highlightMatches := newHighlightStore()
 	attachHighlightStore(sortedItems, highlightMatches)
+	marks := newMarkStore()
+	attachMarkStore(sortedItems, marks)
 
 	dkeys := newDelegateKeyMap()
 	lkeys := newListKeyMap()
@@ -229,6 +237,8 @@ func NewNoteListModel(
 		previewViewport:      viewport.New(0, 0),
 		previewPaletteCursor: -1,
 		graphPaneCursor:      -1,
+		marks:                marks,
+		rangeAnchor:          -1,
 	}
 
 	m.allItems = append([]list.Item(nil), sortedItems...)
@@ -475,6 +485,15 @@ func (m *NoteListModel) filteredItems() []list.Item {
 	return filtered
 }
 
+// SetTagFilter replaces the active tag filter. Nested tags match their
+// parents, so project also shows notes tagged project/apollo.
+func (m *NoteListModel) SetTagFilter(tags []string) tea.Cmd {
+	m.searchQuery.Tags = cloneStringSlice(tags)
+	m.syncFilterPalette()
+	m.updateFilterStatus()
+	return m.applyActiveFilters()
+}
+
 func (m *NoteListModel) applyActiveFilters() tea.Cmd {
 	filtered := m.filteredItems()
 	cmd := m.list.SetItems(filtered)
@@ -932,6 +951,10 @@ func (m *NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return model, cmd
 		}
 
+		if m.bulk != nil {
+			return m.handleBulkUpdate(msg)
+		}
+
 		switch {
 		case m.copying:
 			return m.handleCopyUpdate(msg)
@@ -973,6 +996,7 @@ func (m *NoteListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 
 	m.ensureSelectionInBounds()
+	m.extendRangeMark()
 
 	if nextSelection := m.currentSelectionPath(); nextSelection != previousSelection {
 		if nextSelection == "" {
@@ -1168,7 +1192,7 @@ func (m *NoteListModel) startInlineEdit() tea.Cmd {
 
 	width, height := m.editorSize()
 	session := newEditorSession(width, height)
-	session.setMetadata(selected.path, selected.Title(), editorModeExisting)
+	session.setMetadata(selected.path, selected.plainTitle(), editorModeExisting)
 	session.setValue(string(data))
 	session.setOriginal(string(data), info.ModTime())
 	session.status = ""
@@ -1251,7 +1275,7 @@ func (m *NoteListModel) saveExistingEditor(content string) tea.Cmd {
 		}
 	}
 
-	if err := m.state.Handler.WriteFile(path, []byte(content)); err != nil {
+	if err := m.state.Handler.WriteFileOp("edit", path, []byte(content)); err != nil {
 		m.editor.status = fmt.Sprintf("Save failed: %v", err)
 		return nil
 	}
@@ -1313,7 +1337,7 @@ func (m *NoteListModel) saveScratchEditor(content string) tea.Cmd {
 		return nil
 	}
 
-	if err := m.state.Handler.WriteFile(path, []byte(content)); err != nil {
+	if err := m.state.Handler.WriteFileOp("capture", path, []byte(content)); err != nil {
 		m.editor.status = fmt.Sprintf("Save failed: %v", err)
 		return nil
 	}
@@ -1467,7 +1491,39 @@ func (m *NoteListModel) handleDefaultUpdate(msg tea.KeyMsg) (tea.Cmd, bool) {
 		return cmd, true
 	}
 
+	if action, ok := m.markedDelegateAction(msg); ok {
+		return batchCmds(m.blurPreview(), m.startBulk(action)), true
+	}
+
 	switch {
+	case key.Matches(msg, m.keys.clearMarks) && m.marks.len() > 0:
+		m.clearMarks()
+		return m.list.NewStatusMessage(statusStyle("Cleared marks")), true
+
+	case key.Matches(msg, m.keys.toggleMark):
+		return m.toggleMark(), true
+
+	case key.Matches(msg, m.keys.markAll):
+		return m.markAllFiltered(), true
+
+	case key.Matches(msg, m.keys.markRange):
+		return m.toggleRangeMark(), true
+
+	case key.Matches(msg, m.keys.bulkMove):
+		return batchCmds(m.blurPreview(), m.startBulk(bulkMove)), true
+
+	case key.Matches(msg, m.keys.bulkAddTag):
+		return batchCmds(m.blurPreview(), m.startBulk(bulkAddTag)), true
+
+	case key.Matches(msg, m.keys.bulkRemoveTag):
+		return batchCmds(m.blurPreview(), m.startBulk(bulkRemoveTag)), true
+
+	case key.Matches(msg, m.keys.bulkSetMeta):
+		return batchCmds(m.blurPreview(), m.startBulk(bulkSetMeta)), true
+
+	case key.Matches(msg, m.keys.bulkExport):
+		return batchCmds(m.blurPreview(), m.startBulk(bulkExport)), true
+
 	case key.Matches(msg, m.keys.toggleFocus):
 		if m.previewHasFocus() {
 			return m.blurPreview(), true
@@ -1556,6 +1612,9 @@ func (m *NoteListModel) handleDefaultUpdate(msg tea.KeyMsg) (tea.Cmd, bool) {
 		m.toggleCopy()
 		return batchCmds(m.blurPreview()), true
 
+	case key.Matches(msg, m.keys.undoLast):
+		return batchCmds(m.blurPreview(), m.undoLastOperation()), true
+
 	case key.Matches(msg, m.keys.editInline):
 		return batchCmds(m.blurPreview(), m.startInlineEdit()), true
 
@@ -1620,7 +1679,11 @@ func (m NoteListModel) View() string {
 	}
 
 	listView := m.list.View()
-	if status := m.rootStatusLine(); status != "" {
+	status := m.rootStatusLine()
+	if marked := m.marks.len(); marked > 0 {
+		status = strings.TrimSuffix(fmt.Sprintf("%d marked · %s", marked, status), " · ")
+	}
+	if status != "" {
 		originalTitle := m.list.Title
 		gap := "  "
 		if originalTitle == "" {
@@ -1642,6 +1705,21 @@ func (m NoteListModel) View() string {
 		sideWidth = 0
 	}
 
+	if m.bulk != nil {
+		promptContent := lipgloss.NewStyle().
+			Width(sideWidth).
+			MaxWidth(sideWidth).
+			Height(listHeight).
+			MaxHeight(listHeight).
+			Padding(0, 2).
+			Render(m.bulkView())
+
+		textPrompt := textPromptStyle.Render(promptContent)
+
+		layout := lipgloss.JoinHorizontal(lipgloss.Top, list, textPrompt)
+		return appStyle.Render(layout)
+	}
+
 	if m.copying {
 		promptContent := lipgloss.NewStyle().
 			Width(sideWidth).
@@ -1780,7 +1858,45 @@ func rootStatusSuffix(view string, width int, status, gap string) string {
 	return trimmed
 }
 
+// purgeExpiredTrash permanently deletes notes that have been in the trash
+// longer than the workspace's trash.retention allows.
+func purgeExpiredTrash(s *state.State) error {
+	if s == nil || s.Config == nil || s.Handler == nil {
+		return nil
+	}
+	ws := s.Workspace
+	if ws == nil {
+		active, err := s.Config.ActiveWorkspace()
+		if err != nil {
+			return nil
+		}
+		ws = active
+	}
+	if strings.TrimSpace(ws.Trash.Retention) == "" {
+		return nil
+	}
+
+	retention, err := timeutil.ParseAge(ws.Trash.Retention)
+	if err != nil {
+		return fmt.Errorf("trash.retention: %w", err)
+	}
+	if _, err := s.Handler.PurgeTrash(retention, false); err != nil {
+		return fmt.Errorf("trash.retention: %w", err)
+	}
+	return nil
+}
+
 func Run(s *state.State, views map[string]v.View, viewFlag string) error {
+	return RunFiltered(s, views, viewFlag, nil)
+}
+
+// RunFiltered opens the notes TUI with the tag filter already set, as when a
+// tag is picked from 'an tags'.
+func RunFiltered(s *state.State, views map[string]v.View, viewFlag string, tags []string) error {
+	if err := purgeExpiredTrash(s); err != nil {
+		return err
+	}
+
 	originalState, err := term.GetState(int(os.Stdin.Fd()))
 	if err != nil {
 		log.Fatalf("Failed to get original terminal state: %v", err)
@@ -1797,6 +1913,9 @@ func Run(s *state.State, views map[string]v.View, viewFlag string) error {
 	if err != nil {
 		return err
 	}
+	if len(tags) > 0 {
+		noteModel.SetTagFilter(tags)
+	}
 
 	tasksModel, err := taskstui.NewModel(s)
 	if err != nil {
@@ -2064,6 +2183,30 @@ func (m *NoteListModel) refresh() tea.Cmd {
 	return sequenceWithClear(tea.Batch(cmd, m.handlePreview(true)))
 }
 
+// undoLastOperation reverts the most recent journaled vault operation and
+// reloads the list so the restored note shows up again.
+func (m *NoteListModel) undoLastOperation() tea.Cmd {
+	journal := journalFor(m.state)
+	if journal == nil {
+		return m.list.NewStatusMessage(statusStyle("Operation journal unavailable"))
+	}
+
+	undone, err := journal.Undo(1)
+	if errors.Is(err, oplog.ErrNothingToUndo) {
+		return m.list.NewStatusMessage(statusStyle("Nothing to undo"))
+	}
+	if err != nil {
+		return m.list.NewStatusMessage(statusStyle(fmt.Sprintf("Undo failed: %v", err)))
+	}
+
+	message := "Undid " + undone[0].Describe()
+	if len(undone) > 1 {
+		message = fmt.Sprintf("Undid %d operations", len(undone))
+	}
+	refreshCmd := m.refresh()
+	return batchCmds(refreshCmd, m.list.NewStatusMessage(statusStyle(message)))
+}
+
 func (m *NoteListModel) refreshItems() tea.Cmd {
 	files, err := m.state.ViewManager.GetFilesByView(m.viewName)
 	if err != nil {
@@ -2075,6 +2218,7 @@ func (m *NoteListModel) refreshItems() tea.Cmd {
 	items := ParseNoteFiles(files, m.state.Vault, m.showDetails)
 	sortedItems := sortItems(castToListItems(items), m.sortField, m.sortOrder)
 	attachHighlightStore(sortedItems, m.highlights)
+	attachMarkStore(sortedItems, m.marks)
 	m.allItems = append([]list.Item(nil), sortedItems...)
 	m.rebuildSearch(files)
 	m.updateFilterInventory()
@@ -2098,6 +2242,7 @@ func (m *NoteListModel) refreshSort() tea.Cmd {
 	items := castToListItems(m.allItems)
 	sortedItems := sortItems(items, m.sortField, m.sortOrder)
 	attachHighlightStore(sortedItems, m.highlights)
+	attachMarkStore(sortedItems, m.marks)
 	m.allItems = append([]list.Item(nil), sortedItems...)
 	m.list.ResetSelected()
 	cmd := m.applyActiveFilters()
@@ -3151,6 +3296,7 @@ func (m *NoteListModel) applyView(viewName string) tea.Cmd {
 	}
 
 	m.viewName = viewName
+	m.clearMarks()
 	m.sortField = sortFieldFromView(view.Sort.Field)
 	m.sortOrder = sortOrderFromView(view.Sort.Order)
 
@@ -3218,7 +3364,7 @@ func (m *NoteListModel) toggleCopy() {
 		m.copying = true
 		m.inputModel.Input.Focus()
 		if s, ok := m.list.SelectedItem().(ListItem); ok {
-			base := s.Title()
+			base := s.plainTitle()
 			if base == "" {
 				base = strings.TrimSuffix(s.fileName, ".md")
 			}
@@ -3244,7 +3390,7 @@ func (m *NoteListModel) toggleRename() {
 		m.renaming = true
 		m.inputModel.Input.Focus()
 		if s, ok := m.list.SelectedItem().(ListItem); ok {
-			value := s.Title()
+			value := s.plainTitle()
 			if value == "" {
 				value = strings.TrimSuffix(s.fileName, ".md")
 			}
@@ -1397,3 +1397,62 @@ func TestHandlePreviewUsesUpdatedQueue(t *testing.T) {
 		t.Fatalf("expected queue neighbours to include %q, got %v", normalizedBravo, loaded.context.QueueNeighbours)
 	}
 }
+
+func TestPurgeExpiredTrashEnforcesRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	ws := &config.Workspace{VaultDir: tempDir, Trash: config.TrashConfig{Retention: "30d"}}
+	cfg := &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": ws},
+		CurrentWorkspace: "default",
+	}
+	activateWorkspace(t, cfg, "default")
+
+	oldNote := filepath.Join(tempDir, "trash", "atoms", "old.md")
+	recentNote := filepath.Join(tempDir, "trash", "atoms", "recent.md")
+	for _, path := range []string{oldNote, recentNote} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create trash directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatalf("failed to write trashed note: %v", err)
+		}
+	}
+	old := time.Now().Add(-45 * 24 * time.Hour)
+	if err := os.Chtimes(oldNote, old, old); err != nil {
+		t.Fatalf("failed to age trashed note: %v", err)
+	}
+
+	s := &state.State{Config: cfg, Workspace: ws, Handler: handler.NewFileHandler(tempDir)}
+	if err := purgeExpiredTrash(s); err != nil {
+		t.Fatalf("purgeExpiredTrash returned error: %v", err)
+	}
+
+	if _, err := os.Stat(oldNote); !os.IsNotExist(err) {
+		t.Fatalf("expected note past retention to be purged, got %v", err)
+	}
+	if _, err := os.Stat(recentNote); err != nil {
+		t.Fatalf("expected recent note to be kept: %v", err)
+	}
+
+	ws.Trash.Retention = "soon"
+	if err := purgeExpiredTrash(s); err == nil {
+		t.Fatalf("expected an invalid retention to be reported")
+	}
+}
+
+func TestSetTagFilterMatchesNestedTags(t *testing.T) {
+	model := newEditorTestModel(t, map[string]string{
+		"apollo.md": "---\ntitle: Apollo\ntags:\n  - project/apollo\n---\nApollo body",
+		"other.md":  "---\ntitle: Other\ntags:\n  - personal\n---\nOther body",
+	})
+
+	model = drainNoteCmd(t, model, model.SetTagFilter([]string{"project"}))
+
+	items := model.list.Items()
+	if len(items) != 1 {
+		t.Fatalf("expected 1 filtered item, got %d", len(items))
+	}
+	if li, ok := items[0].(ListItem); !ok || filepath.Base(li.path) != "apollo.md" {
+		t.Fatalf("expected the nested tag to match its parent, got %#v", items[0])
+	}
+}
@@ -251,7 +251,7 @@ func (m FormModel) handleSubmit() FormModel {
 		return m
 	}
 
-	tags, err := utils.ValidateInput(m.Inputs[tags].Value())
+	tags, err := utils.ValidateTags(m.Inputs[tags].Value())
 	if err != nil {
 		return m
 	}
@@ -14,7 +14,10 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"gopkg.in/yaml.v2"
 
+	"github.com/Paintersrp/an/internal/oplog"
 	"github.com/Paintersrp/an/internal/pathutil"
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/internal/state"
 )
 
 func ParseNoteFiles(noteFiles []string, vaultDir string, asFileDetails bool) []list.Item {
@@ -193,6 +196,7 @@ func renameFile(m NoteListModel) error {
 			return err
 		}
 
+		var before []byte
 		_, _, start, end := parseFrontMatter(content, s.fileName)
 		if updatedContent, updated, err := updateFrontMatterTitle(content, start, end, newName); err != nil {
 			m.list.NewStatusMessage(
@@ -200,12 +204,19 @@ func renameFile(m NoteListModel) error {
 			)
 			return err
 		} else if updated {
+			if err := historyFor(m.state).SaveFile("rename", s.path); err != nil {
+				m.list.NewStatusMessage(
+					statusStyle(fmt.Sprintf("Error saving snapshot: %s", err)),
+				)
+				return err
+			}
 			if err := os.WriteFile(s.path, updatedContent, 0o644); err != nil {
 				m.list.NewStatusMessage(
 					statusStyle(fmt.Sprintf("Error writing file: %s", err)),
 				)
 				return err
 			}
+			before = content
 			content = updatedContent
 		}
 
@@ -214,6 +225,15 @@ func renameFile(m NoteListModel) error {
 				m.list.NewStatusMessage(statusStyle(fmt.Sprintf("Error renaming: %s", err)))
 				return err
 			}
+			if err := historyFor(m.state).Rename(s.path, newPath); err != nil {
+				m.list.NewStatusMessage(statusStyle(fmt.Sprintf("Renamed, but history not moved: %s", err)))
+			}
+		}
+
+		if needsRename || before != nil {
+			if err := journalFor(m.state).RecordRename("rename", s.path, newPath, before); err != nil {
+				m.list.NewStatusMessage(statusStyle(fmt.Sprintf("Renamed, but not journaled: %s", err)))
+			}
 		}
 	}
 	return nil
@@ -270,11 +290,32 @@ func copyFile(m NoteListModel) error {
 			return err
 		}
 
+		if err := journalFor(m.state).RecordWrite("copy", newPath, nil, false); err != nil {
+			m.list.NewStatusMessage(statusStyle(fmt.Sprintf("Copied, but not journaled: %s", err)))
+			return nil
+		}
+
 		m.list.NewStatusMessage(statusStyle("File copied and title updated successfully"))
 	}
 	return nil
 }
 
+// journalFor returns the operation journal of the state's vault, or nil when
+// there is none. A nil journal records nothing.
+func journalFor(s *state.State) *oplog.Journal {
+	if s == nil {
+		return nil
+	}
+	return s.Handler.Journal()
+}
+
+func historyFor(s *state.State) *snapshot.Store {
+	if s == nil {
+		return nil
+	}
+	return s.Handler.History()
+}
+
 func castToListItems(items []list.Item) []ListItem {
 	var listItems []ListItem
 	for _, item := range items {
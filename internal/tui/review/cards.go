@@ -0,0 +1,243 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+// CardsModel drills flashcards extracted from notes using a flip-card layout.
+// It shares the review model key bindings so grading works the same way in
+// both views.
+type CardsModel struct {
+	state     *state.State
+	cards     []reviewsvc.Card
+	keys      keyMap
+	index     int
+	revealed  bool
+	grading   bool
+	results   map[string]string
+	drilled   []reviewsvc.Card
+	status    string
+	width     int
+	height    int
+	saving    bool
+	savedPath string
+	err       error
+}
+
+type cardGradedMsg struct {
+	card  reviewsvc.Card
+	grade reviewsvc.Grade
+	entry reviewsvc.ScheduleEntry
+	err   error
+}
+
+type cardsSavedMsg struct {
+	path string
+	err  error
+}
+
+// NewCardsModel constructs a flashcard drill over the provided cards.
+func NewCardsModel(st *state.State, cards []reviewsvc.Card) (*CardsModel, error) {
+	if st == nil || strings.TrimSpace(st.Vault) == "" {
+		return nil, fmt.Errorf("card review requires configured state dependencies")
+	}
+	return &CardsModel{
+		state:   st,
+		cards:   append([]reviewsvc.Card(nil), cards...),
+		keys:    newKeyMap(),
+		results: make(map[string]string),
+	}, nil
+}
+
+// RunCards drills the provided cards and returns the path of the saved review
+// log, if any cards were graded.
+func RunCards(st *state.State, cards []reviewsvc.Card) (string, error) {
+	model, err := NewCardsModel(st, cards)
+	if err != nil {
+		return "", err
+	}
+	final, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if err != nil {
+		return "", err
+	}
+	if m, ok := final.(*CardsModel); ok {
+		return m.savedPath, m.err
+	}
+	return "", nil
+}
+
+func (m *CardsModel) Init() tea.Cmd {
+	if len(m.cards) == 0 {
+		m.status = "No flashcards are due."
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *CardsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case cardGradedMsg:
+		m.grading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Failed to grade card: %v", msg.err)
+			return m, nil
+		}
+		m.results[msg.card.ScheduleKey()] = msg.grade.String()
+		m.drilled = append(m.drilled, msg.card)
+		m.status = fmt.Sprintf("Graded %s — next review in %s", msg.grade, humanizeAge(msg.entry.Interval()))
+		m.index++
+		m.revealed = false
+		if m.index >= len(m.cards) {
+			return m, m.finish()
+		}
+		return m, nil
+	case cardsSavedMsg:
+		m.savedPath = msg.path
+		m.err = msg.err
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if m.saving || m.grading {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.exit), key.Matches(msg, m.keys.complete):
+			return m, m.finish()
+		case key.Matches(msg, m.keys.flip):
+			m.revealed = !m.revealed
+			return m, nil
+		case key.Matches(msg, m.keys.gradeAgain):
+			return m, m.grade(reviewsvc.GradeAgain)
+		case key.Matches(msg, m.keys.gradeHard):
+			return m, m.grade(reviewsvc.GradeHard)
+		case key.Matches(msg, m.keys.gradeGood):
+			return m, m.grade(reviewsvc.GradeGood)
+		case key.Matches(msg, m.keys.gradeEasy):
+			return m, m.grade(reviewsvc.GradeEasy)
+		}
+	}
+	return m, nil
+}
+
+func (m *CardsModel) View() string {
+	if len(m.cards) == 0 {
+		return appStyle.Render("No flashcards are due.")
+	}
+	if m.index >= len(m.cards) {
+		return appStyle.Render(statusStyle.Render("Saving card review..."))
+	}
+
+	card := m.cards[m.index]
+	width := m.width - 8
+	if width <= 0 || width > 80 {
+		width = 80
+	}
+
+	face := lipgloss.JoinVertical(lipgloss.Left, "Q: "+card.Question)
+	if m.revealed {
+		face = lipgloss.JoinVertical(lipgloss.Left, face, "", card.Answer)
+	}
+
+	sections := []string{
+		headerStyle.Render("Flashcards"),
+		fmt.Sprintf("Card %d of %d — %s:%d", m.index+1, len(m.cards), card.Path, card.Line),
+		cardStyle.Width(width).Render(face),
+	}
+	if m.revealed {
+		sections = append(sections, "Grade: alt+1 again · alt+2 hard · alt+3 good · alt+4 easy")
+	} else {
+		sections = append(sections, "Press space to reveal the answer · esc to finish")
+	}
+	if m.status != "" {
+		sections = append(sections, statusStyle.Render(m.status))
+	}
+	return appStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+func (m *CardsModel) grade(grade reviewsvc.Grade) tea.Cmd {
+	if m.index >= len(m.cards) {
+		return nil
+	}
+	if !m.revealed {
+		m.status = "Reveal the answer before grading."
+		return nil
+	}
+	m.grading = true
+	card := m.cards[m.index]
+	vault := m.state.Vault
+	return func() tea.Msg {
+		schedule, err := reviewsvc.LoadSchedule(vault)
+		if err != nil {
+			return cardGradedMsg{card: card, grade: grade, err: err}
+		}
+		entry := schedule.Grade(card.ScheduleKey(), grade, time.Now())
+		if err := schedule.Save(); err != nil {
+			return cardGradedMsg{card: card, grade: grade, err: err}
+		}
+		return cardGradedMsg{card: card, grade: grade, entry: entry}
+	}
+}
+
+func (m *CardsModel) finish() tea.Cmd {
+	if len(m.drilled) == 0 {
+		return tea.Quit
+	}
+	m.saving = true
+	st := m.state
+	drilled := append([]reviewsvc.Card(nil), m.drilled...)
+	results := cloneStringMap(m.results)
+	return func() tea.Msg {
+		path, err := persistCardLog(st, drilled, results, time.Now().UTC())
+		return cardsSavedMsg{path: path, err: err}
+	}
+}
+
+// persistCardLog records drilled cards in the review log directory using the
+// shared Markdown log format. Source notes are listed as the session queue.
+func persistCardLog(
+	st *state.State,
+	cards []reviewsvc.Card,
+	results map[string]string,
+	ts time.Time,
+) (string, error) {
+	if st == nil {
+		return "", errors.New("state is not configured")
+	}
+	dir, _, err := ensureReviewDir(st)
+	if err != nil {
+		return "", err
+	}
+
+	seen := make(map[string]struct{}, len(cards))
+	var queue []reviewsvc.ResurfaceItem
+	for _, card := range cards {
+		if _, ok := seen[card.Path]; ok {
+			continue
+		}
+		seen[card.Path] = struct{}{}
+		path := filepath.Join(st.Vault, filepath.FromSlash(card.Path))
+		item := reviewsvc.ResurfaceItem{Path: path, Bucket: "cards"}
+		if info, err := os.Stat(path); err == nil {
+			item.ModifiedAt = info.ModTime()
+		}
+		queue = append(queue, item)
+	}
+
+	manifest := reviewsvc.CardSessionManifest(cards)
+	return reviewsvc.WriteMarkdownLog(dir, manifest, results, queue, ts, st.Vault)
+}
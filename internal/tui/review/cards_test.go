@@ -0,0 +1,57 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+)
+
+func TestCardsModelFlipGradeAndSave(t *testing.T) {
+	tempDir := t.TempDir()
+	st := newTestState(t, tempDir)
+
+	cards := reviewsvc.ExtractCards(tempDir, filepath.Join(tempDir, "atoms", "card.md"), []byte("Capital of France? :: Paris\n"))
+	model, err := NewCardsModel(st, cards)
+	if err != nil {
+		t.Fatalf("NewCardsModel returned error: %v", err)
+	}
+
+	if strings.Contains(model.View(), "Paris") {
+		t.Fatalf("expected answer to be hidden before flipping")
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3"), Alt: true}); cmd != nil {
+		t.Fatalf("expected grading to wait for the answer to be revealed")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if !strings.Contains(model.View(), "Paris") {
+		t.Fatalf("expected answer after flipping, got %q", model.View())
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3"), Alt: true})
+	if cmd == nil {
+		t.Fatal("expected grading command")
+	}
+	_, saveCmd := model.Update(cmd())
+	if saveCmd == nil {
+		t.Fatal("expected save command after the last card")
+	}
+	model.Update(saveCmd())
+
+	if model.err != nil {
+		t.Fatalf("saving card log failed: %v", model.err)
+	}
+	content, err := os.ReadFile(model.savedPath)
+	if err != nil {
+		t.Fatalf("expected card log to be written: %v", err)
+	}
+	if !strings.Contains(string(content), "Capital of France? (atoms/card.md):** good") {
+		t.Fatalf("expected graded card in review log, got %s", content)
+	}
+}
@@ -33,6 +33,7 @@ type Model struct {
 	height          int
 	step            int
 	mode            reviewMode
+	modes           []reviewMode
 	showGraph       bool
 	status          string
 	loading         bool
@@ -43,6 +44,7 @@ type Model struct {
 	historyPreview  map[string][]string
 	historySelected int
 	historyLoading  bool
+	queueSelected   int
 }
 
 type keyMap struct {
@@ -55,9 +57,17 @@ type keyMap struct {
 	daily       key.Binding
 	weekly      key.Binding
 	retro       key.Binding
+	queueMode   key.Binding
 	historyNext key.Binding
 	historyPrev key.Binding
 	historyOpen key.Binding
+	queueNext   key.Binding
+	queuePrev   key.Binding
+	gradeAgain  key.Binding
+	gradeHard   key.Binding
+	gradeGood   key.Binding
+	gradeEasy   key.Binding
+	flip        key.Binding
 	exit        key.Binding
 }
 
@@ -65,6 +75,9 @@ type reviewMode struct {
 	Name     string
 	Template string
 	Key      string
+	// Queue holds the configured preset backing the mode. Built-in modes
+	// leave it nil and use the default resurfacing buckets.
+	Queue *reviewsvc.QueuePreset
 }
 
 type reviewTab int
@@ -95,6 +108,13 @@ type historySelectedMsg struct {
 	err  error
 }
 
+type noteGradedMsg struct {
+	path  string
+	grade reviewsvc.Grade
+	entry reviewsvc.ScheduleEntry
+	err   error
+}
+
 // ExitRequestedMsg indicates that the user requested to leave the review view.
 type ExitRequestedMsg struct{}
 
@@ -107,6 +127,9 @@ var modes = []reviewMode{
 const (
 	defaultQueueLimit = 12
 	editorMinHeight   = 5
+	// maxQueueModes caps how many review.queues presets are bound to the
+	// number keys following the built-in modes.
+	maxQueueModes = 6
 )
 
 func NewModel(st *state.State) (*Model, error) {
@@ -115,7 +138,11 @@ func NewModel(st *state.State) (*Model, error) {
 	}
 
 	editor := textarea.New(0, 0)
-	mode := modes[0]
+	available, err := loadModes(st)
+	if err != nil {
+		return nil, err
+	}
+	mode := available[0]
 	manifest, err := st.Templater.Manifest(mode.Template)
 	if err != nil {
 		return nil, fmt.Errorf("load %s manifest: %w", mode.Template, err)
@@ -128,6 +155,7 @@ func NewModel(st *state.State) (*Model, error) {
 		editor:         editor,
 		keys:           newKeyMap(),
 		mode:           mode,
+		modes:          available,
 		showGraph:      true,
 		activeTab:      tabChecklist,
 		historyPreview: make(map[string][]string),
@@ -174,6 +202,10 @@ func newKeyMap() keyMap {
 			key.WithKeys("3"),
 			key.WithHelp("3", "retro mode"),
 		),
+		queueMode: key.NewBinding(
+			key.WithKeys("4", "5", "6", "7", "8", "9"),
+			key.WithHelp("4-9", "queue presets"),
+		),
 		historyNext: key.NewBinding(
 			key.WithKeys("down", "ctrl+n"),
 			key.WithHelp("↓/ctrl+n", "next log"),
@@ -186,6 +218,34 @@ func newKeyMap() keyMap {
 			key.WithKeys("enter", "ctrl+enter"),
 			key.WithHelp("enter", "open log"),
 		),
+		queueNext: key.NewBinding(
+			key.WithKeys("alt+down", "alt+j"),
+			key.WithHelp("alt+↓", "next queue item"),
+		),
+		queuePrev: key.NewBinding(
+			key.WithKeys("alt+up", "alt+k"),
+			key.WithHelp("alt+↑", "previous queue item"),
+		),
+		gradeAgain: key.NewBinding(
+			key.WithKeys("alt+1"),
+			key.WithHelp("alt+1", "grade again"),
+		),
+		gradeHard: key.NewBinding(
+			key.WithKeys("alt+2"),
+			key.WithHelp("alt+2", "grade hard"),
+		),
+		gradeGood: key.NewBinding(
+			key.WithKeys("alt+3"),
+			key.WithHelp("alt+3", "grade good"),
+		),
+		gradeEasy: key.NewBinding(
+			key.WithKeys("alt+4"),
+			key.WithHelp("alt+4", "grade easy"),
+		),
+		flip: key.NewBinding(
+			key.WithKeys(" ", "enter"),
+			key.WithHelp("space", "flip card"),
+		),
 		exit: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "exit review"),
@@ -219,6 +279,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.queue = msg.queue
 		m.graph = msg.graph
 		m.ready = true
+		m.clampQueueSelection()
 		if len(m.queue) == 0 {
 			m.status = "No notes are due for resurfacing."
 		} else {
@@ -272,6 +333,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = fmt.Sprintf("Loaded %d review logs", len(m.history))
 		}
 		return m, nil
+	case noteGradedMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Failed to grade note: %v", msg.err)
+			return m, nil
+		}
+		m.removeFromQueue(msg.path)
+		m.status = fmt.Sprintf(
+			"Graded %s as %s — next review in %s",
+			m.relativePath(msg.path),
+			msg.grade,
+			humanizeAge(msg.entry.Interval()),
+		)
+		return m, nil
 	case historySelectedMsg:
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Failed to open review log: %v", msg.err)
@@ -352,11 +426,17 @@ func (m *Model) handleKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		}
 		return true, nil
 	case key.Matches(msg, m.keys.daily):
-		return true, m.switchMode(modes[0])
+		return true, m.switchMode(m.modes[0])
 	case key.Matches(msg, m.keys.weekly):
-		return true, m.switchMode(modes[1])
+		return true, m.switchMode(m.modes[1])
 	case key.Matches(msg, m.keys.retro):
-		return true, m.switchMode(modes[2])
+		return true, m.switchMode(m.modes[2])
+	case key.Matches(msg, m.keys.queueMode):
+		idx := int(msg.String()[0] - '1')
+		if idx >= len(m.modes) {
+			return false, nil
+		}
+		return true, m.switchMode(m.modes[idx])
 	case key.Matches(msg, m.keys.exit):
 		return true, exitRequestedCmd
 	}
@@ -378,6 +458,20 @@ func (m *Model) handleKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 	}
 
 	switch {
+	case key.Matches(msg, m.keys.queueNext):
+		m.moveQueueSelection(1)
+		return true, nil
+	case key.Matches(msg, m.keys.queuePrev):
+		m.moveQueueSelection(-1)
+		return true, nil
+	case key.Matches(msg, m.keys.gradeAgain):
+		return true, m.gradeSelected(reviewsvc.GradeAgain)
+	case key.Matches(msg, m.keys.gradeHard):
+		return true, m.gradeSelected(reviewsvc.GradeHard)
+	case key.Matches(msg, m.keys.gradeGood):
+		return true, m.gradeSelected(reviewsvc.GradeGood)
+	case key.Matches(msg, m.keys.gradeEasy):
+		return true, m.gradeSelected(reviewsvc.GradeEasy)
 	case key.Matches(msg, m.keys.nextStep):
 		m.advanceStep(1)
 		return true, nil
@@ -386,6 +480,15 @@ func (m *Model) handleKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		return true, nil
 	case key.Matches(msg, m.keys.complete):
 		m.persistCurrentResponse()
+		normalized, step, err := reviewsvc.NormalizeResponses(m.manifest, m.responses, m.queue)
+		if err != nil {
+			m.confirmingSave = false
+			m.step = step
+			m.applyCurrentFieldDefaults()
+			m.status = err.Error()
+			return true, nil
+		}
+		m.responses = normalized
 		if !m.confirmingSave {
 			m.confirmingSave = true
 			m.status = "Press ctrl+enter again to save the review log, or esc to cancel."
@@ -401,9 +504,10 @@ func (m *Model) handleKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 }
 
 func (m *Model) switchMode(mode reviewMode) tea.Cmd {
-	if mode.Template == m.mode.Template {
+	if mode.Name == m.mode.Name {
 		return nil
 	}
+	queueChanged := mode.Queue != m.mode.Queue
 	manifest, err := m.state.Templater.Manifest(mode.Template)
 	if err != nil {
 		m.status = fmt.Sprintf("load %s manifest failed: %v", mode.Template, err)
@@ -422,6 +526,9 @@ func (m *Model) switchMode(mode reviewMode) tea.Cmd {
 			delete(m.historyPreview, key)
 		}
 	}
+	if queueChanged {
+		return tea.Batch(m.loadHistory(), m.refreshQueue())
+	}
 	return m.loadHistory()
 }
 
@@ -508,7 +615,14 @@ func (m *Model) loadHistory() tea.Cmd {
 
 func (m *Model) renderHeader() string {
 	title := headerStyle.Render("Review")
-	info := fmt.Sprintf("Mode: %s — Press 1/2/3 to switch modes — Press tab to toggle history", m.mode.Name)
+	info := fmt.Sprintf(
+		"Mode: %s — Press 1-%d to switch modes — Press tab to toggle history",
+		m.mode.Name,
+		len(m.modes),
+	)
+	if m.mode.Queue != nil && m.mode.Queue.Description != "" {
+		info += "\n" + m.mode.Queue.Description
+	}
 	if m.loading {
 		info += " (refreshing...)"
 	}
@@ -545,11 +659,16 @@ func (m *Model) renderQueue() string {
 	lines := []string{fmt.Sprintf("Resurfacing queue (%d items):", len(m.queue))}
 	for i := 0; i < limit; i++ {
 		item := m.queue[i]
-		lines = append(lines, fmt.Sprintf("%2d. %s — last touched %s (%s)", i+1, m.relativePath(item.Path), humanizeAge(item.Age), item.Bucket))
+		indicator := " "
+		if i == m.queueSelected {
+			indicator = "•"
+		}
+		lines = append(lines, fmt.Sprintf("%s%2d. %s — last touched %s (%s)", indicator, i+1, m.relativePath(item.Path), humanizeAge(item.Age), item.Bucket))
 	}
 	if len(m.queue) > limit {
 		lines = append(lines, fmt.Sprintf("…and %d more", len(m.queue)-limit))
 	}
+	lines = append(lines, "Grade the selected note: alt+1 again · alt+2 hard · alt+3 good · alt+4 easy · alt+↑/↓ select")
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
@@ -667,8 +786,18 @@ func (m *Model) renderChecklist() string {
 	if field.Prompt != "" {
 		lines = append(lines, field.Prompt)
 	}
-	if len(field.Options) > 0 {
-		lines = append(lines, fmt.Sprintf("Options: %s", strings.Join(field.Options, ", ")))
+	if hint := reviewsvc.FieldHint(field); hint != "" {
+		lines = append(lines, hint)
+	}
+	switch reviewsvc.FieldKind(field) {
+	case reviewsvc.FieldSelect, reviewsvc.FieldMultiSelect:
+		options := make([]string, len(field.Options))
+		for i, option := range field.Options {
+			options[i] = fmt.Sprintf("%d) %s", i+1, option)
+		}
+		lines = append(lines, "Options: "+strings.Join(options, " · "))
+	case reviewsvc.FieldNotes:
+		lines = append(lines, "Use the numbers shown in the resurfacing queue.")
 	}
 	if len(field.Defaults) > 0 {
 		lines = append(lines, fmt.Sprintf("Suggested focus tags: %s", strings.Join(field.Defaults, ", ")))
@@ -699,8 +828,9 @@ func (m *Model) refreshQueue() tea.Cmd {
 	m.loading = true
 	m.status = "Refreshing resurfacing queue..."
 	state := m.state
+	preset := m.mode.Queue
 	return func() tea.Msg {
-		queue, graph, err := buildArtifacts(state)
+		queue, graph, err := buildArtifacts(state, preset)
 		return queueLoadedMsg{queue: queue, graph: graph, err: err}
 	}
 }
@@ -720,11 +850,81 @@ func (m *Model) computeGraph() tea.Cmd {
 	}
 }
 
+func (m *Model) moveQueueSelection(delta int) {
+	if len(m.queue) == 0 {
+		return
+	}
+	m.queueSelected += delta
+	m.clampQueueSelection()
+	item := m.queue[m.queueSelected]
+	m.status = fmt.Sprintf("Selected %s", m.relativePath(item.Path))
+}
+
+func (m *Model) clampQueueSelection() {
+	limit := len(m.queue)
+	if limit > defaultQueueLimit {
+		limit = defaultQueueLimit
+	}
+	if m.queueSelected >= limit {
+		m.queueSelected = limit - 1
+	}
+	if m.queueSelected < 0 {
+		m.queueSelected = 0
+	}
+}
+
+func (m *Model) removeFromQueue(path string) {
+	for i, item := range m.queue {
+		if item.Path != path {
+			continue
+		}
+		m.queue = append(m.queue[:i:i], m.queue[i+1:]...)
+		break
+	}
+	m.clampQueueSelection()
+}
+
+// gradeSelected records a spaced-repetition grade for the highlighted queue
+// item and persists the updated schedule.
+func (m *Model) gradeSelected(grade reviewsvc.Grade) tea.Cmd {
+	if len(m.queue) == 0 {
+		m.status = "No resurfaced notes to grade."
+		return nil
+	}
+	m.clampQueueSelection()
+	path := m.queue[m.queueSelected].Path
+	vault := m.state.Vault
+	return func() tea.Msg {
+		schedule, err := reviewsvc.LoadSchedule(vault)
+		if err != nil {
+			return noteGradedMsg{path: path, grade: grade, err: err}
+		}
+		entry := schedule.Grade(path, grade, time.Now())
+		if err := schedule.Save(); err != nil {
+			return noteGradedMsg{path: path, grade: grade, err: err}
+		}
+		return noteGradedMsg{path: path, grade: grade, entry: entry}
+	}
+}
+
 func (m *Model) advanceStep(delta int) {
 	if len(m.manifest.Fields) == 0 {
 		return
 	}
 	m.persistCurrentResponse()
+	if delta > 0 {
+		field := m.manifest.Fields[m.step]
+		value, err := reviewsvc.NormalizeResponse(field, m.responses[field.Key], m.queue)
+		if err != nil {
+			title := field.Label
+			if title == "" {
+				title = humanizeKey(field.Key)
+			}
+			m.status = fmt.Sprintf("%s: %v", title, err)
+			return
+		}
+		m.responses[field.Key] = value
+	}
 	next := m.step + delta
 	if next < 0 {
 		next = 0
@@ -807,8 +1007,11 @@ func (m *Model) joinPaths(paths []string) string {
 	return strings.Join(converted, ", ")
 }
 
-func buildArtifacts(st *state.State) ([]reviewsvc.ResurfaceItem, reviewsvc.Graph, error) {
-	queue, idx, err := buildQueue(st)
+func buildArtifacts(
+	st *state.State,
+	preset *reviewsvc.QueuePreset,
+) ([]reviewsvc.ResurfaceItem, reviewsvc.Graph, error) {
+	queue, idx, err := buildQueue(st, preset)
 	if err != nil {
 		return nil, reviewsvc.Graph{}, err
 	}
@@ -823,7 +1026,39 @@ func buildArtifacts(st *state.State) ([]reviewsvc.ResurfaceItem, reviewsvc.Graph
 	return queue, graph, nil
 }
 
-func buildQueue(st *state.State) ([]reviewsvc.ResurfaceItem, *search.Index, error) {
+// loadModes returns the built-in review modes followed by the queue presets
+// configured under review.queues for the current workspace.
+func loadModes(st *state.State) ([]reviewMode, error) {
+	available := append([]reviewMode(nil), modes...)
+	ws := st.Config.MustWorkspace()
+	presets, err := reviewsvc.LoadQueuePresets(ws.Review)
+	if err != nil {
+		return nil, fmt.Errorf("load review queues: %w", err)
+	}
+	if len(presets) > maxQueueModes {
+		presets = presets[:maxQueueModes]
+	}
+	for i := range presets {
+		preset := presets[i]
+		mode := reviewMode{
+			Name:     preset.Name,
+			Template: modes[0].Template,
+			Key:      modes[0].Key,
+			Queue:    &preset,
+		}
+		if preset.Template != "" {
+			mode.Template = preset.Template
+			mode.Key = strings.TrimPrefix(preset.Template, "review-")
+		}
+		available = append(available, mode)
+	}
+	return available, nil
+}
+
+func buildQueue(
+	st *state.State,
+	preset *reviewsvc.QueuePreset,
+) ([]reviewsvc.ResurfaceItem, *search.Index, error) {
 	if st == nil || st.Config == nil {
 		return nil, nil, errors.New("state is not configured")
 	}
@@ -836,17 +1071,25 @@ func buildQueue(st *state.State) ([]reviewsvc.ResurfaceItem, *search.Index, erro
 		Tags:     append([]string(nil), ws.Search.DefaultTagFilters...),
 		Metadata: cloneMetadata(ws.Search.DefaultMetadataFilters),
 	}
+	schedule, err := reviewsvc.LoadSchedule(st.Vault)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load review schedule: %w", err)
+	}
+	opts := reviewsvc.ResurfaceOptions{
+		Now:      time.Now(),
+		Limit:    defaultQueueLimit,
+		Buckets:  reviewsvc.DefaultBuckets(),
+		Query:    query,
+		Schedule: schedule,
+	}
+	if preset != nil {
+		opts = preset.Apply(opts)
+	}
 
 	if st.Index != nil {
 		snapshot, err := st.Index.AcquireSnapshot()
 		if err == nil && snapshot != nil {
-			queue := reviewsvc.BuildResurfaceQueue(snapshot, reviewsvc.ResurfaceOptions{
-				Now:        time.Now(),
-				MinimumAge: 0,
-				Limit:      defaultQueueLimit,
-				Buckets:    reviewsvc.DefaultBuckets(),
-				Query:      query,
-			})
+			queue := reviewsvc.BuildResurfaceQueue(snapshot, opts)
 			return queue, snapshot, nil
 		}
 		if err != nil && !errors.Is(err, indexsvc.ErrUnavailable) && !errors.Is(err, indexsvc.ErrClosed) {
@@ -862,13 +1105,7 @@ func buildQueue(st *state.State) ([]reviewsvc.ResurfaceItem, *search.Index, erro
 	if err := idx.Build(paths); err != nil {
 		return nil, nil, fmt.Errorf("build search index: %w", err)
 	}
-	queue := reviewsvc.BuildResurfaceQueue(idx, reviewsvc.ResurfaceOptions{
-		Now:        time.Now(),
-		MinimumAge: 0,
-		Limit:      defaultQueueLimit,
-		Buckets:    reviewsvc.DefaultBuckets(),
-		Query:      query,
-	})
+	queue := reviewsvc.BuildResurfaceQueue(idx, opts)
 	return queue, idx, nil
 }
 
@@ -248,6 +248,143 @@ func TestHistoryToggleAndPreview(t *testing.T) {
 	}
 }
 
+func TestGradeSelectedQueueItem(t *testing.T) {
+	tempDir := t.TempDir()
+	st := newTestState(t, tempDir)
+
+	model, err := NewModel(st)
+	if err != nil {
+		t.Fatalf("NewModel returned error: %v", err)
+	}
+
+	first := filepath.Join(tempDir, "atoms", "first.md")
+	second := filepath.Join(tempDir, "atoms", "second.md")
+	model.queue = []reviewsvc.ResurfaceItem{
+		{Path: first, Bucket: "weekly"},
+		{Path: second, Bucket: "daily"},
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	m := adoptTestModel(updated)
+	if m.queueSelected != 1 {
+		t.Fatalf("expected second queue item to be selected, got %d", m.queueSelected)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3"), Alt: true})
+	if cmd == nil {
+		t.Fatal("expected grading command")
+	}
+	msg := cmd()
+	graded, ok := msg.(noteGradedMsg)
+	if !ok {
+		t.Fatalf("expected noteGradedMsg, got %T", msg)
+	}
+	if graded.err != nil {
+		t.Fatalf("grading failed: %v", graded.err)
+	}
+
+	updated, _ = m.Update(msg)
+	m = adoptTestModel(updated)
+	if len(m.queue) != 1 || m.queue[0].Path != first {
+		t.Fatalf("expected graded note to leave the queue, got %+v", m.queue)
+	}
+	if !strings.Contains(m.status, "good") {
+		t.Fatalf("expected status to mention grade, got %q", m.status)
+	}
+
+	schedule, err := reviewsvc.LoadSchedule(tempDir)
+	if err != nil {
+		t.Fatalf("LoadSchedule returned error: %v", err)
+	}
+	if _, ok := schedule.Entry(second); !ok {
+		t.Fatalf("expected schedule entry for graded note")
+	}
+}
+
+func TestQueuePresetModes(t *testing.T) {
+	tempDir := t.TempDir()
+	st := newTestState(t, tempDir)
+	st.Workspace.Review.Queues = map[string]config.ReviewQueue{
+		"reading": {
+			Description: "Literature notes",
+			Tags:        []string{"reading"},
+			Template:    "review-weekly",
+		},
+	}
+
+	model, err := NewModel(st)
+	if err != nil {
+		t.Fatalf("NewModel returned error: %v", err)
+	}
+	if len(model.modes) != len(modes)+1 {
+		t.Fatalf("expected preset to be appended to modes, got %+v", model.modes)
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	m := adoptTestModel(updated)
+	if cmd == nil {
+		t.Fatal("expected switching to a preset to reload history and queue")
+	}
+	if m.mode.Queue == nil || m.mode.Name != "reading" || m.mode.Template != "review-weekly" {
+		t.Fatalf("expected reading preset mode, got %+v", m.mode)
+	}
+	if !strings.Contains(m.renderHeader(), "1-4") || !strings.Contains(m.renderHeader(), "Literature notes") {
+		t.Fatalf("expected header to list preset keys and description, got %q", m.renderHeader())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})
+	if adoptTestModel(updated).mode.Name != "reading" {
+		t.Fatalf("expected unbound preset key to be ignored")
+	}
+}
+
+func TestTypedFieldValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	st := newTestState(t, tempDir)
+
+	model, err := NewModel(st)
+	if err != nil {
+		t.Fatalf("NewModel returned error: %v", err)
+	}
+	model.manifest = templater.TemplateManifest{
+		Name: "review-custom",
+		Fields: []templater.TemplateField{
+			{Key: "energy", Label: "Energy", Type: "rating"},
+			{Key: "focus", Label: "Focus", Options: []string{"deep", "shallow"}, Required: true},
+		},
+	}
+	model.step = 0
+	model.applyCurrentFieldDefaults()
+
+	model.editor.SetValue("7")
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m := adoptTestModel(updated)
+	if m.step != 0 || !strings.Contains(m.status, "Energy") {
+		t.Fatalf("expected invalid rating to block advancing, step %d status %q", m.step, m.status)
+	}
+
+	m.editor.SetValue("4")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = adoptTestModel(updated)
+	if m.step != 1 || m.responses["energy"] != "4" {
+		t.Fatalf("expected valid rating to advance, step %d responses %#v", m.step, m.responses)
+	}
+
+	m.editor.SetValue("")
+	updated, _ = m.Update(ctrlEnterMsg())
+	m = adoptTestModel(updated)
+	if m.confirmingSave || !strings.Contains(m.status, "Focus") {
+		t.Fatalf("expected required select to block saving, status %q", m.status)
+	}
+
+	m.editor.SetValue("1")
+	updated, _ = m.Update(ctrlEnterMsg())
+	m = adoptTestModel(updated)
+	if !m.confirmingSave || m.responses["focus"] != "deep" {
+		t.Fatalf("expected numbered option to normalize before saving, responses %#v", m.responses)
+	}
+}
+
 func newTestState(t *testing.T, vault string) *state.State {
 	t.Helper()
 
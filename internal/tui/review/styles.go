@@ -25,4 +25,11 @@ var (
 
 	historyPreviewStyle = lipgloss.NewStyle().
 				MarginLeft(4)
+
+	cardStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#0AF")).
+			Padding(1, 2).
+			MarginTop(1).
+			MarginBottom(1)
 )
@@ -0,0 +1,290 @@
+// Package tags shows the tag hierarchy as a table whose nested tags can be
+// collapsed and expanded.
+package tags
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	tagsvc "github.com/Paintersrp/an/internal/services/tags"
+	tableTui "github.com/Paintersrp/an/internal/tui/table"
+)
+
+var (
+	baseStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color("240"))
+
+	helpStyle = lipgloss.NewStyle().Faint(true)
+)
+
+type keyMap struct {
+	expand    key.Binding
+	collapse  key.Binding
+	toggle    key.Binding
+	expandAll key.Binding
+	open      key.Binding
+	quit      key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		expand:    key.NewBinding(key.WithKeys("right", "l")),
+		collapse:  key.NewBinding(key.WithKeys("left", "h")),
+		toggle:    key.NewBinding(key.WithKeys(" ")),
+		expandAll: key.NewBinding(key.WithKeys("e")),
+		open:      key.NewBinding(key.WithKeys("enter")),
+		quit:      key.NewBinding(key.WithKeys("q", "esc", "ctrl+c")),
+	}
+}
+
+// row is one visible node of the tree.
+type row struct {
+	node  *tagsvc.Node
+	depth int
+}
+
+// relatedShown caps the co-occurring tags listed below the table.
+const relatedShown = 5
+
+// Model is the tag table. Nested tags start collapsed under their parents.
+type Model struct {
+	roots    []*tagsvc.Node
+	stats    map[string]tagsvc.Stat
+	expanded map[string]bool
+	rows     []row
+	table    table.Model
+	keys     keyMap
+	selected string
+}
+
+// NewModel builds the table for the given tag tree. Stats supply the last
+// used dates and related tags; tags without stats show neither.
+func NewModel(roots []*tagsvc.Node, stats []tagsvc.Stat) *Model {
+	cfg := tableTui.TableConfig{
+		Columns: []table.Column{
+			{Title: "Tag", Width: 36},
+			{Title: "Notes", Width: 8},
+			{Title: "Total", Width: 8},
+			{Title: "Last used", Width: 12},
+		},
+		Focused: true,
+		Height:  20,
+	}
+	byName := make(map[string]tagsvc.Stat, len(stats))
+	for _, stat := range stats {
+		byName[strings.ToLower(stat.Name)] = stat
+	}
+	m := &Model{
+		roots:    roots,
+		stats:    byName,
+		expanded: make(map[string]bool),
+		table:    cfg.ReturnTable(),
+		keys:     newKeyMap(),
+	}
+	m.refresh()
+	return m
+}
+
+// Run shows the table and returns the tag picked with enter, or an empty
+// string when the table was closed without a pick.
+func Run(roots []*tagsvc.Node, stats []tagsvc.Stat) (string, error) {
+	final, err := tea.NewProgram(NewModel(roots, stats)).Run()
+	if err != nil {
+		return "", err
+	}
+	if m, ok := final.(*Model); ok {
+		return m.selected, nil
+	}
+	return "", nil
+}
+
+// Selected returns the picked tag.
+func (m *Model) Selected() string {
+	return m.selected
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		current := m.current()
+		switch {
+		case key.Matches(msg, m.keys.quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.open):
+			if current != nil {
+				m.selected = current.node.Name
+			}
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.expand):
+			if current != nil && len(current.node.Children) > 0 {
+				m.expanded[current.node.Name] = true
+				m.refresh()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.collapse):
+			m.collapse(current)
+			return m, nil
+		case key.Matches(msg, m.keys.toggle):
+			if current != nil && len(current.node.Children) > 0 {
+				m.expanded[current.node.Name] = !m.expanded[current.node.Name]
+				m.refresh()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.expandAll):
+			m.toggleAll()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	if len(m.roots) == 0 {
+		return "No tags found.\n"
+	}
+	help := helpStyle.Render("enter open notes · →/l expand · ←/h collapse · space toggle · e expand all · q quit")
+	view := baseStyle.Render(m.table.View()) + "\n"
+	if related := m.related(); related != "" {
+		view += related + "\n"
+	}
+	return view + help + "\n"
+}
+
+// related lists the tags that most often share a note with the current tag.
+func (m *Model) related() string {
+	current := m.current()
+	if current == nil {
+		return ""
+	}
+	stat, ok := m.stats[strings.ToLower(current.node.Name)]
+	if !ok || len(stat.Related) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, relatedShown)
+	for i, r := range stat.Related {
+		if i == relatedShown {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", r.Name, r.Count))
+	}
+	return fmt.Sprintf("Notes tagged %s are also tagged %s", stat.Name, strings.Join(parts, ", "))
+}
+
+// collapse folds the current tag, or moves to its parent when it is already
+// folded or has no children.
+func (m *Model) collapse(current *row) {
+	if current == nil {
+		return
+	}
+	if m.expanded[current.node.Name] {
+		m.expanded[current.node.Name] = false
+		m.refresh()
+		return
+	}
+	idx := strings.LastIndex(current.node.Name, "/")
+	if idx < 0 {
+		return
+	}
+	parent := current.node.Name[:idx]
+	for i, r := range m.rows {
+		if r.node.Name == parent {
+			m.table.SetCursor(i)
+			return
+		}
+	}
+}
+
+// toggleAll expands every tag, or collapses them all when everything is
+// already expanded.
+func (m *Model) toggleAll() {
+	var parents []string
+	var walk func(nodes []*tagsvc.Node)
+	walk = func(nodes []*tagsvc.Node) {
+		for _, node := range nodes {
+			if len(node.Children) > 0 {
+				parents = append(parents, node.Name)
+				walk(node.Children)
+			}
+		}
+	}
+	walk(m.roots)
+
+	all := true
+	for _, name := range parents {
+		all = all && m.expanded[name]
+	}
+	for _, name := range parents {
+		m.expanded[name] = !all
+	}
+	m.refresh()
+}
+
+// refresh rebuilds the visible rows, keeping the cursor on the same tag when
+// it is still visible.
+func (m *Model) refresh() {
+	selected := ""
+	if current := m.current(); current != nil {
+		selected = current.node.Name
+	}
+
+	m.rows = m.rows[:0]
+	var walk func(nodes []*tagsvc.Node, depth int)
+	walk = func(nodes []*tagsvc.Node, depth int) {
+		for _, node := range nodes {
+			m.rows = append(m.rows, row{node: node, depth: depth})
+			if m.expanded[node.Name] {
+				walk(node.Children, depth+1)
+			}
+		}
+	}
+	walk(m.roots, 0)
+
+	rows := make([]table.Row, 0, len(m.rows))
+	cursor := 0
+	for i, r := range m.rows {
+		marker := "  "
+		if len(r.node.Children) > 0 {
+			marker = "▸ "
+			if m.expanded[r.node.Name] {
+				marker = "▾ "
+			}
+		}
+		label := r.node.Label
+		if r.depth == 0 {
+			label = r.node.Name
+		}
+		lastUsed := ""
+		if stat, ok := m.stats[strings.ToLower(r.node.Name)]; ok && !stat.LastUsed.IsZero() {
+			lastUsed = stat.LastUsed.Local().Format("2006-01-02")
+		}
+		rows = append(rows, table.Row{
+			strings.Repeat("  ", r.depth) + marker + label,
+			fmt.Sprintf("%d", r.node.Count),
+			fmt.Sprintf("%d", r.node.Total),
+			lastUsed,
+		})
+		if r.node.Name == selected {
+			cursor = i
+		}
+	}
+	m.table.SetRows(rows)
+	m.table.SetCursor(cursor)
+}
+
+func (m *Model) current() *row {
+	idx := m.table.Cursor()
+	if idx < 0 || idx >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[idx]
+}
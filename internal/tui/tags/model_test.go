@@ -0,0 +1,98 @@
+package tags
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Paintersrp/an/internal/search"
+	tagsvc "github.com/Paintersrp/an/internal/services/tags"
+)
+
+func press(t *testing.T, m *Model, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		m.Update(msg)
+	}
+}
+
+func names(m *Model) string {
+	var out []string
+	for _, r := range m.rows {
+		out = append(out, r.node.Name)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestModelExpandsAndCollapsesHierarchy(t *testing.T) {
+	m := NewModel(tagsvc.Tree(map[string]int{
+		"project/apollo":        2,
+		"project/apollo/launch": 1,
+		"project/gemini":        1,
+		"go":                    1,
+	}), nil)
+
+	if got := names(m); got != "project,go" {
+		t.Fatalf("expected nested tags to start collapsed, got %s", got)
+	}
+
+	press(t, m, "right")
+	if got := names(m); got != "project,project/apollo,project/gemini,go" {
+		t.Fatalf("unexpected rows after expanding, got %s", got)
+	}
+	if !strings.Contains(m.table.Rows()[1][0], "▸ apollo") {
+		t.Fatalf("expected children to be indented by label, got %q", m.table.Rows()[1][0])
+	}
+
+	press(t, m, "down", "left")
+	if cur := m.current(); cur == nil || cur.node.Name != "project" {
+		t.Fatalf("expected collapse on a folded child to move to the parent")
+	}
+	press(t, m, "left")
+	if got := names(m); got != "project,go" {
+		t.Fatalf("unexpected rows after collapsing, got %s", got)
+	}
+
+	press(t, m, "e")
+	if got := names(m); !strings.Contains(got, "project/apollo/launch") {
+		t.Fatalf("expected expand all to show every tag, got %s", got)
+	}
+}
+
+func TestModelSelectsTagOnEnter(t *testing.T) {
+	m := NewModel(tagsvc.Tree(map[string]int{"project/apollo": 1, "go": 3}), nil)
+	press(t, m, "down", "enter")
+	if got := m.Selected(); got != "project" {
+		t.Fatalf("expected enter to pick the tag under the cursor, got %q", got)
+	}
+}
+
+func TestModelShowsLastUsedAndRelatedTags(t *testing.T) {
+	stats := tagsvc.Stats([]search.Metadata{
+		{Path: "a.md", Tags: []string{"go", "cli"}, ModifiedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)},
+		{Path: "b.md", Tags: []string{"go"}, ModifiedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)},
+	})
+	m := NewModel(tagsvc.Tree(tagsvc.Counts(stats)), stats)
+
+	if got := m.table.Rows()[0][3]; got != "2024-03-01" {
+		t.Fatalf("expected the latest use of go, got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "Notes tagged go are also tagged cli (1)") {
+		t.Fatalf("expected related tags below the table, got:\n%s", view)
+	}
+}
@@ -14,22 +14,34 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/note"
 	"github.com/Paintersrp/an/internal/state"
 	"github.com/Paintersrp/an/internal/templater"
+	"github.com/Paintersrp/an/pkg/shared/arg"
 	"github.com/Paintersrp/an/utils"
 )
 
 var readClipboard = clipboard.ReadAll
 
+// captureOptions holds the flags of the capture command.
+type captureOptions struct {
+	templateName   string
+	title          string
+	viewName       string
+	upstream       string
+	tags           []string
+	links          []string
+	fields         []string
+	fence          string
+	skipPreview    bool
+	dryRun         bool
+	nonInteractive bool
+	readStdin      bool
+}
+
 // NewCmdCapture constructs the interactive capture command.
 func NewCmdCapture(s *state.State) *cobra.Command {
-	var templateName string
-	var title string
-	var skipPreview bool
-	var viewName string
-	var dryRun bool
+	var opts captureOptions
 
 	cmd := &cobra.Command{
 		Use:   "capture",
@@ -38,70 +50,115 @@ func NewCmdCapture(s *state.State) *cobra.Command {
                         Capture walks you through selecting a template, reviewing its preview, and collecting
                         the metadata required before the note hits disk. It is ideal for quick captures where you
                         want consistent front matter (status, effort, views) without memorising every flag.
+
+                        With --non-interactive nothing is prompted and no editor is opened, so capture can run
+                        from scripts and CI jobs: pass --template and --title, set template fields with
+                        --field key=value, and pipe the note body in with --stdin.
+                `),
+		Example: heredoc.Doc(`
+                        an capture --template project-release
+                        go test ./... 2>&1 | an capture --non-interactive -t zet -T "ci-run" --tag ci --stdin
+                        an capture --non-interactive -t project -T apollo --field owner=sam --field status=building
                 `),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCapture(s, templateName, title, viewName, skipPreview, dryRun)
+			return runCapture(cmd, s, opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&templateName, "template", "t", "", "Template to capture with")
-	cmd.Flags().StringVarP(&title, "title", "T", "", "Title to assign to the captured note")
-	cmd.Flags().BoolVar(&skipPreview, "no-preview", false, "Skip showing the template preview")
-	cmd.Flags().StringVar(&viewName, "view", "", "Preselect the view metadata value without prompting")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview metadata without creating a note")
+	cmd.Flags().StringVarP(&opts.templateName, "template", "t", "", "Template to capture with")
+	cmd.Flags().StringVarP(&opts.title, "title", "T", "", "Title to assign to the captured note")
+	cmd.Flags().BoolVar(&opts.skipPreview, "no-preview", false, "Skip showing the template preview")
+	cmd.Flags().StringVar(&opts.viewName, "view", "", "Preselect the view metadata value without prompting")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview metadata without creating a note")
+	cmd.Flags().BoolVar(&opts.nonInteractive, "non-interactive", false, "Never prompt; take every value from flags and create the note without opening an editor")
+	cmd.Flags().StringArrayVar(&opts.fields, "field", nil, "Set a template field as key=value (repeatable)")
+	cmd.Flags().StringSliceVar(&opts.tags, "tag", nil, "Tags to add to the note")
+	cmd.Flags().StringSliceVar(&opts.links, "link", nil, "Links to add to the note")
+	cmd.Flags().StringVar(&opts.upstream, "upstream", "", "Upstream note to link")
+	cmd.Flags().BoolVar(&opts.readStdin, "stdin", false, "Read the note body from stdin, fencing it in a code block when it is not Markdown")
+	cmd.Flags().StringVar(&opts.fence, "fence", "", "Wrap the stdin body in a fenced code block with the given language")
 
 	return cmd
 }
 
-func runCapture(s *state.State, templateName, title, viewName string, skipPreview, dryRun bool) error {
+func runCapture(cmd *cobra.Command, s *state.State, opts captureOptions) error {
 	if s == nil || s.Templater == nil {
 		return errors.New("capture requires an initialised workspace and templater")
 	}
 
+	fieldValues, err := parseFieldFlags(opts.fields)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	if opts.readStdin {
+		if !opts.nonInteractive {
+			return errors.New("--stdin requires --non-interactive because prompts also read from stdin")
+		}
+		content, err = arg.HandleStdinContent(opts.fence)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.nonInteractive {
+		return runNonInteractiveCapture(cmd, s, opts, fieldValues, content)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
-	templateChoice, err := selectTemplate(reader, s.Templater, templateName)
+	templateChoice, err := selectTemplate(reader, s.Templater, opts.templateName)
 	if err != nil {
 		return err
 	}
 
-	if !skipPreview {
+	if !opts.skipPreview {
 		if err := showPreview(s.Templater, templateChoice); err != nil {
 			return err
 		}
 	}
 
-	noteTitle, err := ensureTitle(reader, title)
+	noteTitle, err := ensureTitle(reader, opts.title)
 	if err != nil {
 		return err
 	}
 
-	tags, err := promptList(reader, "Tags (space separated, optional)")
-	if err != nil {
-		return err
+	tags := opts.tags
+	if len(tags) == 0 {
+		tags, err = promptList(reader, "Tags (space separated, optional)")
+		if err != nil {
+			return err
+		}
 	}
 
-	links, err := promptList(reader, "Links (space separated, optional)")
-	if err != nil {
-		return err
+	links := opts.links
+	if len(links) == 0 {
+		links, err = promptList(reader, "Links (space separated, optional)")
+		if err != nil {
+			return err
+		}
 	}
 
-	upstream, err := promptValue(reader, "Upstream note (optional)")
-	if err != nil {
-		return err
+	upstream := opts.upstream
+	if upstream == "" {
+		upstream, err = promptValue(reader, "Upstream note (optional)")
+		if err != nil {
+			return err
+		}
 	}
 
-	viewSelection, err := resolveView(reader, s, viewName)
+	viewSelection, err := resolveView(reader, s, opts.viewName)
 	if err != nil {
 		return err
 	}
 
-	ruleTags, ruleMetadata, ruleFields, err := resolveCaptureMetadata(s, templateChoice, upstream)
+	rules, err := resolveCaptureRules(s, newCaptureContext(templateChoice, upstream, noteTitle, content))
 	if err != nil {
 		return err
 	}
 
-	metadata, err := note.CollectTemplateMetadata(s.Templater, templateChoice, ruleFields)
+	metadata, err := note.CollectTemplateMetadata(s.Templater, templateChoice, mergeMetadata(rules.Fields, fieldValues))
 	if err != nil {
 		return err
 	}
@@ -112,116 +169,131 @@ func runCapture(s *state.State, templateName, title, viewName string, skipPrevie
 		metadata["view"] = viewSelection
 	}
 
-	tags = mergeTagSets(tags, ruleTags)
-	metadata = mergeMetadata(metadata, ruleMetadata)
+	tags = mergeTagSets(tags, rules.Tags)
+	metadata = mergeMetadata(metadata, rules.FrontMatter)
 
-	if err := maybePreviewCaptureMetadata(reader, tags, metadata, dryRun); err != nil {
+	if err := maybePreviewCaptureMetadata(reader, tags, metadata, opts.dryRun); err != nil {
 		return err
 	}
 
-	if dryRun {
+	if opts.dryRun {
 		return nil
 	}
 
-	vaultDir := viper.GetString("vaultdir")
-	subDir := viper.GetString("subdir")
-
-	captureNote := note.NewZettelkastenNote(vaultDir, subDir, noteTitle, tags, links, upstream)
+	subDir, err := captureSubdir(s, rules.Subdir, viper.GetString("subdir"), true)
+	if err != nil {
+		return err
+	}
 
+	captureNote := newCaptureNote(subDir, noteTitle, tags, links, upstream, rules)
 	if err := captureNote.HandleConflicts(); err != nil {
 		return err
 	}
 
-	note.StaticHandleNoteLaunch(captureNote, s.Templater, templateChoice, "", metadata)
-	return nil
+	note.StaticHandleNoteLaunch(captureNote, s.Templater, templateChoice, content, metadata)
+	return applyCapturePin(s, captureNote, rules.Pin)
 }
 
-func resolveCaptureMetadata(s *state.State, templateName, upstream string) ([]string, map[string]any, map[string]any, error) {
-	if s == nil || s.Workspace == nil {
-		return nil, nil, nil, nil
+// runNonInteractiveCapture creates a note purely from flags. Required
+// template fields without a default must be supplied with --field, and the
+// note is written without opening an editor.
+func runNonInteractiveCapture(
+	cmd *cobra.Command,
+	s *state.State,
+	opts captureOptions,
+	fieldValues map[string]any,
+	content string,
+) error {
+	var err error
+	if opts.templateName == "" {
+		return errors.New("--template is required with --non-interactive")
+	}
+	if _, err := s.Templater.Manifest(opts.templateName); err != nil {
+		return fmt.Errorf("unknown template %q: %w", opts.templateName, err)
+	}
+	title := strings.TrimSpace(opts.title)
+	if title == "" {
+		return errors.New("--title is required with --non-interactive")
+	}
+
+	var viewSelection string
+	if opts.viewName != "" {
+		viewSelection, err = resolveView(nil, s, opts.viewName)
+		if err != nil {
+			return err
+		}
 	}
 
-	var (
-		overlayTags    []string
-		frontMatter    map[string]any
-		fields         map[string]any
-		clipboardValue string
-		clipboardRead  bool
-	)
-
-	for _, rule := range s.Workspace.Capture.Rules {
-		if !matchesTemplate(rule, templateName) {
-			continue
-		}
-		if !matchesUpstream(rule, upstream) {
-			continue
-		}
-		if rule.Action.Clipboard {
-			if !clipboardRead {
-				value, err := readClipboard()
-				if err != nil {
-					return nil, nil, nil, fmt.Errorf("read clipboard: %w", err)
-				}
-				clipboardValue = value
-				clipboardRead = true
-			}
-			if strings.TrimSpace(clipboardValue) == "" {
-				continue
-			}
-		}
+	rules, err := resolveCaptureRules(s, newCaptureContext(opts.templateName, opts.upstream, title, content))
+	if err != nil {
+		return err
+	}
 
-		for _, tag := range rule.Action.Tags {
-			tag = strings.TrimSpace(tag)
-			if tag == "" {
-				continue
-			}
-			overlayTags = append(overlayTags, tag)
-		}
+	metadata, err := note.CollectTemplateMetadataNonInteractive(s.Templater, opts.templateName, mergeMetadata(rules.Fields, fieldValues))
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	if viewSelection != "" {
+		metadata["view"] = viewSelection
+	}
 
-		if len(rule.Action.FrontMatter) > 0 {
-			if frontMatter == nil {
-				frontMatter = make(map[string]any)
-			}
-			for key, value := range rule.Action.FrontMatter {
-				frontMatter[key] = value
-			}
-		}
+	tags := mergeTagSets(dedupePreserveOrder(opts.tags), rules.Tags)
+	metadata = mergeMetadata(metadata, rules.FrontMatter)
 
-		if len(rule.Action.Fields) > 0 {
-			if fields == nil {
-				fields = make(map[string]any)
-			}
-			for key, value := range rule.Action.Fields {
-				fields[key] = value
-			}
-		}
+	if opts.dryRun {
+		printCaptureMetadataPreview(tags, metadata)
+		return nil
 	}
 
-	overlayTags = dedupePreserveOrder(overlayTags)
-
-	if len(frontMatter) == 0 {
-		frontMatter = nil
+	subDir, err := captureSubdir(s, rules.Subdir, viper.GetString("subdir"), false)
+	if err != nil {
+		return err
 	}
 
-	if len(fields) == 0 {
-		fields = nil
+	captureNote := newCaptureNote(subDir, title, tags, opts.links, opts.upstream, rules)
+	if err := captureNote.HandleConflicts(); err != nil {
+		return err
+	}
+	if _, err := captureNote.Create(opts.templateName, s.Templater, content, metadata); err != nil {
+		return fmt.Errorf("create note: %w", err)
+	}
+	if err := applyCapturePin(s, captureNote, rules.Pin); err != nil {
+		return err
 	}
 
-	return overlayTags, frontMatter, fields, nil
+	fmt.Fprintln(cmd.OutOrStdout(), captureNote.GetFilepath())
+	return nil
 }
 
-func matchesTemplate(rule config.CaptureRule, template string) bool {
-	if rule.Match.Template == "" {
-		return true
+// parseFieldFlags turns repeated --field key=value flags into template
+// prefills. Repeating a key collects its values into a list.
+func parseFieldFlags(values []string) (map[string]any, error) {
+	if len(values) == 0 {
+		return nil, nil
 	}
-	return rule.Match.Template == template
-}
 
-func matchesUpstream(rule config.CaptureRule, upstream string) bool {
-	if rule.Match.UpstreamPrefix == "" {
-		return true
+	fields := make(map[string]any, len(values))
+	for _, raw := range values {
+		key, value, ok := strings.Cut(raw, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --field %q: expected key=value", raw)
+		}
+		value = strings.TrimSpace(value)
+
+		switch existing := fields[key].(type) {
+		case nil:
+			fields[key] = value
+		case string:
+			fields[key] = []string{existing, value}
+		case []string:
+			fields[key] = append(existing, value)
+		}
 	}
-	return strings.HasPrefix(upstream, rule.Match.UpstreamPrefix)
+	return fields, nil
 }
 
 func dedupePreserveOrder(values []string) []string {
@@ -3,12 +3,13 @@ package capture
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/state"
 )
 
-func TestResolveCaptureMetadataOverlappingRules(t *testing.T) {
+func TestResolveCaptureRulesOverlappingRules(t *testing.T) {
 	original := readClipboard
 	t.Cleanup(func() {
 		readClipboard = original
@@ -58,10 +59,11 @@ func TestResolveCaptureMetadataOverlappingRules(t *testing.T) {
 		},
 	}
 
-	tags, metadata, fields, err := resolveCaptureMetadata(s, "daily", "obsidian://note")
+	rules, err := resolveCaptureRules(s, captureContext{Template: "daily", Upstream: "obsidian://note"})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
+	tags, metadata, fields := rules.Tags, rules.FrontMatter, rules.Fields
 
 	wantTags := []string{"global", "link", "daily", "sync", "clip"}
 	if !reflect.DeepEqual(tags, wantTags) {
@@ -87,13 +89,14 @@ func TestResolveCaptureMetadataOverlappingRules(t *testing.T) {
 	}
 }
 
-func TestResolveCaptureMetadataNoRules(t *testing.T) {
+func TestResolveCaptureRulesNoRules(t *testing.T) {
 	s := &state.State{Workspace: &config.Workspace{}}
 
-	tags, metadata, fields, err := resolveCaptureMetadata(s, "", "")
+	rules, err := resolveCaptureRules(s, captureContext{})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
+	tags, metadata, fields := rules.Tags, rules.FrontMatter, rules.Fields
 	if tags != nil {
 		t.Fatalf("expected nil tags, got %v", tags)
 	}
@@ -115,3 +118,118 @@ func TestMergeTagSetsDeduplicatesAutomation(t *testing.T) {
 		t.Fatalf("mergeTagSets dedupe failed, got %v want %v", got, want)
 	}
 }
+
+func TestResolveCaptureRulesContextMatchers(t *testing.T) {
+	originalClipboard, originalRepo := readClipboard, gitRepoRoot
+	t.Cleanup(func() {
+		readClipboard, gitRepoRoot = originalClipboard, originalRepo
+	})
+	readClipboard = func() (string, error) { return "https://example.com/post", nil }
+	repoLookups := 0
+	gitRepoRoot = func(string) (string, error) {
+		repoLookups++
+		return "/src/an", nil
+	}
+
+	friday := time.Date(2024, time.March, 1, 9, 30, 0, 0, time.Local)
+	ctx := captureContext{
+		Title:   "Bug: crash on save",
+		Content: "panic: runtime error",
+		Cwd:     "/home/sam/src/an",
+		Now:     friday,
+	}
+
+	cases := []struct {
+		name  string
+		match config.CaptureMatcher
+		want  bool
+	}{
+		{"title regex", config.CaptureMatcher{Title: `^Bug:`}, true},
+		{"title mismatch", config.CaptureMatcher{Title: `^Idea:`}, false},
+		{"content regex", config.CaptureMatcher{Content: `panic:`}, true},
+		{"clipboard url", config.CaptureMatcher{Clipboard: "url"}, true},
+		{"clipboard mime", config.CaptureMatcher{Clipboard: "text/*"}, true},
+		{"clipboard text", config.CaptureMatcher{Clipboard: "text"}, false},
+		{"cwd base", config.CaptureMatcher{Cwd: "an"}, true},
+		{"cwd path", config.CaptureMatcher{Cwd: "/home/*/src/an"}, true},
+		{"cwd mismatch", config.CaptureMatcher{Cwd: "notes"}, false},
+		{"repo", config.CaptureMatcher{Repo: "an"}, true},
+		{"repo mismatch", config.CaptureMatcher{Repo: "other"}, false},
+		{"time range", config.CaptureMatcher{Time: "09:00-17:00"}, true},
+		{"time outside", config.CaptureMatcher{Time: "18:00-23:00"}, false},
+		{"time wraps midnight", config.CaptureMatcher{Time: "22:00-10:00"}, true},
+		{"weekday", config.CaptureMatcher{Weekdays: []string{"mon", "Friday"}}, true},
+		{"weekday mismatch", config.CaptureMatcher{Weekdays: []string{"sat", "sun"}}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &state.State{Workspace: &config.Workspace{Capture: config.CaptureConfig{
+				Rules: []config.CaptureRule{{Match: tc.match, Action: config.CaptureAction{Tags: []string{"hit"}}}},
+			}}}
+			rules, err := resolveCaptureRules(s, ctx)
+			if err != nil {
+				t.Fatalf("resolveCaptureRules returned error: %v", err)
+			}
+			if got := len(rules.Tags) == 1; got != tc.want {
+				t.Fatalf("expected match=%v, got tags %v", tc.want, rules.Tags)
+			}
+		})
+	}
+
+	if repoLookups != 2 {
+		t.Fatalf("expected repo to be looked up once per resolution, got %d lookups", repoLookups)
+	}
+}
+
+func TestResolveCaptureRulesActions(t *testing.T) {
+	originalRepo := gitRepoRoot
+	t.Cleanup(func() { gitRepoRoot = originalRepo })
+	gitRepoRoot = func(string) (string, error) { return "/src/an", nil }
+
+	hook := &config.CommandTemplate{Exec: "notify-send", Args: []string{"{filename}"}}
+	s := &state.State{Workspace: &config.Workspace{Capture: config.CaptureConfig{
+		Rules: []config.CaptureRule{
+			{Action: config.CaptureAction{Subdir: "inbox", Pin: "default"}},
+			{
+				Match: config.CaptureMatcher{Repo: "an"},
+				Action: config.CaptureAction{
+					Subdir:   "projects",
+					Filename: `{{.Repo}}-{{.Date}}-{{.Title}}`,
+					Pin:      "task:bugs",
+					Hook:     hook,
+				},
+			},
+		},
+	}}}
+
+	ctx := captureContext{Title: "crash", Cwd: "/src/an", Now: time.Date(2024, time.March, 1, 9, 0, 0, 0, time.Local)}
+	rules, err := resolveCaptureRules(s, ctx)
+	if err != nil {
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
+	}
+	if rules.Subdir != "projects" || rules.Pin != "task:bugs" {
+		t.Fatalf("expected last matching rule to win, got subdir %q pin %q", rules.Subdir, rules.Pin)
+	}
+	if rules.Filename != "an-2024-03-01-crash" {
+		t.Fatalf("unexpected filename %q", rules.Filename)
+	}
+	if !reflect.DeepEqual(rules.Hooks, []config.CommandTemplate{*hook}) {
+		t.Fatalf("unexpected hooks %#v", rules.Hooks)
+	}
+}
+
+func TestResolveCaptureRulesInvalidMatchers(t *testing.T) {
+	for _, match := range []config.CaptureMatcher{
+		{Title: "("},
+		{Time: "9am-5pm"},
+		{Weekdays: []string{"someday"}},
+	} {
+		s := &state.State{Workspace: &config.Workspace{Capture: config.CaptureConfig{
+			Rules: []config.CaptureRule{{Match: match}},
+		}}}
+		if _, err := resolveCaptureRules(s, captureContext{Now: time.Now()}); err == nil {
+			t.Fatalf("expected error for matcher %#v", match)
+		}
+	}
+}
@@ -1,31 +1,39 @@
 package capture
 
 import (
+	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/spf13/viper"
+
 	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/templater"
+	"github.com/Paintersrp/an/pkg/shared/arg"
 )
 
-func TestResolveCaptureMetadataNilWorkspace(t *testing.T) {
-	tags, metadata, fields, err := resolveCaptureMetadata(nil, "daily", "")
+func TestResolveCaptureRulesNilWorkspace(t *testing.T) {
+	rules, err := resolveCaptureRules(nil, captureContext{Template: "daily"})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
-	if tags != nil {
-		t.Fatalf("expected nil tags, got %v", tags)
+	if rules.Tags != nil {
+		t.Fatalf("expected nil tags, got %v", rules.Tags)
 	}
-	if metadata != nil {
-		t.Fatalf("expected nil metadata, got %v", metadata)
+	if rules.FrontMatter != nil {
+		t.Fatalf("expected nil metadata, got %v", rules.FrontMatter)
 	}
-	if fields != nil {
-		t.Fatalf("expected nil fields, got %v", fields)
+	if rules.Fields != nil {
+		t.Fatalf("expected nil fields, got %v", rules.Fields)
 	}
 }
 
-func TestResolveCaptureMetadataMergesRules(t *testing.T) {
+func TestResolveCaptureRulesMergesRules(t *testing.T) {
 	s := &state.State{
 		Workspace: &config.Workspace{
 			Capture: config.CaptureConfig{
@@ -51,10 +59,11 @@ func TestResolveCaptureMetadataMergesRules(t *testing.T) {
 		},
 	}
 
-	tags, metadata, fields, err := resolveCaptureMetadata(s, "daily", "obsidian://note")
+	rules, err := resolveCaptureRules(s, captureContext{Template: "daily", Upstream: "obsidian://note"})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
+	tags, metadata, fields := rules.Tags, rules.FrontMatter, rules.Fields
 
 	wantTags := []string{"foo", "bar"}
 	if !reflect.DeepEqual(tags, wantTags) {
@@ -77,7 +86,7 @@ func TestResolveCaptureMetadataMergesRules(t *testing.T) {
 	}
 }
 
-func TestResolveCaptureMetadataClipboard(t *testing.T) {
+func TestResolveCaptureRulesClipboard(t *testing.T) {
 	original := readClipboard
 	t.Cleanup(func() {
 		readClipboard = original
@@ -102,31 +111,170 @@ func TestResolveCaptureMetadataClipboard(t *testing.T) {
 		return "", nil
 	}
 
-	tags, _, _, err := resolveCaptureMetadata(s, "", "")
+	rules, err := resolveCaptureRules(s, captureContext{})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
-	if tags != nil {
-		t.Fatalf("expected no tags when clipboard empty, got %v", tags)
+	if rules.Tags != nil {
+		t.Fatalf("expected no tags when clipboard empty, got %v", rules.Tags)
 	}
 
 	readClipboard = func() (string, error) {
 		return "hello", nil
 	}
 
-	tags, _, _, err = resolveCaptureMetadata(s, "", "")
+	rules, err = resolveCaptureRules(s, captureContext{})
 	if err != nil {
-		t.Fatalf("resolveCaptureMetadata returned error: %v", err)
+		t.Fatalf("resolveCaptureRules returned error: %v", err)
 	}
-	if !reflect.DeepEqual(tags, []string{"clip"}) {
-		t.Fatalf("expected clipboard tags, got %v", tags)
+	if !reflect.DeepEqual(rules.Tags, []string{"clip"}) {
+		t.Fatalf("expected clipboard tags, got %v", rules.Tags)
 	}
 
 	readClipboard = func() (string, error) {
 		return "", errors.New("boom")
 	}
 
-	if _, _, _, err := resolveCaptureMetadata(s, "", ""); err == nil {
+	if _, err := resolveCaptureRules(s, captureContext{}); err == nil {
 		t.Fatalf("expected error when clipboard read fails")
 	}
 }
+
+func TestParseFieldFlags(t *testing.T) {
+	fields, err := parseFieldFlags([]string{"owner=sam", "stakeholders=ana", "stakeholders = li", "note=a=b"})
+	if err != nil {
+		t.Fatalf("parseFieldFlags returned error: %v", err)
+	}
+	want := map[string]any{
+		"owner":        "sam",
+		"stakeholders": []string{"ana", "li"},
+		"note":         "a=b",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("expected %#v, got %#v", want, fields)
+	}
+
+	if _, err := parseFieldFlags([]string{"=x"}); err == nil {
+		t.Fatalf("expected error for missing key")
+	}
+	if _, err := parseFieldFlags([]string{"owner"}); err == nil {
+		t.Fatalf("expected error for missing value separator")
+	}
+}
+
+func TestNonInteractiveCaptureFromStdin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	vaultDir := t.TempDir()
+	viper.Set("vaultdir", vaultDir)
+	viper.Set("subdir", "atoms")
+
+	ws := &config.Workspace{VaultDir: vaultDir}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	s := &state.State{Workspace: ws, Templater: tmpl, Vault: vaultDir}
+
+	prevStdin := arg.Stdin
+	arg.Stdin = strings.NewReader("2024-03-01 10:00:01 ERROR build failed\n")
+	t.Cleanup(func() { arg.Stdin = prevStdin })
+
+	cmd := NewCmdCapture(s)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{
+		"--non-interactive", "--stdin",
+		"-t", "project", "-T", "apollo",
+		"--field", "owner=sam", "--field", "stakeholders=ana", "--tag", "ci",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+
+	path := filepath.Join(vaultDir, "atoms", "apollo.md")
+	if strings.TrimSpace(out.String()) != path {
+		t.Fatalf("expected created path in output, got %q", out.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected note to be created: %v", err)
+	}
+	content := string(data)
+	for _, want := range []string{"owner: sam", "- ci", "```\n2024-03-01 10:00:01 ERROR build failed\n```", "- ana"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in note:\n%s", want, content)
+		}
+	}
+
+	cmd = NewCmdCapture(s)
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--non-interactive", "-t", "project", "-T", "hermes"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected missing required field to fail")
+	}
+}
+
+func TestNonInteractiveCaptureAppliesRuleActions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	vaultDir := t.TempDir()
+	viper.Set("vaultdir", vaultDir)
+	viper.Set("subdir", "atoms")
+
+	marker := filepath.Join(t.TempDir(), "hook.txt")
+	ws := &config.Workspace{
+		VaultDir: vaultDir,
+		SubDirs:  []string{"atoms", "bugs"},
+		Capture: config.CaptureConfig{Rules: []config.CaptureRule{{
+			Match: config.CaptureMatcher{Title: `(?i)^bug`, Content: `panic`},
+			Action: config.CaptureAction{
+				Subdir:   "bugs",
+				Filename: "{{.Title}}-report",
+				Pin:      "bugs",
+				Hook:     &config.CommandTemplate{Exec: "sh", Args: []string{"-c", "echo {filename} > " + marker}},
+			},
+		}}},
+	}
+	cfg := &config.Config{Workspaces: map[string]*config.Workspace{"default": ws}, CurrentWorkspace: "default"}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	s := &state.State{Config: cfg, Workspace: ws, Templater: tmpl, Vault: vaultDir}
+
+	prevStdin := arg.Stdin
+	arg.Stdin = strings.NewReader("panic: nil map\n")
+	t.Cleanup(func() { arg.Stdin = prevStdin })
+
+	cmd := NewCmdCapture(s)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--non-interactive", "--stdin", "-t", "zet", "-T", "bug-save"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("capture returned error: %v", err)
+	}
+
+	path := filepath.Join(vaultDir, "bugs", "bug-save-report.md")
+	if strings.TrimSpace(out.String()) != path {
+		t.Fatalf("expected note at %s, got %q", path, out.String())
+	}
+	hookOutput, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("expected rule hook to run: %v", err)
+	}
+	if strings.TrimSpace(string(hookOutput)) != "bug-save-report.md" {
+		t.Fatalf("unexpected hook output %q", hookOutput)
+	}
+	if ws.NamedPins["bugs"] != path {
+		t.Fatalf("expected note pinned as bugs, got %v", ws.NamedPins)
+	}
+}
@@ -0,0 +1,459 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/note"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+var (
+	now = time.Now
+
+	gitRepoRoot = func(dir string) (string, error) {
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+)
+
+// captureContext describes the capture that rules are matched against.
+type captureContext struct {
+	Template string
+	Upstream string
+	Title    string
+	Content  string
+	Cwd      string
+	Now      time.Time
+}
+
+func newCaptureContext(templateName, upstream, title, content string) captureContext {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+	return captureContext{
+		Template: templateName,
+		Upstream: upstream,
+		Title:    title,
+		Content:  content,
+		Cwd:      cwd,
+		Now:      now(),
+	}
+}
+
+// captureResolution is the combined effect of every matching capture rule.
+type captureResolution struct {
+	Tags        []string
+	FrontMatter map[string]any
+	Fields      map[string]any
+	Subdir      string
+	Filename    string
+	Pin         string
+	Hooks       []config.CommandTemplate
+}
+
+// ruleMatcher matches capture rules against a capture, reading the clipboard
+// and git repository at most once and only when a rule asks for them.
+type ruleMatcher struct {
+	ctx           captureContext
+	clipboard     string
+	clipboardRead bool
+	repo          string
+	repoRead      bool
+}
+
+func resolveCaptureRules(s *state.State, ctx captureContext) (captureResolution, error) {
+	var res captureResolution
+	if s == nil || s.Workspace == nil {
+		return res, nil
+	}
+
+	matcher := &ruleMatcher{ctx: ctx}
+	var filenamePattern string
+
+	for idx, rule := range s.Workspace.Capture.Rules {
+		ok, err := matcher.matches(rule.Match)
+		if err != nil {
+			return captureResolution{}, fmt.Errorf("capture rule %d: %w", idx+1, err)
+		}
+		if !ok {
+			continue
+		}
+		if rule.Action.Clipboard {
+			value, err := matcher.clipboardValue()
+			if err != nil {
+				return captureResolution{}, err
+			}
+			if strings.TrimSpace(value) == "" {
+				continue
+			}
+		}
+
+		for _, tag := range rule.Action.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			res.Tags = append(res.Tags, tag)
+		}
+
+		if len(rule.Action.FrontMatter) > 0 {
+			if res.FrontMatter == nil {
+				res.FrontMatter = make(map[string]any)
+			}
+			for key, value := range rule.Action.FrontMatter {
+				res.FrontMatter[key] = value
+			}
+		}
+
+		if len(rule.Action.Fields) > 0 {
+			if res.Fields == nil {
+				res.Fields = make(map[string]any)
+			}
+			for key, value := range rule.Action.Fields {
+				res.Fields[key] = value
+			}
+		}
+
+		if subdir := strings.TrimSpace(rule.Action.Subdir); subdir != "" {
+			res.Subdir = subdir
+		}
+		if pattern := strings.TrimSpace(rule.Action.Filename); pattern != "" {
+			filenamePattern = pattern
+		}
+		if pin := strings.TrimSpace(rule.Action.Pin); pin != "" {
+			res.Pin = pin
+		}
+		if rule.Action.Hook != nil && strings.TrimSpace(rule.Action.Hook.Exec) != "" {
+			res.Hooks = append(res.Hooks, *rule.Action.Hook)
+		}
+	}
+
+	res.Tags = dedupePreserveOrder(res.Tags)
+
+	if filenamePattern != "" {
+		filename, err := matcher.renderFilename(filenamePattern)
+		if err != nil {
+			return captureResolution{}, err
+		}
+		res.Filename = filename
+	}
+
+	return res, nil
+}
+
+func (m *ruleMatcher) matches(match config.CaptureMatcher) (bool, error) {
+	if match.Template != "" && match.Template != m.ctx.Template {
+		return false, nil
+	}
+	if match.UpstreamPrefix != "" && !strings.HasPrefix(m.ctx.Upstream, match.UpstreamPrefix) {
+		return false, nil
+	}
+
+	if ok, err := matchRegexp("title", match.Title, m.ctx.Title); !ok || err != nil {
+		return false, err
+	}
+	if ok, err := matchRegexp("content", match.Content, m.ctx.Content); !ok || err != nil {
+		return false, err
+	}
+
+	if match.Cwd != "" && !matchCwd(match.Cwd, m.ctx.Cwd) {
+		return false, nil
+	}
+
+	if match.Repo != "" {
+		repo := m.repoName()
+		if repo == "" {
+			return false, nil
+		}
+		ok, err := filepath.Match(match.Repo, repo)
+		if err != nil {
+			return false, fmt.Errorf("invalid repo pattern %q: %w", match.Repo, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if match.Time != "" {
+		ok, err := matchTimeRange(match.Time, m.ctx.Now)
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+
+	if len(match.Weekdays) > 0 {
+		ok, err := matchWeekday(match.Weekdays, m.ctx.Now.Weekday())
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+
+	if match.Clipboard != "" {
+		value, err := m.clipboardValue()
+		if err != nil {
+			return false, err
+		}
+		ok, err := matchClipboard(match.Clipboard, value)
+		if !ok || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (m *ruleMatcher) clipboardValue() (string, error) {
+	if !m.clipboardRead {
+		value, err := readClipboard()
+		if err != nil {
+			return "", fmt.Errorf("read clipboard: %w", err)
+		}
+		m.clipboard = value
+		m.clipboardRead = true
+	}
+	return m.clipboard, nil
+}
+
+// repoName returns the base name of the git repository containing the
+// working directory, or "" outside a repository.
+func (m *ruleMatcher) repoName() string {
+	if !m.repoRead {
+		m.repoRead = true
+		if m.ctx.Cwd != "" {
+			if root, err := gitRepoRoot(m.ctx.Cwd); err == nil && root != "" {
+				m.repo = filepath.Base(root)
+			}
+		}
+	}
+	return m.repo
+}
+
+func (m *ruleMatcher) renderFilename(pattern string) (string, error) {
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid filename pattern %q: %w", pattern, err)
+	}
+
+	cwd := ""
+	if m.ctx.Cwd != "" {
+		cwd = filepath.Base(m.ctx.Cwd)
+	}
+	data := map[string]any{
+		"Title":    m.ctx.Title,
+		"Template": m.ctx.Template,
+		"Date":     m.ctx.Now.Format("2006-01-02"),
+		"Now":      m.ctx.Now,
+		"Cwd":      cwd,
+		"Repo":     m.repoName(),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render filename pattern %q: %w", pattern, err)
+	}
+	filename := strings.TrimSpace(buf.String())
+	if filename == "" {
+		return "", fmt.Errorf("filename pattern %q rendered an empty name", pattern)
+	}
+	if strings.ContainsAny(filename, `/\`) {
+		return "", fmt.Errorf("filename pattern %q rendered %q, which contains a path separator", pattern, filename)
+	}
+	return filename, nil
+}
+
+func matchRegexp(field, pattern, value string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s pattern %q: %w", field, pattern, err)
+	}
+	return re.MatchString(value), nil
+}
+
+// matchCwd matches a glob against the full working directory, or against
+// its base name when the pattern has no separator.
+func matchCwd(pattern, cwd string) bool {
+	if cwd == "" {
+		return false
+	}
+	if strings.HasPrefix(pattern, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.Join(home, pattern[2:])
+		}
+	}
+	if strings.ContainsRune(pattern, filepath.Separator) {
+		ok, _ := filepath.Match(pattern, cwd)
+		return ok
+	}
+	ok, _ := filepath.Match(pattern, filepath.Base(cwd))
+	return ok
+}
+
+// matchTimeRange reports whether now falls within a `HH:MM-HH:MM` range. A
+// range whose end is before its start wraps past midnight.
+func matchTimeRange(spec string, now time.Time) (bool, error) {
+	startRaw, endRaw, ok := strings.Cut(spec, "-")
+	if !ok {
+		return false, fmt.Errorf("invalid time range %q: expected HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(startRaw)
+	if err != nil {
+		return false, fmt.Errorf("invalid time range %q: %w", spec, err)
+	}
+	end, err := parseClock(endRaw)
+	if err != nil {
+		return false, fmt.Errorf("invalid time range %q: %w", spec, err)
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return minute >= start && minute < end, nil
+	}
+	return minute >= start || minute < end, nil
+}
+
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", strings.TrimSpace(value))
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func matchWeekday(days []string, today time.Weekday) (bool, error) {
+	for _, day := range days {
+		day = strings.ToLower(strings.TrimSpace(day))
+		weekday, ok := parseWeekday(day)
+		if !ok {
+			return false, fmt.Errorf("invalid weekday %q", day)
+		}
+		if weekday == today {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseWeekday(day string) (time.Weekday, bool) {
+	if len(day) < 3 {
+		return 0, false
+	}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if strings.HasPrefix(name, day) {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+// clipboardMIME classifies clipboard text. The clipboard library only reads
+// text, so a single absolute URL is reported as `text/uri-list` and anything
+// else as `text/plain`.
+func clipboardMIME(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if !strings.ContainsAny(value, " \t\n") {
+		if parsed, err := url.Parse(value); err == nil && parsed.Scheme != "" && (parsed.Host != "" || parsed.Opaque != "") {
+			return "text/uri-list"
+		}
+	}
+	return "text/plain"
+}
+
+func matchClipboard(pattern, value string) (bool, error) {
+	mime := clipboardMIME(value)
+	if mime == "" {
+		return false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(pattern)) {
+	case "any":
+		return true, nil
+	case "url":
+		return mime == "text/uri-list", nil
+	case "text":
+		return mime == "text/plain", nil
+	}
+	ok, err := path.Match(pattern, mime)
+	if err != nil {
+		return false, fmt.Errorf("invalid clipboard pattern %q: %w", pattern, err)
+	}
+	return ok, nil
+}
+
+// captureSubdir resolves the subdirectory a capture is written to. A rule's
+// subdirectory goes through the workspace filesystem mode; without a prompt
+// to confirm it, only configured subdirectories (or any in free mode) are
+// accepted.
+func captureSubdir(s *state.State, subdir, fallback string, interactive bool) (string, error) {
+	if subdir == "" {
+		return fallback, nil
+	}
+	if s == nil || s.Config == nil || s.Workspace == nil || s.Workspace.HasSubdir(subdir) {
+		return subdir, nil
+	}
+	if interactive {
+		s.Config.HandleSubdir(subdir)
+		return subdir, nil
+	}
+	if s.Workspace.FileSystemMode != "free" {
+		return "", fmt.Errorf("capture rule subdirectory %q does not exist; add it with `an add-subdir %s`", subdir, subdir)
+	}
+	if err := s.Config.AddSubdir(subdir); err != nil {
+		return "", err
+	}
+	return subdir, nil
+}
+
+// applyCapturePin pins the captured note as a text pin, or as a task pin
+// when the name is prefixed with `task:`.
+func applyCapturePin(s *state.State, n *note.ZettelkastenNote, pin string) error {
+	if pin == "" {
+		return nil
+	}
+	if s == nil || s.Config == nil {
+		return errors.New("capture rule pin requires a loaded config")
+	}
+	pinType := "text"
+	if name, ok := strings.CutPrefix(pin, "task:"); ok {
+		pinType, pin = "task", name
+	}
+	if err := s.Config.ChangePin(n.GetFilepath(), pinType, pin); err != nil {
+		return fmt.Errorf("pin captured note: %w", err)
+	}
+	return nil
+}
+
+// newCaptureNote builds the note for a capture, applying a rule's filename
+// pattern and extra post-create hooks.
+func newCaptureNote(subDir, title string, tags, links []string, upstream string, rules captureResolution) *note.ZettelkastenNote {
+	filename := title
+	if rules.Filename != "" {
+		filename = rules.Filename
+	}
+	n := note.NewZettelkastenNote(viper.GetString("vaultdir"), subDir, filename, tags, links, upstream)
+	n.PostCreate = rules.Hooks
+	return n
+}
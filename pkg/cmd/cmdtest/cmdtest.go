@@ -0,0 +1,85 @@
+// Package cmdtest builds throwaway vaults for command tests and runs
+// commands against them.
+package cmdtest
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+// NewState creates a vault holding notes, keyed by their slash-separated
+// path inside the vault, and returns a state for its default workspace.
+// Each configure func may adjust the workspace before it is activated. HOME
+// points at a temporary directory, so commands that save the config leave
+// the real one alone.
+func NewState(t testing.TB, notes map[string]string, configure ...func(*config.Workspace)) (*state.State, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	vaultDir := t.TempDir()
+	ws := &config.Workspace{VaultDir: vaultDir}
+	for _, fn := range configure {
+		fn(ws)
+	}
+	cfg := &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": ws},
+		CurrentWorkspace: "default",
+	}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+	s := &state.State{
+		Config:        cfg,
+		Workspace:     cfg.MustWorkspace(),
+		WorkspaceName: cfg.CurrentWorkspace,
+		Handler:       handler.NewFileHandler(vaultDir),
+		Vault:         vaultDir,
+	}
+
+	for rel, content := range notes {
+		path := filepath.Join(vaultDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write note: %v", err)
+		}
+	}
+	return s, vaultDir
+}
+
+// Run executes the command built by newCmd for s with args and returns
+// what it wrote to stdout.
+func Run(t testing.TB, s *state.State, newCmd func(*state.State) *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	cmd := newCmd(s)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetIn(strings.NewReader(""))
+	cmd.SilenceUsage = true
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// ReadFile returns the content of path, failing the test when it cannot be
+// read.
+func ReadFile(t testing.TB, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
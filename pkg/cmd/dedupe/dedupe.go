@@ -0,0 +1,154 @@
+package dedupe
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	dedupesvc "github.com/Paintersrp/an/internal/dedupe"
+	"github.com/Paintersrp/an/internal/search"
+	indexsvc "github.com/Paintersrp/an/internal/services/index"
+	"github.com/Paintersrp/an/internal/state"
+	dedupetui "github.com/Paintersrp/an/internal/tui/dedupe"
+)
+
+// skippedDirs hold notes that are already out of the way.
+var skippedDirs = []string{"archive", "trash"}
+
+func NewCmdDedupe(s *state.State) *cobra.Command {
+	opts := dedupesvc.DefaultOptions()
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "dedupe [--threshold 0.6] [--shingle 3] [--list]",
+		Short: "Find and resolve duplicate notes.",
+		Long: heredoc.Doc(`
+			Fingerprints the body of every note in the search index with MinHash
+			over shingles of consecutive words and groups notes whose estimated
+			similarity reaches --threshold into clusters. Archived and trashed
+			notes, and notes too short to compare, are skipped.
+
+			Each cluster opens in a review TUI that shows two notes side by side
+			with their differences highlighted. Press m to merge the right note
+			into the left one, the same way 'an merge' does: links to the merged
+			note are redirected and it goes to the trash, so 'an undo' reverts
+			it. Press x to mark the pair as not duplicates; the mark is kept in
+			.an/dedupe.json and the pair is not reported again. Use --list to
+			print the clusters without opening the TUI.
+
+			Example:
+			  an dedupe
+			  an dedupe --threshold 0.8 --list
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Threshold <= 0 || opts.Threshold > 1 {
+				return errors.New("--threshold must be between 0 and 1")
+			}
+			if s.Handler == nil {
+				return errors.New("dedupe is unavailable without a vault")
+			}
+
+			decisions, err := dedupesvc.LoadDecisions(s.Handler.VaultDir())
+			if err != nil {
+				return err
+			}
+			notes, err := indexedNotes(s)
+			if err != nil {
+				return err
+			}
+			opts.Distinct = decisions.Distinct
+			clusters := dedupesvc.Find(notes, opts)
+
+			out := cmd.OutOrStdout()
+			if len(clusters) == 0 {
+				fmt.Fprintln(out, "No likely duplicates found.")
+				return nil
+			}
+			if list {
+				printClusters(out, s, clusters)
+				return nil
+			}
+
+			result, err := dedupetui.Run(s, clusters, decisions)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Merged %d note(s), marked %d pair(s) as not duplicates.\n", result.Merged, result.Marked)
+			return nil
+		},
+	}
+
+	cmd.Flags().Float64Var(&opts.Threshold, "threshold", opts.Threshold, "Minimum estimated similarity, from 0 to 1")
+	cmd.Flags().IntVar(&opts.ShingleSize, "shingle", opts.ShingleSize, "Number of consecutive words per shingle")
+	cmd.Flags().BoolVar(&list, "list", false, "Print the clusters instead of opening the review TUI")
+	return cmd
+}
+
+func printClusters(out io.Writer, s *state.State, clusters []dedupesvc.Cluster) {
+	for i, cluster := range clusters {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Cluster %d · %d notes · up to %.0f%% similar\n", i+1, len(cluster.Notes), cluster.Similarity*100)
+		for _, pair := range cluster.Pairs {
+			fmt.Fprintf(out, "  %3.0f%%  %s  %s\n", pair.Similarity*100, rel(s, pair.A), rel(s, pair.B))
+		}
+	}
+}
+
+// indexedNotes returns the bodies of the indexed notes outside the archive
+// and trash.
+func indexedNotes(s *state.State) ([]dedupesvc.Note, error) {
+	idx, err := loadIndex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []dedupesvc.Note
+	for _, doc := range idx.Documents() {
+		if skipped(rel(s, doc.Path)) {
+			continue
+		}
+		body, ok := idx.Body(doc.Path)
+		if !ok {
+			continue
+		}
+		notes = append(notes, dedupesvc.Note{Path: doc.Path, Body: body})
+	}
+	return notes, nil
+}
+
+// loadIndex returns the shared search index when it is ready and builds a
+// fresh one otherwise.
+func loadIndex(s *state.State) (*search.Index, error) {
+	cfg := search.Config{EnableBody: true}
+	if s.Workspace != nil {
+		cfg.IgnoredFolders = append([]string(nil), s.Workspace.Search.IgnoredFolders...)
+	}
+	return indexsvc.Snapshot(s.Index, s.Handler.VaultDir(), cfg, func() ([]string, error) {
+		return s.Handler.WalkFiles(skippedDirs, nil, "")
+	})
+}
+
+func skipped(rel string) bool {
+	first, _, _ := strings.Cut(rel, "/")
+	for _, dir := range skippedDirs {
+		if first == dir {
+			return true
+		}
+	}
+	return first == ".an"
+}
+
+func rel(s *state.State, path string) string {
+	if r, err := filepath.Rel(s.Handler.VaultDir(), path); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return path
+}
@@ -0,0 +1,64 @@
+package dedupe
+
+import (
+	"strings"
+	"testing"
+
+	dedupesvc "github.com/Paintersrp/an/internal/dedupe"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+const body = "Spaced repetition works because each review interrupts forgetting " +
+	"just before the memory fades, and every successful recall makes the next interval longer.\n"
+
+var dedupeNotes = map[string]string{
+	"atoms/memory.md":     "---\ntitle: memory\n---\n\n" + body,
+	"inbox/spaced.md":     "---\ntitle: spaced\ntags: [review]\n---\n\n" + body + "Keep sessions short.\n",
+	"trash/old.md":        body,
+	"atoms/unrelated.md":  "The build pipeline caches modules between runs, so a clean checkout only downloads dependencies once.\n",
+	"atoms/template.md":   "## Relations\n",
+	"atoms/template-2.md": "## Relations\n",
+}
+
+func TestDedupeListsClusters(t *testing.T) {
+	s, _ := cmdtest.NewState(t, dedupeNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdDedupe, "--list")
+	if err != nil {
+		t.Fatalf("dedupe returned error: %v", err)
+	}
+	if !strings.HasPrefix(out, "Cluster 1 · 2 notes · up to ") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if !strings.Contains(out, "atoms/memory.md  inbox/spaced.md") {
+		t.Fatalf("expected the duplicate pair, got:\n%s", out)
+	}
+	if strings.Contains(out, "trash/") || strings.Contains(out, "template") || strings.Contains(out, "unrelated") {
+		t.Fatalf("expected trashed, short, and unrelated notes to be skipped, got:\n%s", out)
+	}
+}
+
+func TestDedupeSkipsRememberedPairs(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, dedupeNotes)
+
+	decisions, err := dedupesvc.LoadDecisions(vaultDir)
+	if err != nil {
+		t.Fatalf("LoadDecisions returned error: %v", err)
+	}
+	decisions.MarkDistinct("atoms/memory.md", "inbox/spaced.md")
+	if err := decisions.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	out, err := cmdtest.Run(t, s, NewCmdDedupe, "--list")
+	if err != nil {
+		t.Fatalf("dedupe returned error: %v", err)
+	}
+	if out != "No likely duplicates found.\n" {
+		t.Fatalf("expected the marked pair to stay hidden, got:\n%s", out)
+	}
+
+	if _, err := cmdtest.Run(t, s, NewCmdDedupe, "--threshold", "1.5"); err == nil {
+		t.Fatalf("expected an out of range threshold to fail")
+	}
+}
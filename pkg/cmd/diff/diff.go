@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/textdiff"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdDiff(s *state.State) *cobra.Command {
+	var rev int
+
+	cmd := &cobra.Command{
+		Use:   "diff <note> [--rev N]",
+		Short: "Show how a note changed since a saved version.",
+		Long: heredoc.Doc(`
+			Prints a unified diff from a version of the note kept in the
+			snapshot store (.an/history) to its current content. Without --rev
+			the newest saved version is used; 'an history <note>' lists the
+			revisions. A note that no longer exists is compared as empty.
+
+			Example:
+			  an diff atoms/zettel.md
+			  an diff atoms/zettel.md --rev 3
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := s.Handler.History()
+			if store == nil {
+				return errors.New("note history is disabled; set history.enabled on the workspace")
+			}
+			path, err := cmdpkg.ResolveVaultPath(cmd, s, snapshot.WithExt(args[0]))
+			if err != nil {
+				return err
+			}
+
+			if !cmd.Flags().Changed("rev") {
+				versions, err := store.Versions(path)
+				if err != nil {
+					return err
+				}
+				if len(versions) == 0 {
+					return fmt.Errorf("no saved versions of %s", filepath.Base(path))
+				}
+				rev = versions[len(versions)-1].Rev
+			}
+
+			old, _, err := store.Read(path, rev)
+			if err != nil {
+				return err
+			}
+			current, err := os.ReadFile(path)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+
+			name := rel(s, path)
+			out := cmd.OutOrStdout()
+			diff := textdiff.Unified(fmt.Sprintf("a/%s (rev %d)", name, rev), "b/"+name, string(old), string(current), 3)
+			if diff == "" {
+				fmt.Fprintf(out, "%s matches rev %d.\n", name, rev)
+				return nil
+			}
+			fmt.Fprint(out, diff)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&rev, "rev", 0, "Revision to compare against (default: the newest)")
+	return cmd
+}
+
+func rel(s *state.State, path string) string {
+	if r, err := filepath.Rel(s.Handler.VaultDir(), path); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return path
+}
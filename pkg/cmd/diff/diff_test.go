@@ -0,0 +1,42 @@
+package diff
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+func TestDiffAgainstSavedVersions(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, nil)
+
+	if _, err := cmdtest.Run(t, s, NewCmdDiff, "note"); err == nil || !strings.Contains(err.Error(), "history is disabled") {
+		t.Fatalf("expected disabled history to be reported, got %v", err)
+	}
+
+	s.Handler.SetHistory(snapshot.Open(vaultDir, snapshot.Options{}))
+	notePath := filepath.Join(vaultDir, "note.md")
+	for _, content := range []string{"alpha\nbeta\n", "alpha\ngamma\n", "alpha\ndelta\n"} {
+		if err := s.Handler.WriteFileOp("edit", notePath, []byte(content)); err != nil {
+			t.Fatalf("WriteFileOp returned error: %v", err)
+		}
+	}
+
+	out, err := cmdtest.Run(t, s, NewCmdDiff, "note")
+	if err != nil {
+		t.Fatalf("diff returned error: %v", err)
+	}
+	if !strings.Contains(out, "--- a/note.md (rev 2)") || !strings.Contains(out, "-gamma") || !strings.Contains(out, "+delta") {
+		t.Fatalf("expected a diff from the newest version, got:\n%s", out)
+	}
+
+	out, err = cmdtest.Run(t, s, NewCmdDiff, "note.md", "--rev", "1")
+	if err != nil {
+		t.Fatalf("diff returned error: %v", err)
+	}
+	if !strings.Contains(out, "-beta") || !strings.Contains(out, "+delta") {
+		t.Fatalf("expected a diff from rev 1, got:\n%s", out)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/note"
 	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/pkg/shared/arg"
 	"github.com/Paintersrp/an/pkg/shared/flags"
 )
 
@@ -22,7 +23,7 @@ func NewCmdEcho(s *state.State) *cobra.Command {
 	var auto bool
 
 	cmd := &cobra.Command{
-		Use:     "echo [message] --name [pin-name] --template [template-name] --auto --paste",
+		Use:     "echo [message|-] --name [pin-name] --template [template-name] --auto --paste --fence [lang]",
 		Aliases: []string{"e"},
 		Short:   "Append a message to the pinned file or from the clipboard if --paste is set.",
 		Long: heredoc.Doc(`
@@ -30,6 +31,8 @@ func NewCmdEcho(s *state.State) *cobra.Command {
 			If no file is pinned, it returns an error.
 			If the --auto flag is set, an autogenerated file will be used instead of a pinned file.
 			If the --paste flag is set, the current clipboard content is used as the message.
+			Pass - as the message to read it from stdin; piped input that is not Markdown is wrapped
+			in a code block, and --fence wraps any message in a code block with the given language.
 
 			Examples:
 			  an echo "Add this to the default pinned file."
@@ -37,6 +40,8 @@ func NewCmdEcho(s *state.State) *cobra.Command {
 			  an echo "Add this to autogenerated file." --auto
 			  an echo -a --paste  // Add clipboard contents to autogenerated file
 			  an e -a -p --template feature // Add clipboard contents to autogenerated file with template
+			  go test ./... 2>&1 | an echo - // Add command output to the pinned file
+			  git diff | an echo - -a --fence diff
 		`),
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,6 +55,7 @@ func NewCmdEcho(s *state.State) *cobra.Command {
 	flags.AddPaste(cmd)
 	flags.AddTemplate(cmd, "echo")
 	flags.AddName(cmd, "Named pin to target")
+	flags.AddFence(cmd)
 
 	return cmd
 }
@@ -71,7 +77,12 @@ func run(
 		return err
 	}
 
-	message, err := prepareMessage(args, paste)
+	fence, err := flags.HandleFence(cmd)
+	if err != nil {
+		return err
+	}
+
+	message, err := prepareMessage(args, paste, fence)
 	if err != nil {
 		return err
 	}
@@ -94,7 +105,17 @@ func run(
 	return nil
 }
 
-func prepareMessage(args []string, paste bool) (string, error) {
+func prepareMessage(args []string, paste bool, fence string) (string, error) {
+	readStdin := len(args) == 1 && args[0] == arg.StdinArg
+	if paste && readStdin {
+		return "", errors.New("cannot read from both stdin and the clipboard")
+	}
+
+	if readStdin {
+		return arg.HandleStdinContent(fence)
+	}
+
+	var message string
 	if paste {
 		msg, err := clipboard.ReadAll()
 		if err != nil {
@@ -103,13 +124,18 @@ func prepareMessage(args []string, paste bool) (string, error) {
 		if msg == "" {
 			return "", errors.New("clipboard is empty")
 		}
-		return msg, nil
+		message = msg
+	} else {
+		if len(args) == 0 {
+			return "", errors.New("no message provided and --paste not set")
+		}
+		message = strings.Join(args, " ")
 	}
 
-	if len(args) == 0 {
-		return "", errors.New("no message provided and --paste not set")
+	if fence != "" {
+		message = arg.Fence(message, fence)
 	}
-	return strings.Join(args, " "), nil
+	return message, nil
 }
 
 func determineTargetPin(
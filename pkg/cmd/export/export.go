@@ -0,0 +1,27 @@
+package export
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/pkg/cmd/export/exportAnki"
+)
+
+func NewCmdExport(s *state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export vault content to other tools.",
+		Long: heredoc.Doc(`
+			The export command group writes vault content in formats other tools
+			can import.
+
+			Examples:
+			  an export anki --output cards.txt
+		`),
+	}
+
+	cmd.AddCommand(exportAnki.NewCmdExportAnki(s))
+
+	return cmd
+}
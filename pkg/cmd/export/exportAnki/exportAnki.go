@@ -0,0 +1,72 @@
+package exportAnki
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+func NewCmdExportAnki(s *state.State) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "anki",
+		Short: "Export flashcards as an Anki import file.",
+		Long: heredoc.Doc(`
+			Writes every flashcard declared in the vault to a tab-separated file
+			that Anki can import. Each card carries a stable GUID derived from its
+			question, so re-importing updates existing cards instead of creating
+			duplicates, even after the note is moved or renamed. When two notes
+			ask the same question, the later one's GUID also covers its path.
+
+			Example:
+			  an export anki --output ~/anki/vault-cards.txt
+			  an export anki > cards.txt
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s == nil || s.Config == nil {
+				return errors.New("state is not configured")
+			}
+			ws := s.Config.MustWorkspace()
+
+			cards, err := reviewsvc.CollectVaultCards(s.Vault, ws.Search.IgnoredFolders)
+			if err != nil {
+				return fmt.Errorf("collect cards: %w", err)
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" && output != "-" {
+				if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+					return err
+				}
+				file, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				w = file
+			}
+
+			if err := reviewsvc.WriteAnkiExport(w, cards); err != nil {
+				return fmt.Errorf("write anki export: %w", err)
+			}
+
+			if output != "" && output != "-" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d cards to %s\n", len(cards), output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write (defaults to stdout)")
+
+	return cmd
+}
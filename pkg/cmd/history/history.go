@@ -0,0 +1,121 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdHistory(s *state.State) *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history [note] [--limit N]",
+		Short: "List recent vault operations or the saved versions of a note.",
+		Long: heredoc.Doc(`
+			Lists the operations recorded in the vault's operation journal
+			(.an/ops.log), newest first. Operations that have been reverted with
+			'an undo' are marked as undone.
+
+			Given a note, lists the versions of it kept in the snapshot store
+			(.an/history), newest first. Snapshots are taken before an rewrites
+			or deletes a note when history.enabled is set on the workspace. Use
+			'an diff' and 'an restore' with the revision numbers shown here.
+
+			Example:
+			  an history
+			  an history --limit 50
+			  an history atoms/zettel.md
+		`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return listVersions(cmd, s, args[0], limit)
+			}
+
+			journal := s.Handler.Journal()
+			if journal == nil {
+				return errors.New("operation journal is unavailable without a vault")
+			}
+
+			entries, err := journal.Entries()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No operations recorded.")
+				return nil
+			}
+
+			undone := make(map[int]bool)
+			for _, entry := range entries {
+				if entry.Undoes != 0 {
+					undone[entry.Undoes] = true
+				}
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			shown := 0
+			for i := len(entries) - 1; i >= 0 && (limit <= 0 || shown < limit); i-- {
+				entry := entries[i]
+				status := ""
+				if undone[entry.ID] {
+					status = "(undone)"
+				}
+				fmt.Fprintf(w, "#%d\t%s\t%s\t%s\n",
+					entry.ID,
+					entry.Time.Local().Format("2006-01-02 15:04"),
+					entry.Describe(),
+					status,
+				)
+				shown++
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of operations or versions to show (0 for all)")
+	return cmd
+}
+
+func listVersions(cmd *cobra.Command, s *state.State, name string, limit int) error {
+	store := s.Handler.History()
+	if store == nil {
+		return errors.New("note history is disabled; set history.enabled on the workspace")
+	}
+	path, err := cmdpkg.ResolveVaultPath(cmd, s, snapshot.WithExt(name))
+	if err != nil {
+		return err
+	}
+
+	versions, err := store.Versions(path)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No saved versions of %s.\n", filepath.Base(path))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	shown := 0
+	for i := len(versions) - 1; i >= 0 && (limit <= 0 || shown < limit); i-- {
+		version := versions[i]
+		fmt.Fprintf(w, "rev %d\t%s\t%s\t%d bytes\n",
+			version.Rev,
+			version.Time.Local().Format("2006-01-02 15:04"),
+			version.Op,
+			version.Size,
+		)
+		shown++
+	}
+	return w.Flush()
+}
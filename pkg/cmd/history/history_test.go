@@ -0,0 +1,53 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+func TestHistoryKeepsContentReplacedByUndo(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, nil)
+	h := s.Handler
+	h.SetHistory(snapshot.Open(vaultDir, snapshot.Options{}))
+
+	notePath := filepath.Join(vaultDir, "atoms", "note.md")
+	for _, content := range []string{"draft\n", "edited\n"} {
+		if err := h.WriteFileOp("edit", notePath, []byte(content)); err != nil {
+			t.Fatalf("WriteFileOp returned error: %v", err)
+		}
+	}
+	if err := h.Trash(notePath); err != nil {
+		t.Fatalf("Trash returned error: %v", err)
+	}
+	if _, err := h.Journal().Undo(2); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(notePath); string(data) != "draft\n" {
+		t.Fatalf("expected the edit undone, got %q", data)
+	}
+
+	out, err := cmdtest.Run(t, s, NewCmdHistory, "atoms/note")
+	if err != nil {
+		t.Fatalf("history returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "rev 2") || !strings.Contains(lines[0], "undo") {
+		t.Fatalf("expected the undone edit listed as rev 2, got:\n%s", out)
+	}
+
+	content, _, err := h.History().Read(notePath, 2)
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if string(content) != "edited\n" {
+		t.Fatalf("expected the undone content kept, got %q", content)
+	}
+	if versions, _ := h.History().Versions(filepath.Join(vaultDir, "trash", "atoms", "note.md")); len(versions) != 0 {
+		t.Fatalf("expected no history left under the trash, got %+v", versions)
+	}
+}
@@ -0,0 +1,39 @@
+package inbox
+
+import (
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/tui/inbox"
+)
+
+func NewCmdInbox(s *state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "inbox",
+		Aliases: []string{"in"},
+		Short:   "Triage unsorted notes one at a time",
+		Long: heredoc.Doc(`
+            The 'inbox' command walks through unsorted notes one at a time, oldest
+            first, so quick captures can be filed before they pile up.
+
+            A note is in the inbox when it sits directly inside one of the inbox
+            folders (inbox/ and echoes/ by default) or its filename matches one of
+            the inbox patterns (scratch-*.md by default). Both can be changed with
+            the 'inbox' section of the workspace config.
+
+            Each note can be moved to a subdirectory (m), tagged (t), given an
+            upstream link (u), merged into another note (M), turned into a task on
+            a pinned task file (x), archived (a), trashed (d), skipped (s), or
+            opened in your editor (enter).
+        `),
+		Example: heredoc.Doc(`
+            an inbox
+        `),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return inbox.Run(s)
+		},
+	}
+
+	return cmd
+}
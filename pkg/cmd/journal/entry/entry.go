@@ -84,7 +84,7 @@ func run(
 		tagInput = args[0]
 	}
 
-	tags, err := utils.ValidateInput(tagInput)
+	tags, err := utils.ValidateTags(tagInput)
 	if err != nil {
 		fmt.Printf("error processing tags argument: %s", err)
 		os.Exit(1)
@@ -0,0 +1,95 @@
+package merge
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/relocate"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdMerge(s *state.State) *cobra.Command {
+	var into string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <note>... --into <target> [--dry-run]",
+		Short: "Merge notes into one.",
+		Long: heredoc.Doc(`
+			Folds one or more notes into a target note. The bodies are appended
+			to the target in the order given, tags and other list values are
+			combined, and front matter keys the target lacks are copied over. A
+			target that does not exist yet is created from the first note's
+			front matter.
+
+			Links to the merged notes elsewhere in the vault are redirected to
+			the target, and the merged notes are moved to the trash. Use
+			--dry-run to print the plan without changing anything. The merge is
+			journaled as one operation, so 'an undo' reverts it.
+
+			Example:
+			  an merge inbox/a.md inbox/b.md --into atoms/topic.md
+			  an merge atoms/old --into atoms/new --dry-run
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(into) == "" {
+				return errors.New("--into is required")
+			}
+			if s.Handler == nil {
+				return errors.New("merge is unavailable without a vault")
+			}
+
+			target, err := cmdpkg.ResolveVaultPath(cmd, s, withExt(into))
+			if err != nil {
+				return err
+			}
+			sources := make([]string, 0, len(args))
+			for _, arg := range args {
+				path, err := cmdpkg.ResolveVaultPath(cmd, s, withExt(arg))
+				if err != nil {
+					return err
+				}
+				sources = append(sources, path)
+			}
+
+			files, err := s.Handler.WalkFiles(nil, nil, "")
+			if err != nil {
+				return err
+			}
+			plan, err := relocate.Merge(s.Handler.VaultDir(), sources, target, files)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			plan.Describe(out)
+			if dryRun {
+				fmt.Fprintln(out, "Dry run: nothing was changed.")
+				return nil
+			}
+			if err := plan.Apply(s.Handler); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Merged %d note(s) into %s, updated links in %d note(s).\n", len(plan.Trash), filepath.Base(target), plan.Relinked())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&into, "into", "", "Note to merge into; created if it does not exist")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the merge and link changes without applying them")
+	return cmd
+}
+
+func withExt(name string) string {
+	if filepath.Ext(name) != ".md" {
+		return name + ".md"
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+var mergeNotes = map[string]string{
+	"atoms/topic.md": "---\ntags: [go]\n---\n\nTopic.\n",
+	"inbox/a.md":     "---\ntags: [cli]\n---\n\nA.\n",
+	"hubs/index.md":  "- [[a]]\n",
+}
+
+func TestMergeRequiresInto(t *testing.T) {
+	s, _ := cmdtest.NewState(t, mergeNotes)
+	if _, err := cmdtest.Run(t, s, NewCmdMerge, "inbox/a.md"); err == nil {
+		t.Fatalf("expected an error without --into")
+	}
+}
+
+func TestMergeFoldsNoteAndRedirectsLinks(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, mergeNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdMerge, "inbox/a", "--into", "atoms/topic")
+	if err != nil {
+		t.Fatalf("merge returned error: %v", err)
+	}
+	if !strings.Contains(out, "Merged 1 note(s) into topic.md, updated links in 1 note(s).") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	topic, err := os.ReadFile(filepath.Join(vaultDir, "atoms", "topic.md"))
+	if err != nil {
+		t.Fatalf("failed to read target: %v", err)
+	}
+	if string(topic) != "---\ntags: [go, cli]\n---\n\nTopic.\n\nA.\n" {
+		t.Fatalf("unexpected target:\n%s", topic)
+	}
+	hub, _ := os.ReadFile(filepath.Join(vaultDir, "hubs", "index.md"))
+	if string(hub) != "- [[topic]]\n" {
+		t.Fatalf("expected the backlink redirected, got %q", hub)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "trash", "inbox", "a.md")); err != nil {
+		t.Fatalf("expected the source in the trash: %v", err)
+	}
+}
@@ -0,0 +1,369 @@
+package meta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/frontmatter"
+	"github.com/Paintersrp/an/internal/search"
+	indexsvc "github.com/Paintersrp/an/internal/services/index"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/textdiff"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+type options struct {
+	query  string
+	view   string
+	dryRun bool
+}
+
+func NewCmdMeta(s *state.State) *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "meta <get|set|add|remove|rename-key> <key> [value] [note...] [--query q] [--view name]",
+		Short: "Read and edit front matter across notes.",
+		Long: heredoc.Doc(`
+			Reads and edits the YAML front matter of one or more notes in place.
+			Untouched keys keep their order, comments, and quoting.
+
+			Pick the notes by path, with --query using the search syntax
+			(tag:x, key:value, and free text), with --view using a view name
+			such as unfulfilled, or any combination. Use --dry-run to print a
+			diff of every change without writing it. Each command is journaled
+			as one operation, so 'an undo' reverts it.
+
+			Example:
+			  an meta get status --query tag:project
+			  an meta set status shipped --query status:building --dry-run
+			  an meta add tags review --view unfulfilled
+			  an meta remove tags --value draft atoms/idea.md
+			  an meta rename-key upstream up --view default
+		`),
+	}
+
+	cmd.PersistentFlags().StringVar(&opts.query, "query", "", "Select notes with a search query")
+	cmd.PersistentFlags().StringVar(&opts.view, "view", "", "Select the notes in a view")
+	cmd.PersistentFlags().BoolVar(&opts.dryRun, "dry-run", false, "Print the changes as diffs without writing them")
+
+	cmd.AddCommand(
+		newCmdGet(s, opts),
+		newCmdSet(s, opts),
+		newCmdAdd(s, opts),
+		newCmdRemove(s, opts),
+		newCmdRenameKey(s, opts),
+	)
+	return cmd
+}
+
+func newCmdGet(s *state.State, opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key> [note...]",
+		Short: "Print a front matter value for each note.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			paths, err := targets(cmd, s, opts, args[1:])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			found := 0
+			for _, path := range paths {
+				doc, err := readDocument(path)
+				if err != nil {
+					return fmt.Errorf("%s: %w", rel(s, path), err)
+				}
+				node, ok := doc.Get(key)
+				if !ok {
+					continue
+				}
+				found++
+				fmt.Fprintf(writer, "%s\t%s\n", rel(s, path), formatValue(doc, key, node))
+			}
+			if found == 0 {
+				fmt.Fprintf(out, "No notes have %s.\n", key)
+				return nil
+			}
+			return writer.Flush()
+		},
+	}
+}
+
+func newCmdSet(s *state.State, opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value> [note...]",
+		Short: "Set a front matter key.",
+		Long: heredoc.Doc(`
+			Sets key to value on every selected note. The value is read as YAML,
+			so numbers, booleans, and lists such as [a, b] keep their type, and a
+			replaced string keeps its quoting.
+		`),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			return edit(cmd, s, opts, args[2:], func(doc *frontmatter.Document) error {
+				return doc.SetYAML(key, value)
+			})
+		},
+	}
+}
+
+func newCmdAdd(s *state.State, opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <key> <value> [note...]",
+		Short: "Add a value to a front matter list.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			return edit(cmd, s, opts, args[2:], func(doc *frontmatter.Document) error {
+				doc.AddValues(key, value)
+				return nil
+			})
+		},
+	}
+}
+
+func newCmdRemove(s *state.State, opts *options) *cobra.Command {
+	var values []string
+
+	cmd := &cobra.Command{
+		Use:   "remove <key> [note...] [--value v]",
+		Short: "Remove a front matter key or some of its values.",
+		Long: heredoc.Doc(`
+			Removes key from every selected note. With --value, only the given
+			values are removed from the list stored under key.
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			return edit(cmd, s, opts, args[1:], func(doc *frontmatter.Document) error {
+				if len(values) > 0 {
+					doc.RemoveValues(key, values...)
+				} else {
+					doc.Delete(key)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&values, "value", nil, "Remove only these values from the list")
+	return cmd
+}
+
+func newCmdRenameKey(s *state.State, opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename-key <old> <new> [note...]",
+		Short: "Rename a front matter key, keeping its value and position.",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldKey, newKey := args[0], args[1]
+			return edit(cmd, s, opts, args[2:], func(doc *frontmatter.Document) error {
+				_, err := doc.RenameKey(oldKey, newKey)
+				return err
+			})
+		},
+	}
+}
+
+type change struct {
+	path   string
+	before []byte
+	after  []byte
+}
+
+// edit applies fn to every selected note. Nothing is written unless every
+// note can be edited, and the writes are journaled as one group.
+func edit(cmd *cobra.Command, s *state.State, opts *options, args []string, fn func(*frontmatter.Document) error) error {
+	paths, err := targets(cmd, s, opts, args)
+	if err != nil {
+		return err
+	}
+
+	var changes []change
+	for _, path := range paths {
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := frontmatter.Parse(before)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel(s, path), err)
+		}
+		baseline, err := doc.Bytes()
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel(s, path), err)
+		}
+		if err := fn(doc); err != nil {
+			return fmt.Errorf("%s: %w", rel(s, path), err)
+		}
+		after, err := doc.Bytes()
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel(s, path), err)
+		}
+		if !bytes.Equal(baseline, after) {
+			changes = append(changes, change{path: path, before: before, after: after})
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "No notes changed.")
+		return nil
+	}
+
+	if opts.dryRun {
+		for _, c := range changes {
+			name := rel(s, c.path)
+			fmt.Fprint(out, textdiff.Unified("a/"+name, "b/"+name, string(c.before), string(c.after), 3))
+		}
+		fmt.Fprintf(out, "Dry run: %d note(s) would change.\n", len(changes))
+		return nil
+	}
+
+	err = s.Handler.Journal().Group(func() error {
+		for _, c := range changes {
+			if err := s.Handler.WriteFileOp("meta", c.path, c.after); err != nil {
+				return fmt.Errorf("%s: %w", rel(s, c.path), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated %d note(s).\n", len(changes))
+	return nil
+}
+
+// targets resolves the notes named by args, --query, and --view, in that
+// order and without duplicates.
+func targets(cmd *cobra.Command, s *state.State, opts *options, args []string) ([]string, error) {
+	if s.Handler == nil {
+		return nil, errors.New("meta is unavailable without a vault")
+	}
+	if len(args) == 0 && strings.TrimSpace(opts.query) == "" && strings.TrimSpace(opts.view) == "" {
+		return nil, errors.New("name notes or select them with --query or --view")
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, arg := range args {
+		if filepath.Ext(arg) != ".md" {
+			arg += ".md"
+		}
+		path, err := cmdpkg.ResolveVaultPath(cmd, s, arg)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+		add(path)
+	}
+
+	if query := strings.TrimSpace(opts.query); query != "" {
+		matches, err := queryNotes(s, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			add(path)
+		}
+	}
+
+	if view := strings.TrimSpace(opts.view); view != "" {
+		if s.ViewManager == nil {
+			return nil, errors.New("views are not configured")
+		}
+		files, err := s.ViewManager.GetFilesByView(view)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			add(path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("no notes matched")
+	}
+	return paths, nil
+}
+
+// queryNotes returns the notes matching a search query, using the shared
+// index when it is ready and a fresh one otherwise.
+func queryNotes(s *state.State, raw string) ([]string, error) {
+	cfg := search.Config{EnableBody: true}
+	if s.Workspace != nil {
+		cfg.IgnoredFolders = append([]string(nil), s.Workspace.Search.IgnoredFolders...)
+	}
+	idx, err := indexsvc.Snapshot(s.Index, s.Handler.VaultDir(), cfg, func() ([]string, error) {
+		return s.Handler.WalkFiles(nil, nil, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q := search.ParseQuery(raw)
+	var paths []string
+	if q.Term == "" {
+		for _, doc := range idx.FilteredDocuments(q) {
+			paths = append(paths, doc.Path)
+		}
+	} else {
+		for _, result := range idx.Search(q) {
+			paths = append(paths, result.Path)
+		}
+	}
+	return paths, nil
+}
+
+func readDocument(path string) (*frontmatter.Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return frontmatter.Parse(content)
+}
+
+func formatValue(doc *frontmatter.Document, key string, node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.ScalarNode, yaml.SequenceNode:
+		return strings.Join(doc.Strings(key), ", ")
+	default:
+		data, err := yaml.Marshal(node)
+		if err != nil {
+			return ""
+		}
+		return strings.Join(strings.Fields(string(data)), " ")
+	}
+}
+
+func rel(s *state.State, path string) string {
+	if r, err := filepath.Rel(s.Handler.VaultDir(), path); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return path
+}
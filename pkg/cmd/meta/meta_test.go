@@ -0,0 +1,103 @@
+package meta
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/views"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+var metaNotes = map[string]string{
+	"atoms/alpha.md": "---\n# lifecycle\nstatus: building # in progress\ntags: [go]\nfulfilled: false\n---\nAlpha.\n",
+	"atoms/beta.md":  "---\nstatus: building\ntags:\n  - go\n  - draft\nfulfilled: true\n---\nBeta.\n",
+	"atoms/gamma.md": "---\nstatus: idea\n---\nGamma.\n",
+}
+
+func TestMetaSetByQueryWithDryRun(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, metaNotes)
+	alpha := filepath.Join(vaultDir, "atoms", "alpha.md")
+
+	out, err := cmdtest.Run(t, s, NewCmdMeta, "set", "status", "shipped", "--query", "status:building", "--dry-run")
+	if err != nil {
+		t.Fatalf("meta set --dry-run returned error: %v", err)
+	}
+	for _, want := range []string{
+		"--- a/atoms/alpha.md",
+		"-status: building # in progress",
+		"+status: shipped # in progress",
+		"+++ b/atoms/beta.md",
+		"Dry run: 2 note(s) would change.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(cmdtest.ReadFile(t, alpha), "shipped") {
+		t.Fatalf("expected dry run to leave notes untouched")
+	}
+
+	out, err = cmdtest.Run(t, s, NewCmdMeta, "set", "status", "shipped", "--query", "status:building")
+	if err != nil {
+		t.Fatalf("meta set returned error: %v", err)
+	}
+	if !strings.Contains(out, "Updated 2 note(s).") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if got, want := cmdtest.ReadFile(t, alpha), "---\n# lifecycle\nstatus: shipped # in progress\ntags: [go]\nfulfilled: false\n---\nAlpha.\n"; got != want {
+		t.Fatalf("unexpected note:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(cmdtest.ReadFile(t, filepath.Join(vaultDir, "atoms", "gamma.md")), "shipped") {
+		t.Fatalf("expected notes outside the query to be left alone")
+	}
+
+	if _, err := s.Handler.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if !strings.Contains(cmdtest.ReadFile(t, filepath.Join(vaultDir, "atoms", "beta.md")), "status: building") {
+		t.Fatalf("expected one undo to revert every note")
+	}
+}
+
+func TestMetaEditsByPathAndView(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, metaNotes)
+	vm, err := views.NewViewManager(s.Handler, s.Config)
+	if err != nil {
+		t.Fatalf("NewViewManager returned error: %v", err)
+	}
+	s.ViewManager = vm
+	beta := filepath.Join(vaultDir, "atoms", "beta.md")
+
+	if _, err := cmdtest.Run(t, s, NewCmdMeta, "remove", "tags", "atoms/beta", "--value", "draft"); err != nil {
+		t.Fatalf("meta remove returned error: %v", err)
+	}
+	if _, err := cmdtest.Run(t, s, NewCmdMeta, "rename-key", "status", "state", "atoms/beta.md"); err != nil {
+		t.Fatalf("meta rename-key returned error: %v", err)
+	}
+	if got, want := cmdtest.ReadFile(t, beta), "---\nstate: building\ntags:\n  - go\nfulfilled: true\n---\nBeta.\n"; got != want {
+		t.Fatalf("unexpected note:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := cmdtest.Run(t, s, NewCmdMeta, "add", "tags", "review", "--view", "unfulfilled"); err != nil {
+		t.Fatalf("meta add returned error: %v", err)
+	}
+	if !strings.Contains(cmdtest.ReadFile(t, filepath.Join(vaultDir, "atoms", "alpha.md")), "tags: [go, review]") {
+		t.Fatalf("expected the unfulfilled note to be tagged")
+	}
+	if strings.Contains(cmdtest.ReadFile(t, beta), "review") {
+		t.Fatalf("expected the fulfilled note to be left alone")
+	}
+
+	out, err := cmdtest.Run(t, s, NewCmdMeta, "get", "tags", "--query", "tag:go")
+	if err != nil {
+		t.Fatalf("meta get returned error: %v", err)
+	}
+	if !strings.Contains(out, "atoms/alpha.md  go, review") || !strings.Contains(out, "atoms/beta.md   go") {
+		t.Fatalf("unexpected get output:\n%s", out)
+	}
+
+	if _, err := cmdtest.Run(t, s, NewCmdMeta, "set", "status", "x"); err == nil {
+		t.Fatalf("expected an error without any notes selected")
+	}
+}
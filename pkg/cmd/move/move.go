@@ -0,0 +1,110 @@
+package move
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/relocate"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdMove(s *state.State) *cobra.Command {
+	var to string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "move <note>... --to <subdir> [--dry-run]",
+		Aliases: []string{"mv"},
+		Short:   "Move notes to another subdirectory without breaking links.",
+		Long: heredoc.Doc(`
+			Moves one or more notes into a subdirectory of the vault. Relative
+			markdown links inside the moved notes, links in other notes that
+			point at them, and path-qualified wiki links such as [[atoms/idea]]
+			are rewritten so they keep resolving.
+
+			The destination follows the workspace filesystem mode: strict mode
+			only accepts configured subdirectories, confirm mode asks before
+			adding a new one, and free mode adds it. Use --dry-run to print the
+			plan without changing anything. The whole move is journaled as one
+			operation, so 'an undo' reverts it.
+
+			Example:
+			  an move atoms/idea.md --to projects
+			  an move inbox/a.md inbox/b.md --to atoms --dry-run
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subdir := strings.Trim(strings.TrimSpace(to), "/")
+			if subdir == "" {
+				return errors.New("--to is required")
+			}
+			if s.Handler == nil {
+				return errors.New("move is unavailable without a vault")
+			}
+
+			notes := make([]string, 0, len(args))
+			for _, arg := range args {
+				path, err := cmdpkg.ResolveVaultPath(cmd, s, arg)
+				if err != nil {
+					return err
+				}
+				notes = append(notes, path)
+			}
+
+			files, err := s.Handler.WalkFiles(nil, nil, "")
+			if err != nil {
+				return err
+			}
+			plan, err := relocate.Build(s.Handler.VaultDir(), notes, subdir, files)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(plan.Moves) == 0 {
+				fmt.Fprintf(out, "Nothing to move; the notes are already in %s.\n", plan.Subdir)
+				return nil
+			}
+			plan.Describe(out)
+
+			if dryRun {
+				if s.Workspace != nil && !s.Workspace.HasSubdir(plan.Subdir) {
+					if s.Workspace.FileSystemMode == "strict" {
+						return fmt.Errorf("subdirectory %q does not exist; in strict mode add it with `an add-subdir %s`", plan.Subdir, plan.Subdir)
+					}
+					fmt.Fprintf(out, "%s is not a configured subdirectory and would be added.\n", plan.Subdir)
+				}
+				fmt.Fprintln(out, "Dry run: nothing was changed.")
+				return nil
+			}
+
+			if s.Config != nil {
+				confirm := func() bool {
+					fmt.Fprintf(out, "Subdirectory %s does not exist. Create it? (y/n): ", plan.Subdir)
+					answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+					answer = strings.ToLower(strings.TrimSpace(answer))
+					return answer == "y" || answer == "yes"
+				}
+				if err := s.Config.EnsureSubdir(plan.Subdir, confirm); err != nil {
+					return err
+				}
+			}
+
+			if err := plan.Apply(s.Handler); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Moved %d note(s) to %s, updated links in %d note(s).\n", len(plan.Moves), plan.Subdir, len(plan.Rewrites))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Subdirectory to move the notes into")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the moves and link changes without applying them")
+	return cmd
+}
@@ -0,0 +1,91 @@
+package move
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+var moveNotes = map[string]string{
+	"atoms/idea.md":  "See [hub](../hubs/index.md).\n",
+	"hubs/index.md":  "- [idea](../atoms/idea.md)\n",
+	"atoms/other.md": "unrelated\n",
+}
+
+func newMoveState(t *testing.T, mode string) (*state.State, string) {
+	t.Helper()
+	return cmdtest.NewState(t, moveNotes, func(ws *config.Workspace) {
+		ws.FileSystemMode = mode
+		ws.SubDirs = []string{"atoms", "projects"}
+	})
+}
+
+func TestMoveDryRunPrintsPlan(t *testing.T) {
+	s, vaultDir := newMoveState(t, "strict")
+
+	out, err := cmdtest.Run(t, s, NewCmdMove, "atoms/idea.md", "--to", "projects", "--dry-run")
+	if err != nil {
+		t.Fatalf("move --dry-run returned error: %v", err)
+	}
+	for _, want := range []string{
+		"move   atoms/idea.md -> projects/idea.md",
+		"relink hubs/index.md: ../atoms/idea.md -> ../projects/idea.md",
+		"Dry run: nothing was changed.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "atoms", "idea.md")); err != nil {
+		t.Fatalf("expected dry run to leave the note in place: %v", err)
+	}
+}
+
+func TestMoveRewritesBacklinks(t *testing.T) {
+	s, vaultDir := newMoveState(t, "strict")
+
+	out, err := cmdtest.Run(t, s, NewCmdMove, "atoms/idea.md", "--to", "projects")
+	if err != nil {
+		t.Fatalf("move returned error: %v", err)
+	}
+	if !strings.Contains(out, "Moved 1 note(s) to projects, updated links in 1 note(s).") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	hub, err := os.ReadFile(filepath.Join(vaultDir, "hubs", "index.md"))
+	if err != nil {
+		t.Fatalf("failed to read hub: %v", err)
+	}
+	if string(hub) != "- [idea](../projects/idea.md)\n" {
+		t.Fatalf("expected backlink rewritten, got %q", hub)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "projects", "idea.md")); err != nil {
+		t.Fatalf("expected note moved: %v", err)
+	}
+}
+
+func TestMoveEnforcesFileSystemMode(t *testing.T) {
+	s, vaultDir := newMoveState(t, "strict")
+	if _, err := cmdtest.Run(t, s, NewCmdMove, "atoms/idea.md", "--to", "new"); err == nil {
+		t.Fatalf("expected strict mode to refuse an unknown subdirectory")
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "atoms", "idea.md")); err != nil {
+		t.Fatalf("expected the note to stay put: %v", err)
+	}
+
+	s, vaultDir = newMoveState(t, "free")
+	if _, err := cmdtest.Run(t, s, NewCmdMove, "atoms/idea.md", "--to", "new"); err != nil {
+		t.Fatalf("expected free mode to add the subdirectory, got %v", err)
+	}
+	if !s.Workspace.HasSubdir("new") {
+		t.Fatalf("expected the new subdirectory to be configured")
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "new", "idea.md")); err != nil {
+		t.Fatalf("expected note moved: %v", err)
+	}
+}
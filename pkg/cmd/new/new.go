@@ -16,19 +16,37 @@ import (
 	"github.com/Paintersrp/an/pkg/shared/flags"
 )
 
+// openNote is swapped out in tests to avoid launching an editor.
+var openNote = func(path string) error {
+	return note.OpenFromPath(path, false)
+}
+
+// stdinIsTerminal is swapped out in tests, which never run on a terminal.
+var stdinIsTerminal = arg.StdinIsTerminal
+
 func NewCmdNew(s *state.State) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "new [title] [tags] [content] [--template template_name] [--links link1 link2 ...] [--pin] [--upstream] [--symlink] [--reverse-symlink] [--paste]",
+		Use:     "new [title] [tags] [content] [--template template_name] [--scaffold template_name] [--links link1 link2 ...] [--pin] [--upstream] [--symlink] [--reverse-symlink] [--paste] [--stdin] [--fence lang]",
 		Aliases: []string{"n"},
 		Short:   "Create a new zettelkasten note.",
 		Long: heredoc.Doc(`
 			The 'new' command creates a new atomic zettelkasten note in your note vault directory.
 			Provide a required title argument and an optional tags argument to add tags to the newly created note.
 			You can also specify a template, add links, pin the note, or set an upstream file using flags.
+			Use --stdin to take the note content from piped input; input that is not Markdown is wrapped
+			in a code block, and --fence sets the code block language. When the content comes from stdin,
+			or stdin is not a terminal, no editor is opened and the path of the new note is printed instead,
+			so the command can be used in scripts.
+
+			With --scaffold, every note listed in the template's scaffold manifest is created at once,
+			each from its own template and linked to the others. Either all of the notes are created or,
+			if any step fails, none of them are.
 		`),
 		Example: heredoc.Doc(`
 			an new cli-notes "cli notetaking zettel" --links 'zettelkasten cli-moc' --upstream
 			an n Tasks -t tasks --pin
+			an new --scaffold project Apollo
+			make test 2>&1 | an new "test-run" --stdin
 		`),
 		Args: cobra.MaximumNArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -41,11 +59,15 @@ func NewCmdNew(s *state.State) *cobra.Command {
 	flags.AddUpstream(cmd)
 	flags.AddPin(cmd)
 	flags.AddPaste(cmd)
+	flags.AddStdin(cmd)
+	flags.AddFence(cmd)
 
 	cmd.Flags().
 		Bool("symlink", false, "Automatically add a symlink to the new note in the current working directory.")
 	cmd.Flags().
 		Bool("reverse-symlink", false, "Create the note in the current working directory and add a symlink in the vault.")
+	cmd.Flags().
+		String("scaffold", "", "Create every note listed in the scaffold of the given template.")
 
 	return cmd
 }
@@ -86,19 +108,33 @@ func run(
 		)
 	}
 
+	scaffold, err := cmd.Flags().GetString("scaffold")
+	if err != nil {
+		return err
+	}
+	if scaffold != "" && (createSymlink || reverseSymlink) {
+		return fmt.Errorf("cannot combine --scaffold with symlink flags")
+	}
+
 	paste, err := flags.HandlePaste(cmd)
 	if err != nil {
 		return err
 	}
 
-	var content string
-	if paste {
-		msg, err := clipboard.ReadAll()
-		if err == nil && msg != "" {
-			content = msg
-		}
-	} else if len(args) >= 3 {
-		content = arg.HandleContent(args)
+	readStdin, err := flags.HandleStdin(cmd)
+	if err != nil {
+		return err
+	}
+	content, err := handleContent(cmd, args, paste)
+	if err != nil {
+		return err
+	}
+	// The editor would inherit the drained pipe as its input, so piped runs
+	// only create the note.
+	interactive := !readStdin && stdinIsTerminal()
+
+	if scaffold != "" {
+		return runScaffold(cmd, s, scaffold, vaultDir, subDir, title, tags, links, upstream, content, interactive)
 	}
 
 	var (
@@ -131,7 +167,18 @@ func run(
 	}
 
 	flags.HandlePin(cmd, s.Config, n, "text", title)
-	note.StaticHandleNoteLaunch(n, s.Templater, tmpl, content, nil)
+	if interactive {
+		note.StaticHandleNoteLaunch(n, s.Templater, tmpl, content, nil)
+	} else {
+		metadata, err := note.CollectTemplateMetadataNonInteractive(s.Templater, tmpl, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := n.Create(tmpl, s.Templater, content, metadata); err != nil {
+			return fmt.Errorf("create note: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), n.GetFilepath())
+	}
 
 	if createSymlink {
 		notePath := n.GetFilepath()
@@ -154,3 +201,90 @@ func run(
 	}
 	return nil
 }
+
+func runScaffold(
+	cmd *cobra.Command,
+	s *state.State,
+	name, vaultDir, subDir, title string,
+	tags, links []string,
+	upstream, content string,
+	interactive bool,
+) error {
+	files, err := s.Templater.ScaffoldFiles(name, title)
+	if err != nil {
+		return err
+	}
+
+	handled := map[string]bool{subDir: true}
+	for _, file := range files {
+		if file.Subdir != "" && !handled[file.Subdir] {
+			handled[file.Subdir] = true
+			s.Config.HandleSubdir(file.Subdir)
+		}
+	}
+
+	scaffold := &note.Scaffold{
+		VaultDir: vaultDir,
+		SubDir:   subDir,
+		Files:    files,
+		Tags:     tags,
+		Links:    links,
+		Upstream: upstream,
+		Content:  content,
+	}
+	paths, err := scaffold.Create(s.Templater)
+	if err != nil {
+		return fmt.Errorf("failed to create scaffold: %w", err)
+	}
+	for _, path := range paths {
+		fmt.Fprintf(cmd.OutOrStdout(), "Created %s\n", path)
+	}
+
+	root := files[0]
+	rootDir := root.Subdir
+	if rootDir == "" {
+		rootDir = subDir
+	}
+	n := note.NewZettelkastenNote(vaultDir, rootDir, root.Filename, tags, links, upstream)
+	flags.HandlePin(cmd, s.Config, n, "text", root.Filename)
+
+	if !interactive {
+		return nil
+	}
+	return openNote(paths[0])
+}
+
+// handleContent returns the note content from stdin, the clipboard, or the
+// content argument, fenced when --fence is set.
+func handleContent(cmd *cobra.Command, args []string, paste bool) (string, error) {
+	readStdin, err := flags.HandleStdin(cmd)
+	if err != nil {
+		return "", err
+	}
+	fence, err := flags.HandleFence(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	if readStdin {
+		if paste {
+			return "", fmt.Errorf("cannot use both --stdin and --paste flags simultaneously")
+		}
+		return arg.HandleStdinContent(fence)
+	}
+
+	var content string
+	if paste {
+		msg, err := clipboard.ReadAll()
+		if err == nil && msg != "" {
+			content = msg
+		}
+	} else if len(args) >= 3 {
+		content = arg.HandleContent(args)
+	}
+
+	if content != "" && fence != "" {
+		content = arg.Fence(content, fence)
+	}
+	return content, nil
+}
@@ -1,8 +1,10 @@
 package new
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -10,6 +12,7 @@ import (
 	"github.com/Paintersrp/an/internal/config"
 	"github.com/Paintersrp/an/internal/state"
 	"github.com/Paintersrp/an/internal/templater"
+	"github.com/Paintersrp/an/pkg/shared/arg"
 )
 
 func TestRunCreatesSingleSymlink(t *testing.T) {
@@ -111,3 +114,136 @@ func TestRunCreatesSingleSymlink(t *testing.T) {
 		t.Fatalf("expected symlink target %s, got %s", expectedTarget, target)
 	}
 }
+
+func TestRunScaffoldCreatesAllNotesAndOpensRoot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	vaultDir := t.TempDir()
+	viper.Set("vaultdir", vaultDir)
+	viper.Set("subdir", "")
+
+	templateDir := filepath.Join(vaultDir, ".an", "templates")
+	if err := os.MkdirAll(templateDir, 0o755); err != nil {
+		t.Fatalf("failed to create template dir: %v", err)
+	}
+	manifest := "{{/* an:manifest\nscaffold:\n  - key: hub\n    template: zet\n  - key: notes\n    template: zet\n    up: hub\n*/}}\n"
+	if err := os.WriteFile(filepath.Join(templateDir, "kit.tmpl"), []byte(manifest), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "kit") })
+
+	ws := &config.Workspace{
+		VaultDir:       vaultDir,
+		FileSystemMode: "strict",
+		SubDirs:        []string{""},
+		NamedPins:      config.PinMap{},
+		NamedTaskPins:  config.PinMap{},
+	}
+	cfg := &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": ws},
+		CurrentWorkspace: "default",
+	}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	st := &state.State{Config: cfg, Workspace: ws, WorkspaceName: "default", Templater: tmpl}
+
+	var opened []string
+	prev := openNote
+	openNote = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+	t.Cleanup(func() { openNote = prev })
+	prevTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdinIsTerminal = prevTerminal })
+
+	cmd := NewCmdNew(st)
+	if err := cmd.Flags().Set("scaffold", "kit"); err != nil {
+		t.Fatalf("failed to set scaffold flag: %v", err)
+	}
+	if err := run(cmd, []string{"apollo"}, st); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	for _, name := range []string{"apollo.md", "apollo-notes.md"} {
+		if _, err := os.Stat(filepath.Join(vaultDir, name)); err != nil {
+			t.Fatalf("expected %s to be created: %v", name, err)
+		}
+	}
+	if len(opened) != 1 || opened[0] != filepath.Join(vaultDir, "apollo.md") {
+		t.Fatalf("expected the root note to be opened, got %v", opened)
+	}
+}
+
+func TestRunFromStdinSkipsEditorAndPrintsPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	vaultDir := t.TempDir()
+	viper.Set("vaultdir", vaultDir)
+	viper.Set("subdir", "")
+
+	ws := &config.Workspace{
+		VaultDir:       vaultDir,
+		FileSystemMode: "strict",
+		SubDirs:        []string{""},
+		NamedPins:      config.PinMap{},
+		NamedTaskPins:  config.PinMap{},
+	}
+	cfg := &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": ws},
+		CurrentWorkspace: "default",
+	}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	st := &state.State{Config: cfg, Workspace: ws, WorkspaceName: "default", Templater: tmpl}
+
+	prevStdin := arg.Stdin
+	arg.Stdin = strings.NewReader("ok  \tgithub.com/example/pkg\t0.1s\n")
+	t.Cleanup(func() { arg.Stdin = prevStdin })
+	prevTerminal := stdinIsTerminal
+	stdinIsTerminal = func() bool { return true }
+	t.Cleanup(func() { stdinIsTerminal = prevTerminal })
+	prevOpen := openNote
+	openNote = func(path string) error {
+		t.Fatalf("expected no editor for piped content, opened %s", path)
+		return nil
+	}
+	t.Cleanup(func() { openNote = prevOpen })
+
+	cmd := NewCmdNew(st)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Flags().Set("stdin", "true"); err != nil {
+		t.Fatalf("failed to set stdin flag: %v", err)
+	}
+	if err := run(cmd, []string{"test-run"}, st); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+
+	notePath := filepath.Join(vaultDir, "test-run.md")
+	if out.String() != notePath+"\n" {
+		t.Fatalf("expected the note path printed, got %q", out.String())
+	}
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("expected note file at %s: %v", notePath, err)
+	}
+	if !strings.Contains(string(data), "github.com/example/pkg") {
+		t.Fatalf("expected the piped content in the note, got:\n%s", data)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 func NewCmdNotes(s *state.State) *cobra.Command {
 	var viewFlag string
+	var tagFlags []string
 	cmd := &cobra.Command{
 		Use:     "notes --view {view_name}",
 		Aliases: []string{"n"},
@@ -30,6 +31,9 @@ func NewCmdNotes(s *state.State) *cobra.Command {
             - Viewing and managing unfulfilled notes
             - And more!
 
+            Use the '--tag' flag to start with the list filtered to a tag. Nested
+            tags match their parents, so '--tag project' includes project/apollo.
+
             Use the '--view' flag to select the initial view you want to start with.
             The available views are:
 
@@ -43,16 +47,17 @@ func NewCmdNotes(s *state.State) *cobra.Command {
             instructions to perform various actions on your notes.
         `),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run(s, s.Views, viewFlag)
+			return run(s, s.Views, viewFlag, tagFlags)
 		},
 	}
 
 	cmd.Flags().StringVarP(&viewFlag, "view", "v", "default", "Select initial view")
+	cmd.Flags().StringSliceVarP(&tagFlags, "tag", "t", nil, "Filter the list to notes with a tag")
 	return cmd
 }
 
-func run(s *state.State, views map[string]v.View, viewFlag string) error {
-	if err := notes.Run(s, views, viewFlag); err != nil {
+func run(s *state.State, views map[string]v.View, viewFlag string, tags []string) error {
+	if err := notes.RunFiltered(s, views, viewFlag, tags); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,61 @@
+package restore
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdRestore(s *state.State) *cobra.Command {
+	var rev int
+
+	cmd := &cobra.Command{
+		Use:   "restore <note> --rev N",
+		Short: "Restore a note to a saved version.",
+		Long: heredoc.Doc(`
+			Replaces the content of a note with a version kept in the snapshot
+			store (.an/history); 'an history <note>' lists the revisions. A
+			deleted note is recreated. The content being replaced is saved as a
+			new version first, and the restore is journaled, so 'an undo'
+			reverts it.
+
+			Example:
+			  an restore atoms/zettel.md --rev 3
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := s.Handler.History()
+			if store == nil {
+				return errors.New("note history is disabled; set history.enabled on the workspace")
+			}
+			if rev <= 0 {
+				return errors.New("--rev must be a revision listed by 'an history <note>'")
+			}
+			path, err := cmdpkg.ResolveVaultPath(cmd, s, snapshot.WithExt(args[0]))
+			if err != nil {
+				return err
+			}
+
+			content, _, err := store.Read(path, rev)
+			if err != nil {
+				return err
+			}
+			if err := s.Handler.WriteFileOp("restore", path, content); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Restored %s to rev %d.\n", filepath.Base(path), rev)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&rev, "rev", 0, "Revision to restore")
+	cmd.MarkFlagRequired("rev")
+	return cmd
+}
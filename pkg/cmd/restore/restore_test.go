@@ -0,0 +1,51 @@
+package restore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/snapshot"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+func TestRestoreWritesSavedVersion(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, nil)
+	s.Handler.SetHistory(snapshot.Open(vaultDir, snapshot.Options{}))
+	notePath := filepath.Join(vaultDir, "atoms", "note.md")
+	for _, content := range []string{"first\n", "second\n", "third\n"} {
+		if err := s.Handler.WriteFileOp("edit", notePath, []byte(content)); err != nil {
+			t.Fatalf("WriteFileOp returned error: %v", err)
+		}
+	}
+
+	out, err := cmdtest.Run(t, s, NewCmdRestore, "atoms/note", "--rev", "1")
+	if err != nil {
+		t.Fatalf("restore returned error: %v", err)
+	}
+	if out != "Restored note.md to rev 1.\n" {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if data, _ := os.ReadFile(notePath); string(data) != "first\n" {
+		t.Fatalf("expected rev 1 restored, got %q", data)
+	}
+
+	versions, err := s.Handler.History().Versions(notePath)
+	if err != nil {
+		t.Fatalf("Versions returned error: %v", err)
+	}
+	if last := versions[len(versions)-1]; last.Op != "restore" || last.Rev != 3 {
+		t.Fatalf("expected the replaced content saved as rev 3, got %+v", last)
+	}
+
+	if _, err := s.Handler.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(notePath); string(data) != "third\n" {
+		t.Fatalf("expected undo to revert the restore, got %q", data)
+	}
+
+	if _, err := cmdtest.Run(t, s, NewCmdRestore, "atoms/note", "--rev", "7"); err == nil {
+		t.Fatalf("expected an unknown revision to fail")
+	}
+}
@@ -16,6 +16,9 @@ import (
 	"github.com/Paintersrp/an/internal/search"
 	indexsvc "github.com/Paintersrp/an/internal/services/index"
 	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/pkg/cmd/review/reviewCards"
+	"github.com/Paintersrp/an/pkg/cmd/review/reviewGrade"
+	"github.com/Paintersrp/an/pkg/cmd/review/reviewHistory"
 )
 
 // metadataFlag captures repeated key=value metadata filters.
@@ -70,6 +73,7 @@ func (m metadataFlag) Set(value string) error {
 func NewCmdReview(s *state.State) *cobra.Command {
 	var (
 		mode      string
+		queueName string
 		limit     int
 		minAge    time.Duration
 		showGraph bool
@@ -89,7 +93,11 @@ func NewCmdReview(s *state.State) *cobra.Command {
 		Short: "Run guided review rituals backed by resurfacing queues",
 		Long: `Review assembles the notes that deserve another look,
 surfaces how they connect, and walks you through a repeatable checklist.
-Use it to keep daily, weekly, or project retrospectives inside your vault.`,
+Use it to keep daily, weekly, or project retrospectives inside your vault.
+
+Queue presets declared under review.queues in the workspace config bundle
+buckets, filters, limits, and a checklist template. Select one with --queue;
+explicit --limit, --min-age, and --mode flags still take precedence.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if s == nil {
 				return errors.New("state is not configured")
@@ -113,6 +121,33 @@ Use it to keep daily, weekly, or project retrospectives inside your vault.`,
 				query.Metadata[key] = append(query.Metadata[key], values...)
 			}
 
+			opts := reviewsvc.ResurfaceOptions{
+				Now:        time.Now(),
+				MinimumAge: minAge,
+				Limit:      limit,
+				Buckets:    reviewsvc.DefaultBuckets(),
+				Query:      query,
+			}
+
+			templateName := ""
+			if strings.TrimSpace(queueName) != "" {
+				preset, err := reviewsvc.LoadQueuePreset(ws.Review, queueName)
+				if err != nil {
+					return err
+				}
+				opts = preset.Apply(opts)
+				flags := cmd.Flags()
+				if flags.Changed("min-age") {
+					opts.MinimumAge = minAge
+				}
+				if flags.Changed("limit") {
+					opts.Limit = limit
+				}
+				if !flags.Changed("mode") {
+					templateName = preset.Template
+				}
+			}
+
 			var idx *search.Index
 			if s.Index != nil {
 				snapshot, err := s.Index.AcquireSnapshot()
@@ -139,13 +174,13 @@ Use it to keep daily, weekly, or project retrospectives inside your vault.`,
 				idx = built
 			}
 
-			queue := reviewsvc.BuildResurfaceQueue(idx, reviewsvc.ResurfaceOptions{
-				Now:        time.Now(),
-				MinimumAge: minAge,
-				Limit:      limit,
-				Buckets:    reviewsvc.DefaultBuckets(),
-				Query:      query,
-			})
+			schedule, err := reviewsvc.LoadSchedule(s.Vault)
+			if err != nil {
+				return fmt.Errorf("load review schedule: %w", err)
+			}
+
+			opts.Schedule = schedule
+			queue := reviewsvc.BuildResurfaceQueue(idx, opts)
 
 			out := cmd.OutOrStdout()
 			if len(queue) == 0 {
@@ -174,9 +209,11 @@ Use it to keep daily, weekly, or project retrospectives inside your vault.`,
 				printGraph(out, graph, s.Vault)
 			}
 
-			templateName, err := resolveTemplate(mode)
-			if err != nil {
-				return err
+			if templateName == "" {
+				templateName, err = resolveTemplate(mode)
+				if err != nil {
+					return err
+				}
 			}
 
 			manifest, err := s.Templater.Manifest(templateName)
@@ -215,6 +252,7 @@ Use it to keep daily, weekly, or project retrospectives inside your vault.`,
 	}
 
 	cmd.Flags().StringVar(&mode, "mode", "daily", "Review mode to run (daily, weekly, retro)")
+	cmd.Flags().StringVar(&queueName, "queue", "", "Named queue preset from review.queues to build the resurfacing queue")
 	cmd.Flags().IntVar(&limit, "limit", 12, "Maximum resurfacing candidates to include")
 	cmd.Flags().DurationVar(&minAge, "min-age", 0, "Minimum age (for example 48h) before resurfacing a note")
 	cmd.Flags().BoolVar(&showGraph, "graph", false, "Render a backlink graph for resurfacing candidates")
@@ -222,6 +260,10 @@ Use it to keep daily, weekly, or project retrospectives inside your vault.`,
 	cmd.Flags().Var(meta, "metadata", "Metadata filter in key=value form (repeatable)")
 	cmd.Flags().StringVar(&logPath, "log-path", logPath, "Directory to store review logs (relative to the vault by default)")
 
+	cmd.AddCommand(reviewGrade.NewCmdReviewGrade(s))
+	cmd.AddCommand(reviewCards.NewCmdReviewCards(s))
+	cmd.AddCommand(reviewHistory.NewCmdReviewHistory(s))
+
 	return cmd
 }
 
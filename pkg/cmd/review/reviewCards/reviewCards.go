@@ -0,0 +1,93 @@
+package reviewCards
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+	"github.com/Paintersrp/an/internal/state"
+	reviewTui "github.com/Paintersrp/an/internal/tui/review"
+)
+
+func NewCmdReviewCards(s *state.State) *cobra.Command {
+	var (
+		all   bool
+		limit int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cards",
+		Short: "Drill flashcards declared in your notes.",
+		Long: heredoc.Doc(`
+			Collects question/answer cards from your notes and drills them with a
+			flip-card interface. Cards are declared either as single lines in the
+			form "question:: answer" or as headings tagged with #card, where the
+			answer is the block that follows the heading. The "::" must be
+			followed by a space, so std::vector, Foo::Bar, bracketed Dataview
+			fields ([key:: value]), and "::" inside code do not make cards.
+
+			Grades use the same spaced-repetition schedule as the review queue, and
+			each session is recorded in the review log directory. Card IDs come
+			from the question, so a card keeps its schedule when its note is
+			moved or renamed.
+
+			Example:
+			  an review cards
+			  an review cards --all --limit 20
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s == nil || s.Config == nil {
+				return errors.New("state is not configured")
+			}
+			ws := s.Config.MustWorkspace()
+			if !ws.Review.Enable {
+				return errors.New("review rituals are disabled for this workspace")
+			}
+
+			cards, err := reviewsvc.CollectVaultCards(s.Vault, ws.Search.IgnoredFolders)
+			if err != nil {
+				return fmt.Errorf("collect cards: %w", err)
+			}
+
+			schedule, err := reviewsvc.LoadSchedule(s.Vault)
+			if err != nil {
+				return fmt.Errorf("load review schedule: %w", err)
+			}
+			if schedule.RelinkCards(cards) {
+				if err := schedule.Save(); err != nil {
+					return fmt.Errorf("save review schedule: %w", err)
+				}
+			}
+			if !all {
+				cards = reviewsvc.DueCards(cards, schedule, time.Now())
+			}
+			if limit > 0 && len(cards) > limit {
+				cards = cards[:limit]
+			}
+
+			out := cmd.OutOrStdout()
+			if len(cards) == 0 {
+				fmt.Fprintln(out, "No flashcards are due.")
+				return nil
+			}
+
+			path, err := reviewTui.RunCards(s, cards)
+			if err != nil {
+				return err
+			}
+			if path != "" {
+				fmt.Fprintf(out, "Review log saved: %s\n", path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Drill every card, including those not yet due")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of cards to drill (0 for no limit)")
+
+	return cmd
+}
@@ -0,0 +1,67 @@
+package reviewGrade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdReviewGrade(s *state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grade [path] [again|hard|good|easy]",
+		Short: "Grade a resurfaced note and schedule its next review.",
+		Long: heredoc.Doc(`
+			Records how well you recalled a note and reschedules it using a
+			spaced-repetition interval. Schedules are stored in
+			<vault>/.an/review-state.json and drive the order of the review queue.
+
+			Example:
+			  an review grade atoms/idea.md good
+		`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s == nil {
+				return errors.New("state is not configured")
+			}
+
+			path, err := cmdpkg.ResolveVaultPath(cmd, s, args[0])
+			if err != nil {
+				return err
+			}
+
+			grade, err := reviewsvc.ParseGrade(args[1])
+			if err != nil {
+				return err
+			}
+
+			schedule, err := reviewsvc.LoadSchedule(s.Vault)
+			if err != nil {
+				return fmt.Errorf("load review schedule: %w", err)
+			}
+
+			entry := schedule.Grade(path, grade, time.Now())
+			if err := schedule.Save(); err != nil {
+				return fmt.Errorf("save review schedule: %w", err)
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Graded %s as %s — next review %s (ease %.2f)\n",
+				args[0],
+				grade,
+				entry.Due.Local().Format("2006-01-02"),
+				entry.Ease,
+			)
+			return nil
+		},
+	}
+
+	return cmd
+}
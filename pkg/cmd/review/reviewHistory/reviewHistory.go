@@ -0,0 +1,122 @@
+package reviewHistory
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	reviewsvc "github.com/Paintersrp/an/internal/review"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+// weeklyRitual is the manifest name used by the weekly review template.
+const weeklyRitual = "review-weekly"
+
+func NewCmdReviewHistory(s *state.State) *cobra.Command {
+	var (
+		weeks        int
+		top          int
+		logPath      string
+		appendWeekly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Summarize logged review sessions.",
+		Long: heredoc.Doc(`
+			Parses the sessions recorded in the review log directory and reports
+			how often each ritual ran per week, which notes were reviewed the most
+			and least, which notes have never been resurfaced, the average session
+			length (checklist steps answered and notes resurfaced), and which
+			checklist steps are skipped.
+
+			Use --append-weekly to attach the summary to the next weekly review log.
+
+			Example:
+			  an review history
+			  an review history --weeks 12 --top 10
+			  an review history --append-weekly
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s == nil || s.Config == nil {
+				return errors.New("state is not configured")
+			}
+			ws := s.Config.MustWorkspace()
+			if !ws.Review.Enable {
+				return errors.New("review rituals are disabled for this workspace")
+			}
+
+			dir := strings.TrimSpace(logPath)
+			if dir == "" {
+				dir = ws.Review.Directory
+			}
+			logDir, _, err := reviewsvc.EnsureLogDir(s.Vault, dir)
+			if err != nil {
+				return fmt.Errorf("prepare review log directory: %w", err)
+			}
+
+			sessions, err := reviewsvc.LoadSessions(logDir)
+			if err != nil {
+				return fmt.Errorf("load review sessions: %w", err)
+			}
+
+			notes, err := vaultNotes(s.Vault, logDir, ws.Search.IgnoredFolders)
+			if err != nil {
+				return fmt.Errorf("collect notes: %w", err)
+			}
+
+			summary := reviewsvc.SummarizeHistory(sessions, reviewsvc.HistoryOptions{
+				Now:   time.Now(),
+				Weeks: weeks,
+				Top:   top,
+				Notes: notes,
+			})
+			report := reviewsvc.RenderHistoryMarkdown(summary)
+
+			out := cmd.OutOrStdout()
+			fmt.Fprint(out, report)
+
+			if appendWeekly {
+				path, err := reviewsvc.QueueLogAppendix(s.Vault, weeklyRitual, report)
+				if err != nil {
+					return fmt.Errorf("queue summary for weekly review: %w", err)
+				}
+				fmt.Fprintf(out, "\nSummary will be appended to the next weekly review (%s).\n", path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&weeks, "weeks", 8, "Number of trailing weeks to report per ritual")
+	cmd.Flags().IntVar(&top, "top", 5, "Number of notes to list in the most, least, and never reviewed sections")
+	cmd.Flags().StringVar(&logPath, "log-path", "", "Directory containing review logs (relative to the vault by default)")
+	cmd.Flags().BoolVar(&appendWeekly, "append-weekly", false, "Append the summary to the next weekly review log")
+
+	return cmd
+}
+
+// vaultNotes lists vault-relative note paths, excluding the review logs
+// themselves so they are not reported as never resurfaced.
+func vaultNotes(vault, logDir string, ignored []string) ([]string, error) {
+	paths, err := reviewsvc.VaultNotes(vault, ignored)
+	if err != nil {
+		return nil, err
+	}
+	notes := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if rel, err := filepath.Rel(logDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel, err := filepath.Rel(vault, path)
+		if err != nil {
+			continue
+		}
+		notes = append(notes, filepath.ToSlash(rel))
+	}
+	return notes, nil
+}
@@ -161,3 +161,43 @@ func TestReviewCommand_LogWriteFailure(t *testing.T) {
 		t.Fatalf("expected error to mention directory preparation, got: %v", err)
 	}
 }
+
+func TestReviewCommand_UnknownQueue(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	vault := t.TempDir()
+	cfg := &config.Config{
+		Workspaces: map[string]*config.Workspace{
+			"default": {
+				VaultDir: vault,
+				Review: config.ReviewConfig{
+					Enable:    true,
+					Directory: "logs",
+					Queues:    map[string]config.ReviewQueue{"reading": {Tags: []string{"reading"}}},
+				},
+			},
+		},
+		CurrentWorkspace: "default",
+	}
+	if err := cfg.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+
+	tmpl, err := templater.NewTemplater(cfg.MustWorkspace())
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+
+	st := &state.State{Config: cfg, Workspace: cfg.MustWorkspace(), Templater: tmpl, Vault: vault}
+
+	cmd := NewCmdReview(st)
+	cmd.SetArgs([]string{"--queue", "papers"})
+	var output bytes.Buffer
+	cmd.SetOut(&output)
+	cmd.SetErr(&output)
+
+	err = cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "available: reading") {
+		t.Fatalf("expected unknown queue error listing presets, got %v", err)
+	}
+}
@@ -8,20 +8,32 @@ import (
 	"github.com/Paintersrp/an/pkg/cmd/addSubdir"
 	"github.com/Paintersrp/an/pkg/cmd/archive"
 	"github.com/Paintersrp/an/pkg/cmd/capture"
+	"github.com/Paintersrp/an/pkg/cmd/dedupe"
+	"github.com/Paintersrp/an/pkg/cmd/diff"
 	"github.com/Paintersrp/an/pkg/cmd/echo"
+	"github.com/Paintersrp/an/pkg/cmd/export"
+	"github.com/Paintersrp/an/pkg/cmd/history"
+	"github.com/Paintersrp/an/pkg/cmd/inbox"
 	"github.com/Paintersrp/an/pkg/cmd/initialize"
 	"github.com/Paintersrp/an/pkg/cmd/journal"
+	"github.com/Paintersrp/an/pkg/cmd/merge"
+	"github.com/Paintersrp/an/pkg/cmd/meta"
+	"github.com/Paintersrp/an/pkg/cmd/move"
 	"github.com/Paintersrp/an/pkg/cmd/new"
 	"github.com/Paintersrp/an/pkg/cmd/notes"
 	"github.com/Paintersrp/an/pkg/cmd/open"
 	"github.com/Paintersrp/an/pkg/cmd/pin"
+	"github.com/Paintersrp/an/pkg/cmd/restore"
         "github.com/Paintersrp/an/pkg/cmd/review"
         "github.com/Paintersrp/an/pkg/cmd/settings"
+	"github.com/Paintersrp/an/pkg/cmd/split"
 	"github.com/Paintersrp/an/pkg/cmd/symlink"
 	"github.com/Paintersrp/an/pkg/cmd/tags"
 	"github.com/Paintersrp/an/pkg/cmd/tasks"
+	"github.com/Paintersrp/an/pkg/cmd/templates"
 	"github.com/Paintersrp/an/pkg/cmd/trash"
 	"github.com/Paintersrp/an/pkg/cmd/unarchive"
+	"github.com/Paintersrp/an/pkg/cmd/undo"
 	"github.com/Paintersrp/an/pkg/cmd/untrash"
 	"github.com/Paintersrp/an/pkg/cmd/views"
 	"github.com/Paintersrp/an/pkg/cmd/workspace"
@@ -70,8 +82,9 @@ func NewCmdRoot(s *state.State) (*cobra.Command, error) {
 		addSubdir.NewCmdAddSubdir(s),
 		new.NewCmdNew(s),
 		open.NewCmdOpen(s.Config),
-		tags.NewCmdTags(s.Config),
+		tags.NewCmdTags(s),
 		tasks.NewCmdTasks(s),
+		templates.NewCmdTemplates(s),
 		pin.NewCmdPin(s, "text"),
 		echo.NewCmdEcho(s),
                 capture.NewCmdCapture(s),
@@ -83,8 +96,19 @@ func NewCmdRoot(s *state.State) (*cobra.Command, error) {
 		unarchive.NewCmdUnarchive(s),
 		trash.NewCmdTrash(s),
 		untrash.NewCmdUntrash(s),
+		move.NewCmdMove(s),
+		split.NewCmdSplit(s),
+		merge.NewCmdMerge(s),
+		meta.NewCmdMeta(s),
+		dedupe.NewCmdDedupe(s),
+		undo.NewCmdUndo(s),
+		history.NewCmdHistory(s),
+		diff.NewCmdDiff(s),
+		restore.NewCmdRestore(s),
 		journal.NewCmdJournal(s),
 		views.NewCmdViews(s),
+		export.NewCmdExport(s),
+		inbox.NewCmdInbox(s),
 		workspace.NewCmdWorkspace(s),
 	)
 
@@ -0,0 +1,83 @@
+package split
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/relocate"
+	"github.com/Paintersrp/an/internal/state"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+func NewCmdSplit(s *state.State) *cobra.Command {
+	var level int
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "split <note> [--level N] [--dry-run]",
+		Short: "Split a note into one note per heading.",
+		Long: heredoc.Doc(`
+			Turns every heading of the given level in a note into a note of its
+			own, next to the original. Each new note copies the original's front
+			matter, so it keeps the same template layout and tags, with its title
+			set to the heading and its upstream link pointing back at the
+			original. The original keeps its other content, with each section
+			replaced by a link to the new note.
+
+			Links to the split headings, such as [[note#Heading]] or
+			[text](note.md#heading), are retargeted to the new notes. Use
+			--dry-run to print the plan without changing anything. The split is
+			journaled as one operation, so 'an undo' reverts it.
+
+			Example:
+			  an split atoms/guide.md
+			  an split atoms/guide.md --level 3 --dry-run
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s.Handler == nil {
+				return errors.New("split is unavailable without a vault")
+			}
+			note, err := cmdpkg.ResolveVaultPath(cmd, s, withExt(args[0]))
+			if err != nil {
+				return err
+			}
+			files, err := s.Handler.WalkFiles(nil, nil, "")
+			if err != nil {
+				return err
+			}
+			plan, err := relocate.Split(s.Handler.VaultDir(), note, level, files, time.Now())
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			plan.Describe(out)
+			if dryRun {
+				fmt.Fprintln(out, "Dry run: nothing was changed.")
+				return nil
+			}
+			if err := plan.Apply(s.Handler); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Split %s into %d note(s), updated links in %d note(s).\n", filepath.Base(note), len(plan.Creates), plan.Relinked())
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&level, "level", 2, "Heading level to split on")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the new notes and link changes without applying them")
+	return cmd
+}
+
+func withExt(name string) string {
+	if filepath.Ext(name) != ".md" {
+		return name + ".md"
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package split
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+var splitNotes = map[string]string{
+	"atoms/guide.md": "---\ntitle: guide\n---\n\n## Setup\n\nInstall.\n\n## Usage\n\nRun.\n",
+}
+
+func TestSplitDryRunLeavesVaultUntouched(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, splitNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdSplit, "atoms/guide", "--dry-run")
+	if err != nil {
+		t.Fatalf("split --dry-run returned error: %v", err)
+	}
+	for _, want := range []string{"create atoms/setup.md", "create atoms/usage.md", "Dry run: nothing was changed."} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "atoms", "setup.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected dry run not to create notes, got %v", err)
+	}
+}
+
+func TestSplitCreatesNotes(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, splitNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdSplit, "atoms/guide.md")
+	if err != nil {
+		t.Fatalf("split returned error: %v", err)
+	}
+	if !strings.Contains(out, "Split guide.md into 2 note(s), updated links in 0 note(s).") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	data, err := os.ReadFile(filepath.Join(vaultDir, "atoms", "guide.md"))
+	if err != nil {
+		t.Fatalf("failed to read parent: %v", err)
+	}
+	if !strings.Contains(string(data), "- [[setup|Setup]]\n- [[usage|Usage]]") {
+		t.Fatalf("expected the parent to link to the new notes, got:\n%s", data)
+	}
+}
@@ -1,40 +1,204 @@
 package tags
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
 
+	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/spf13/cobra"
 
-	"github.com/Paintersrp/an/internal/config"
-	"github.com/Paintersrp/an/internal/parser"
+	"github.com/Paintersrp/an/internal/search"
+	indexsvc "github.com/Paintersrp/an/internal/services/index"
+	tagsvc "github.com/Paintersrp/an/internal/services/tags"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/textdiff"
+	"github.com/Paintersrp/an/internal/tui/notes"
+	tagstui "github.com/Paintersrp/an/internal/tui/tags"
 )
 
-func NewCmdTags(c *config.Config) *cobra.Command {
+func NewCmdTags(s *state.State) *cobra.Command {
+	var asJSON bool
+
 	cmd := &cobra.Command{
 		Use:   "tags",
-		Short: "Display a table of tags",
-		Long: `The tags command parses tags from notes and displays them in a sortable and navigable table,
-allowing for quick access and organization of notes by their associated tags.`,
-		Example: `
-    # Display a table of tags
-    an-cli tags
-    `,
+		Short: "Browse, rename, and merge tags",
+		Long: heredoc.Doc(`
+			Shows every tag in the vault as a table. Tags may be nested with
+			"/", as in project/apollo, and nested tags are folded under their
+			parents: use right and left (or l and h) to expand and collapse a
+			tag, space to toggle it, and e to expand or collapse everything.
+			Press enter to open the notes TUI filtered to the selected tag,
+			which includes the notes tagged below it.
+
+			Tags are read from the search index: the front matter tags list,
+			in block or flow style, and inline #tags in the note body outside
+			code. Each tag shows when a note using it last changed and, below
+			the table, the tags its notes most often carry alongside it. Use
+			--json to print the same figures, including every co-occurring
+			tag, instead of opening the table.
+		`),
+		Example: heredoc.Doc(`
+			# Browse the tag hierarchy
+			an tags
+
+			# Print tag counts, last use, and co-occurrence as JSON
+			an tags --json
+
+			# Rename a tag, including the tags nested below it
+			an tags rename project work
+
+			# Merge several tags into one
+			an tags merge golang go-lang --into go
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd, s, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print tag statistics as JSON")
+	cmd.AddCommand(newCmdRename(s), newCmdMerge(s))
+	return cmd
+}
+
+func run(cmd *cobra.Command, s *state.State, asJSON bool) error {
+	idx, err := loadIndex(s)
+	if err != nil {
+		return err
+	}
+	stats := tagsvc.Stats(idx.Documents())
+
+	if asJSON {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	tag, err := tagstui.Run(tagsvc.Tree(tagsvc.Counts(stats)), stats)
+	if err != nil || tag == "" {
+		return err
+	}
+	return notes.RunFiltered(s, s.Views, "default", []string{tag})
+}
+
+// loadIndex returns the shared search index when it is ready and builds a
+// fresh one otherwise.
+func loadIndex(s *state.State) (*search.Index, error) {
+	cfg := search.Config{}
+	if s.Workspace != nil {
+		cfg.IgnoredFolders = append([]string(nil), s.Workspace.Search.IgnoredFolders...)
+	}
+	return indexsvc.Snapshot(s.Index, s.Handler.VaultDir(), cfg, func() ([]string, error) {
+		return noteFiles(s)
+	})
+}
+
+func newCmdRename(s *state.State) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <old> <new> [--dry-run]",
+		Short: "Rename a tag across the vault.",
+		Long: heredoc.Doc(`
+			Renames a tag in the front matter and inline #tags of every note.
+			Tags nested below it move along, so renaming project to work turns
+			project/apollo into work/apollo. Use --dry-run to print a diff of
+			every change without writing it. The rename is journaled as one
+			operation, so 'an undo' reverts it.
+		`),
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return run(c)
+			renames, err := tagsvc.NewRenames(args[:1], args[1])
+			if err != nil {
+				return err
+			}
+			return retag(cmd, s, renames, dryRun)
 		},
 	}
 
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes as diffs without writing them")
 	return cmd
 }
 
-func run(c *config.Config) error {
-	p := parser.NewParser(c.MustWorkspace().VaultDir)
+func newCmdMerge(s *state.State) *cobra.Command {
+	var into string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <tag>... --into <tag> [--dry-run]",
+		Short: "Merge tags into one.",
+		Long: heredoc.Doc(`
+			Replaces every given tag with the --into tag, along with the tags
+			nested below them. Notes that end up with the same tag twice keep
+			one copy. Use --dry-run to print a diff of every change without
+			writing it. The merge is journaled as one operation, so 'an undo'
+			reverts it.
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(into) == "" {
+				return errors.New("--into is required")
+			}
+			renames, err := tagsvc.NewRenames(args, into)
+			if err != nil {
+				return err
+			}
+			return retag(cmd, s, renames, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&into, "into", "", "Tag to merge into")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes as diffs without writing them")
+	return cmd
+}
 
-	if err := p.Walk(); err != nil {
-		fmt.Println("Error:", err)
+func retag(cmd *cobra.Command, s *state.State, renames tagsvc.Renames, dryRun bool) error {
+	files, err := noteFiles(s)
+	if err != nil {
+		return err
+	}
+	plan, err := tagsvc.NewPlan(files, renames)
+	if err != nil {
 		return err
 	}
 
-	p.ShowTagTable()
+	out := cmd.OutOrStdout()
+	if len(plan.Changes) == 0 {
+		fmt.Fprintln(out, "No notes use these tags.")
+		return nil
+	}
+	if dryRun {
+		printDiffs(out, s, plan)
+		fmt.Fprintf(out, "Dry run: %d note(s) would change.\n", len(plan.Changes))
+		return nil
+	}
+	if err := plan.Apply(s.Handler); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Retagged %d note(s).\n", len(plan.Changes))
 	return nil
 }
+
+func printDiffs(out io.Writer, s *state.State, plan *tagsvc.Plan) {
+	for _, change := range plan.Changes {
+		name := change.Path
+		if rel, err := filepath.Rel(s.Handler.VaultDir(), change.Path); err == nil {
+			name = filepath.ToSlash(rel)
+		}
+		fmt.Fprint(out, textdiff.Unified("a/"+name, "b/"+name, string(change.Before), string(change.After), 3))
+	}
+}
+
+func noteFiles(s *state.State) ([]string, error) {
+	if s.Handler == nil {
+		return nil, errors.New("tags are unavailable without a vault")
+	}
+	return s.Handler.WalkFiles(nil, nil, "")
+}
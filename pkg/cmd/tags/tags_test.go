@@ -0,0 +1,103 @@
+package tags
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tagsvc "github.com/Paintersrp/an/internal/services/tags"
+	"github.com/Paintersrp/an/pkg/cmd/cmdtest"
+)
+
+var tagNotes = map[string]string{
+	"a.md": "---\ntags:\n  - project/apollo\n  - golang\n---\n\nA.\n",
+	"b.md": "B is about #golang and #go.\n",
+	"c.md": "---\ntags: [cooking]\n---\n\nC.\n",
+}
+
+func TestTagsRenameMovesNestedTags(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, tagNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdTags, "rename", "project", "work")
+	if err != nil {
+		t.Fatalf("rename returned error: %v", err)
+	}
+	if !strings.Contains(out, "Retagged 1 note(s).") {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if got := cmdtest.ReadFile(t, filepath.Join(vaultDir, "a.md")); !strings.Contains(got, "  - work/apollo\n") {
+		t.Fatalf("expected the nested tag to be renamed, got %q", got)
+	}
+}
+
+func TestTagsMergeDryRunAndApply(t *testing.T) {
+	s, vaultDir := cmdtest.NewState(t, tagNotes)
+	b := filepath.Join(vaultDir, "b.md")
+
+	out, err := cmdtest.Run(t, s, NewCmdTags, "merge", "golang", "--into", "go", "--dry-run")
+	if err != nil {
+		t.Fatalf("merge --dry-run returned error: %v", err)
+	}
+	if !strings.Contains(out, "-B is about #golang and #go.\n+B is about #go and #go.\n") ||
+		!strings.Contains(out, "Dry run: 2 note(s) would change.") {
+		t.Fatalf("unexpected dry run output:\n%s", out)
+	}
+	if got := cmdtest.ReadFile(t, b); !strings.Contains(got, "#golang") {
+		t.Fatalf("expected dry run to leave notes alone")
+	}
+
+	if _, err := cmdtest.Run(t, s, NewCmdTags, "merge", "golang", "--into", "go"); err != nil {
+		t.Fatalf("merge returned error: %v", err)
+	}
+	if got := cmdtest.ReadFile(t, filepath.Join(vaultDir, "a.md")); !strings.Contains(got, "  - go\n") {
+		t.Fatalf("expected front matter to be merged, got %q", got)
+	}
+
+	if _, err := s.Handler.Journal().Undo(1); err != nil {
+		t.Fatalf("Undo returned error: %v", err)
+	}
+	if got := cmdtest.ReadFile(t, b); !strings.Contains(got, "#golang") {
+		t.Fatalf("expected one undo to restore every note, got %q", got)
+	}
+}
+
+func TestTagsMergeRequiresInto(t *testing.T) {
+	s, _ := cmdtest.NewState(t, tagNotes)
+	if _, err := cmdtest.Run(t, s, NewCmdTags, "merge", "golang"); err == nil || !strings.Contains(err.Error(), "--into") {
+		t.Fatalf("expected a missing --into error, got %v", err)
+	}
+	out, err := cmdtest.Run(t, s, NewCmdTags, "rename", "missing", "other")
+	if err != nil || !strings.Contains(out, "No notes use these tags.") {
+		t.Fatalf("expected no changes for an unused tag, got %q (%v)", out, err)
+	}
+}
+
+func TestTagsJSONCountsFlowAndInlineTags(t *testing.T) {
+	s, _ := cmdtest.NewState(t, tagNotes)
+
+	out, err := cmdtest.Run(t, s, NewCmdTags, "--json")
+	if err != nil {
+		t.Fatalf("tags --json returned error: %v", err)
+	}
+	var stats []tagsvc.Stat
+	if err := json.Unmarshal([]byte(out), &stats); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", out, err)
+	}
+
+	byName := make(map[string]tagsvc.Stat)
+	for _, stat := range stats {
+		byName[stat.Name] = stat
+	}
+	if byName["golang"].Count != 2 || byName["cooking"].Count != 1 || byName["go"].Count != 1 {
+		t.Fatalf("expected front matter, flow, and inline tags to be counted, got %#v", stats)
+	}
+	if byName["golang"].LastUsed.IsZero() {
+		t.Fatalf("expected a last used date for golang")
+	}
+	related := byName["golang"].Related
+	if len(related) != 2 || related[0] != (tagsvc.Related{Name: "go", Count: 1}) ||
+		related[1] != (tagsvc.Related{Name: "project/apollo", Count: 1}) {
+		t.Fatalf("unexpected related tags for golang: %#v", related)
+	}
+}
@@ -0,0 +1,368 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Paintersrp/an/internal/note"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/templater"
+	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
+)
+
+// openInEditor is swapped out in tests to avoid launching an editor.
+var openInEditor = func(path string) error {
+	return note.OpenFromPath(path, false)
+}
+
+func NewCmdTemplates(s *state.State) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Inspect and manage note templates",
+		Long: heredoc.Doc(`
+			Inspect the templates available to the current workspace. Templates are
+			loaded from the workspace (<vault>/.an/templates), then your home
+			directory (~/.an/templates), then the built-in set; the first layer that
+			defines a name wins.
+		`),
+	}
+
+	cmd.AddCommand(
+		newCmdTemplatesList(s),
+		newCmdTemplatesShow(s),
+		newCmdTemplatesNew(s),
+		newCmdTemplatesEdit(s),
+		newCmdTemplatesValidate(s),
+		newCmdTemplatesFromNote(s),
+	)
+
+	return cmd
+}
+
+func newCmdTemplatesList(s *state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List templates with their source and inheritance",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSOURCE\tDESCRIPTION\tEXTENDS")
+			for _, info := range t.List() {
+				extends := "-"
+				if len(info.Chain) > 1 {
+					extends = strings.Join(info.Chain[1:], " → ")
+				}
+				description := strings.TrimSpace(info.Manifest.Description)
+				if description == "" {
+					description = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Name, info.Source, description, extends)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if partials := t.Partials(); len(partials) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nPartials: %s\n", strings.Join(partials, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func newCmdTemplatesShow(s *state.State) *cobra.Command {
+	var resolved bool
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Print a template and its manifest",
+		Long: heredoc.Doc(`
+			Prints the template exactly as stored. With --resolved the content of
+			every template listed in "extends" is merged in and the combined
+			manifest is printed first.
+
+			Example:
+			  an templates show zet
+			  an templates show project-release --resolved
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			out := cmd.OutOrStdout()
+
+			if !resolved {
+				raw, err := t.Raw(name)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(out, raw)
+				return nil
+			}
+
+			content, manifest, err := t.Resolved(name)
+			if err != nil {
+				return fmt.Errorf("resolve template %s: %w", name, err)
+			}
+			data, err := yaml.Marshal(manifest)
+			if err != nil {
+				return fmt.Errorf("encode manifest: %w", err)
+			}
+			fmt.Fprintf(out, "{{/* an:manifest\n%s*/}}\n%s", data, content)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&resolved, "resolved", false, "Merge inherited templates and print the combined manifest")
+
+	return cmd
+}
+
+func newCmdTemplatesNew(s *state.State) *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Create a workspace template and open it in your editor",
+		Long: heredoc.Doc(`
+			Creates <vault>/.an/templates/<name>.tmpl and opens it in your editor.
+			Use --from to start from a copy of an existing template.
+
+			Example:
+			  an templates new meeting
+			  an templates new weekly-plan --from week
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			if err := validateTemplateName(name); err != nil {
+				return err
+			}
+
+			content, err := t.Scaffold(name, strings.TrimSpace(from))
+			if err != nil {
+				return fmt.Errorf("prepare template: %w", err)
+			}
+			path, err := writeWorkspaceTemplate(t, name, content)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Created template %s\n", path)
+			return openInEditor(path)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Existing template to copy")
+
+	return cmd
+}
+
+func newCmdTemplatesEdit(s *state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Open a template in your editor",
+		Long: heredoc.Doc(`
+			Opens the file that defines the template. Built-in templates cannot be
+			edited in place, so they are first copied into <vault>/.an/templates
+			where the copy overrides the built-in version.
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSpace(args[0])
+			info, err := t.Info(name)
+			if err != nil {
+				return err
+			}
+
+			path := info.Path
+			if info.Source == templater.SourceEmbedded {
+				raw, err := t.Raw(name)
+				if err != nil {
+					return err
+				}
+				path, err = writeWorkspaceTemplate(t, name, raw)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Copied built-in template to %s\n", path)
+			}
+
+			return openInEditor(path)
+		},
+	}
+}
+
+func newCmdTemplatesValidate(s *state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate [name...]",
+		Short: "Check manifests and dry-render templates with sample data",
+		Long: heredoc.Doc(`
+			Parses every template manifest, resolves inheritance, and renders each
+			template with sample data. Pass names to limit the check.
+
+			Example:
+			  an templates validate
+			  an templates validate meeting zet
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+
+			names := args
+			if len(names) == 0 {
+				names = t.Templates()
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for _, name := range names {
+				if err := t.Validate(name); err != nil {
+					failed++
+					fmt.Fprintf(out, "✗ %s\n", name)
+					for _, line := range strings.Split(err.Error(), "\n") {
+						fmt.Fprintf(out, "    %s\n", line)
+					}
+					continue
+				}
+				fmt.Fprintf(out, "✓ %s\n", name)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d templates failed validation", failed, len(names))
+			}
+			return nil
+		},
+	}
+}
+
+func newCmdTemplatesFromNote(s *state.State) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "from-note <note>",
+		Short: "Create a workspace template from an existing note",
+		Long: heredoc.Doc(`
+			Copies a note into <vault>/.an/templates/<name>.tmpl. The title, created
+			date, tags, and upstream link are replaced with template placeholders,
+			and every other front matter key becomes a manifest field you can edit.
+
+			Example:
+			  an templates from-note atoms/apollo-kickoff.md --name kickoff
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := templaterFor(s)
+			if err != nil {
+				return err
+			}
+			name = strings.TrimSpace(name)
+			if err := validateTemplateName(name); err != nil {
+				return err
+			}
+
+			path, err := cmdpkg.ResolveVaultPath(cmd, s, args[0])
+			if err != nil {
+				return err
+			}
+			if filepath.Ext(path) == "" {
+				path += ".md"
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read note: %w", err)
+			}
+
+			source := filepath.ToSlash(path)
+			if rel, err := filepath.Rel(s.Vault, path); err == nil {
+				source = filepath.ToSlash(rel)
+			}
+			content, err := templater.FromNote(name, source, string(data))
+			if err != nil {
+				return fmt.Errorf("convert note: %w", err)
+			}
+
+			target, err := writeWorkspaceTemplate(t, name, content)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created template %s from %s\n", target, source)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Name of the template to create")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func templaterFor(s *state.State) (*templater.Templater, error) {
+	if s == nil || s.Templater == nil {
+		return nil, errors.New("templates are not configured")
+	}
+	return s.Templater, nil
+}
+
+func validateTemplateName(name string) error {
+	if name == "" {
+		return errors.New("template name cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid template name %q", name)
+	}
+	return nil
+}
+
+// writeWorkspaceTemplate creates <vault>/.an/templates/<name>.tmpl, refusing to
+// overwrite an existing file.
+func writeWorkspaceTemplate(t *templater.Templater, name, content string) (string, error) {
+	dir := t.WorkspaceTemplateDir()
+	if dir == "" {
+		return "", errors.New("no workspace vault is configured")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create template directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name+".tmpl")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("template file %s already exists", path)
+		}
+		return "", fmt.Errorf("create template: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return "", fmt.Errorf("write template: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,208 @@
+package templates
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/config"
+	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/templater"
+)
+
+func newTestState(t *testing.T) *state.State {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	vault := t.TempDir()
+	ws := &config.Workspace{VaultDir: vault}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("failed to create templater: %v", err)
+	}
+	return &state.State{Workspace: ws, Templater: tmpl, Vault: vault}
+}
+
+func stubEditor(t *testing.T) *[]string {
+	t.Helper()
+	var opened []string
+	prev := openInEditor
+	openInEditor = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+	t.Cleanup(func() { openInEditor = prev })
+	return &opened
+}
+
+func runTemplates(t *testing.T, s *state.State, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCmdTemplates(s)
+	cmd.SetArgs(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestTemplatesListShowsSourceAndExtends(t *testing.T) {
+	s := newTestState(t)
+
+	out, err := runTemplates(t, s, "list")
+	if err != nil {
+		t.Fatalf("list returned error: %v", err)
+	}
+
+	var release string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "project-release ") {
+			release = line
+		}
+	}
+	if release == "" {
+		t.Fatalf("expected project-release in list output:\n%s", out)
+	}
+	if !strings.Contains(release, "embedded") || !strings.Contains(release, "project") {
+		t.Fatalf("expected source and extends chain, got %q", release)
+	}
+}
+
+func TestTemplatesNewFromExistingAndEdit(t *testing.T) {
+	s := newTestState(t)
+	opened := stubEditor(t)
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "standup") })
+
+	if _, err := runTemplates(t, s, "new", "standup", "--from", "zet"); err != nil {
+		t.Fatalf("new returned error: %v", err)
+	}
+
+	path := filepath.Join(s.Vault, ".an", "templates", "standup.tmpl")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected template file: %v", err)
+	}
+	if !strings.Contains(string(content), "name: standup") {
+		t.Fatalf("expected renamed manifest, got:\n%s", content)
+	}
+	if len(*opened) != 1 || (*opened)[0] != path {
+		t.Fatalf("expected editor to open %s, got %v", path, *opened)
+	}
+
+	if _, err := runTemplates(t, s, "new", "standup"); err == nil {
+		t.Fatalf("expected error when template file already exists")
+	}
+
+	if _, err := runTemplates(t, s, "edit", "day"); err != nil {
+		t.Fatalf("edit returned error: %v", err)
+	}
+	dayPath := filepath.Join(s.Vault, ".an", "templates", "day.tmpl")
+	if _, err := os.Stat(dayPath); err != nil {
+		t.Fatalf("expected built-in template to be copied for editing: %v", err)
+	}
+	if last := (*opened)[len(*opened)-1]; last != dayPath {
+		t.Fatalf("expected editor to open %s, got %s", dayPath, last)
+	}
+}
+
+func TestTemplatesValidateAndShowResolved(t *testing.T) {
+	s := newTestState(t)
+
+	out, err := runTemplates(t, s, "validate")
+	if err != nil {
+		t.Fatalf("validate returned error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "✓ zet") {
+		t.Fatalf("expected zet to validate, got:\n%s", out)
+	}
+
+	out, err = runTemplates(t, s, "show", "project-release", "--resolved")
+	if err != nil {
+		t.Fatalf("show returned error: %v", err)
+	}
+	if !strings.HasPrefix(out, "{{/* an:manifest\n") || !strings.Contains(out, "extends:") {
+		t.Fatalf("expected resolved manifest header, got:\n%s", out)
+	}
+}
+
+func TestTemplatesMalformedManifestStillLoads(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { delete(templater.AvailableTemplates, "broken") })
+
+	vault := t.TempDir()
+	path := filepath.Join(vault, ".an", "templates", "broken.tmpl")
+	raw := "{{/* an:manifest\nfields: [unclosed\n*/}}\nBody\n"
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	ws := &config.Workspace{VaultDir: vault}
+	tmpl, err := templater.NewTemplater(ws)
+	if err != nil {
+		t.Fatalf("expected a malformed manifest not to stop loading, got %v", err)
+	}
+	s := &state.State{Workspace: ws, Templater: tmpl, Vault: vault}
+	opened := stubEditor(t)
+
+	out, err := runTemplates(t, s, "validate")
+	if err == nil || !strings.Contains(out, "✗ broken") || !strings.Contains(out, "failed to parse manifest") {
+		t.Fatalf("expected validate to report the manifest, got %v:\n%s", err, out)
+	}
+	if !strings.Contains(out, "✓ zet") {
+		t.Fatalf("expected other templates to still validate, got:\n%s", out)
+	}
+
+	if out, err := runTemplates(t, s, "show", "broken"); err != nil || out != raw {
+		t.Fatalf("expected show to print the raw file, got %q, %v", out, err)
+	}
+	if _, err := runTemplates(t, s, "edit", "broken"); err != nil {
+		t.Fatalf("edit returned error: %v", err)
+	}
+	if len(*opened) != 1 || (*opened)[0] != path {
+		t.Fatalf("expected editor to open %s, got %v", path, *opened)
+	}
+	if _, err := tmpl.Execute("broken", nil); err == nil {
+		t.Fatalf("expected rendering a malformed template to fail")
+	}
+}
+
+func TestTemplatesFromNote(t *testing.T) {
+	s := newTestState(t)
+	s.Config = &config.Config{
+		Workspaces:       map[string]*config.Workspace{"default": s.Workspace},
+		CurrentWorkspace: "default",
+	}
+	if err := s.Config.ActivateWorkspace("default"); err != nil {
+		t.Fatalf("failed to activate workspace: %v", err)
+	}
+
+	notePath := filepath.Join(s.Vault, "atoms", "kickoff.md")
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		t.Fatalf("failed to create note directory: %v", err)
+	}
+	note := "---\ntitle: kickoff\ncreated: 20240301\nstatus: open\n---\n\n# kickoff\n"
+	if err := os.WriteFile(notePath, []byte(note), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	out, err := runTemplates(t, s, "from-note", "atoms/kickoff", "--name", "meeting")
+	if err != nil {
+		t.Fatalf("from-note returned error: %v\n%s", err, out)
+	}
+
+	content, err := os.ReadFile(filepath.Join(s.Vault, ".an", "templates", "meeting.tmpl"))
+	if err != nil {
+		t.Fatalf("expected template file: %v", err)
+	}
+	if !strings.Contains(string(content), "  - key: status\n") || !strings.Contains(string(content), "title: {{.Title}}") {
+		t.Fatalf("unexpected template content:\n%s", content)
+	}
+
+	if _, err := runTemplates(t, s, "from-note", "atoms/kickoff.md"); err == nil {
+		t.Fatalf("expected --name to be required")
+	}
+}
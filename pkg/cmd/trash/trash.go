@@ -1,24 +1,37 @@
 package trash
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/state"
+	"github.com/Paintersrp/an/internal/timeutil"
 	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
 	"github.com/spf13/cobra"
 )
 
 func NewCmdTrash(s *state.State) *cobra.Command {
+	var reason string
+
 	cmd := &cobra.Command{
 		Use:   "trash [path]",
 		Short: "Move a note to the trash.",
 		Long: heredoc.Doc(`
 			This command moves a note to the 'trash' subdirectory.
 			Provide the path to the note you want to move to the trash.
+			The original path, the time, and an optional reason are recorded
+			next to the trashed note.
 
 			Example:
 			  an trash /path/to/note
+			  an trash atoms/idea.md --reason "superseded"
+			  an trash list
+			  an trash purge --older-than 30d --dry-run
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
@@ -29,9 +42,118 @@ func NewCmdTrash(s *state.State) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return s.Handler.Trash(path)
+			return s.Handler.TrashWithReason(path, reason)
+		},
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the note is being trashed")
+	cmd.AddCommand(newCmdList(s), newCmdPurge(s))
+	return cmd
+}
+
+func newCmdList(s *state.State) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the notes in the trash.",
+		Long: heredoc.Doc(`
+			Lists the notes in the trash, oldest first, with the time they were
+			trashed, the path they were trashed from, and the reason if one was
+			given.
+
+			Example:
+			  an trash list
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s.Handler == nil {
+				return errors.New("trash is unavailable without a vault")
+			}
+			entries, err := s.Handler.TrashEntries()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "Trash is empty.")
+				return nil
+			}
+			return printEntries(cmd.OutOrStdout(), entries)
+		},
+	}
+}
+
+func newCmdPurge(s *state.State) *cobra.Command {
+	var olderThan string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "purge [--older-than 30d] [--dry-run]",
+		Short: "Permanently delete old notes from the trash.",
+		Long: heredoc.Doc(`
+			Permanently deletes the notes that have been in the trash for longer
+			than --older-than. Durations accept days ("30d") and weeks ("4w") as
+			well as Go durations ("36h"). Without --older-than the workspace's
+			trash.retention setting is used.
+
+			A purge is recorded in the operation journal as one step, so a
+			single 'an undo' brings every purged note back.
+
+			Example:
+			  an trash purge --older-than 30d --dry-run
+			  an trash purge --older-than 0d
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s.Handler == nil {
+				return errors.New("trash is unavailable without a vault")
+			}
+
+			spec := strings.TrimSpace(olderThan)
+			if spec == "" && s.Workspace != nil {
+				spec = strings.TrimSpace(s.Workspace.Trash.Retention)
+			}
+			if spec == "" {
+				return errors.New("--older-than is required when trash.retention is not set")
+			}
+			age, err := timeutil.ParseAge(spec)
+			if err != nil {
+				return err
+			}
+
+			purged, err := s.Handler.PurgeTrash(age, dryRun)
+			if len(purged) > 0 {
+				if printErr := printEntries(cmd.OutOrStdout(), purged); printErr != nil {
+					return printErr
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case len(purged) == 0:
+				fmt.Fprintf(cmd.OutOrStdout(), "Nothing in the trash is older than %s.\n", spec)
+			case dryRun:
+				fmt.Fprintf(cmd.OutOrStdout(), "Would purge %d note(s).\n", len(purged))
+			default:
+				fmt.Fprintf(cmd.OutOrStdout(), "Purged %d note(s).\n", len(purged))
+			}
+			return nil
 		},
 	}
 
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Purge notes trashed longer ago than this (e.g. 30d)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the notes that would be purged without deleting them")
 	return cmd
 }
+
+func printEntries(out io.Writer, entries []handler.TrashEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\n",
+			entry.TrashedAt.Local().Format("2006-01-02 15:04"),
+			entry.Original,
+			entry.Reason,
+		)
+	}
+	return w.Flush()
+}
@@ -1,9 +1,14 @@
 package trash
 
 import (
+	"bytes"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/state"
 )
 
@@ -24,3 +29,69 @@ func TestTrashCommandRequiresArgument(t *testing.T) {
 		t.Fatalf("expected error message %q, got %q", "path argument is required", err.Error())
 	}
 }
+
+func TestTrashListAndPurge(t *testing.T) {
+	vaultDir := t.TempDir()
+	notePath := filepath.Join(vaultDir, "atoms", "idea.md")
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(notePath, []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	s := &state.State{Handler: handler.NewFileHandler(vaultDir)}
+	if err := s.Handler.TrashWithReason(notePath, "superseded"); err != nil {
+		t.Fatalf("TrashWithReason returned error: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := NewCmdTrash(s)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("trash %v returned error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	if out := run("list"); !strings.Contains(out, "atoms/idea.md") || !strings.Contains(out, "superseded") {
+		t.Fatalf("unexpected list output %q", out)
+	}
+
+	if out := run("purge", "--older-than", "30d"); !strings.Contains(out, "Nothing in the trash is older than 30d.") {
+		t.Fatalf("unexpected purge output %q", out)
+	}
+
+	if out := run("purge", "--older-than", "0d", "--dry-run"); !strings.Contains(out, "Would purge 1 note(s).") {
+		t.Fatalf("unexpected dry-run output %q", out)
+	}
+	trashed := filepath.Join(vaultDir, "trash", "atoms", "idea.md")
+	if _, err := os.Stat(trashed); err != nil {
+		t.Fatalf("expected dry run to keep the note: %v", err)
+	}
+
+	if out := run("purge", "--older-than", "0d"); !strings.Contains(out, "Purged 1 note(s).") {
+		t.Fatalf("unexpected purge output %q", out)
+	}
+	if _, err := os.Stat(trashed); !os.IsNotExist(err) {
+		t.Fatalf("expected note purged, got %v", err)
+	}
+	if out := run("list"); strings.TrimSpace(out) != "Trash is empty." {
+		t.Fatalf("unexpected list output %q", out)
+	}
+}
+
+func TestTrashPurgeRequiresAge(t *testing.T) {
+	s := &state.State{Handler: handler.NewFileHandler(t.TempDir())}
+	cmd := NewCmdTrash(s)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"purge"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected purge without --older-than or trash.retention to fail")
+	}
+}
@@ -0,0 +1,54 @@
+package undo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/Paintersrp/an/internal/oplog"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+func NewCmdUndo(s *state.State) *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "undo [--steps N]",
+		Short: "Undo the most recent vault operations.",
+		Long: heredoc.Doc(`
+			Reverts the most recent operations recorded in the vault's operation
+			journal (.an/ops.log): archiving, trashing, restoring, deleting, moving,
+			renaming, copying, editing, and task toggles.
+
+			An operation is only undone when the note still matches what the
+			operation left behind, so later edits are never overwritten. Use
+			'an history' to see what would be undone.
+
+			Example:
+			  an undo
+			  an undo --steps 3
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			journal := s.Handler.Journal()
+			if journal == nil {
+				return errors.New("operation journal is unavailable without a vault")
+			}
+
+			undone, err := journal.Undo(steps)
+			for _, entry := range undone {
+				fmt.Fprintf(cmd.OutOrStdout(), "Undid #%d %s\n", entry.ID, entry.Describe())
+			}
+			if errors.Is(err, oplog.ErrNothingToUndo) {
+				fmt.Fprintln(cmd.OutOrStdout(), "Nothing to undo.")
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "Number of operations to undo")
+	return cmd
+}
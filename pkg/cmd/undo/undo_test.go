@@ -0,0 +1,53 @@
+package undo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paintersrp/an/internal/handler"
+	"github.com/Paintersrp/an/internal/state"
+)
+
+func TestUndoRestoresTrashedNote(t *testing.T) {
+	vaultDir := t.TempDir()
+	notePath := filepath.Join(vaultDir, "atoms", "note.md")
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(notePath, []byte("content"), 0o644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	s := &state.State{Handler: handler.NewFileHandler(vaultDir)}
+	if err := s.Handler.Trash(notePath); err != nil {
+		t.Fatalf("Trash returned error: %v", err)
+	}
+
+	cmd := NewCmdUndo(s)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("undo returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "trash atoms/note.md -> trash/atoms/note.md") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	if _, err := os.Stat(notePath); err != nil {
+		t.Fatalf("expected note restored: %v", err)
+	}
+
+	out.Reset()
+	cmd = NewCmdUndo(s)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--steps", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("undo returned error: %v", err)
+	}
+	if strings.TrimSpace(out.String()) != "Nothing to undo." {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+}
@@ -1,27 +1,42 @@
 package untrash
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/spf13/cobra"
 
+	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/state"
 	cmdpkg "github.com/Paintersrp/an/pkg/cmd"
 )
 
+// selectEntry picks one of the trashed notes, starting from query. It is a
+// variable so tests can replace the interactive finder.
+var selectEntry = findEntry
+
 func NewCmdUntrash(s *state.State) *cobra.Command {
+	var find string
+
 	cmd := &cobra.Command{
-		Use:   "untrash [path]",
+		Use:   "untrash [path] | --find <query>",
 		Short: "Restore a note from the trash.",
 		Long: heredoc.Doc(`
-			This command restores a note from the 'trash' subdirectory.
-			Provide the path to the note you want to untrash.
+			This command restores a note from the 'trash' subdirectory to the
+			path it was trashed from. Provide the path to the note you want to
+			untrash, or use --find to pick it with the fuzzy finder.
 
 			Example:
 			  an untrash /path/to/trashed/note
+			  an untrash --find "meeting"
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("find") {
+				return untrashFind(cmd, s, find)
+			}
 			if len(args) < 1 {
 				_ = cmd.Help()
 				return fmt.Errorf("path argument is required")
@@ -34,5 +49,62 @@ func NewCmdUntrash(s *state.State) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().StringVar(&find, "find", "", "Pick the note to restore with the fuzzy finder, starting from this query")
 	return cmd
 }
+
+func untrashFind(cmd *cobra.Command, s *state.State, query string) error {
+	if s.Handler == nil {
+		return errors.New("trash is unavailable without a vault")
+	}
+	entries, err := s.Handler.TrashEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New("trash is empty")
+	}
+
+	idx, err := selectEntry(entries, query)
+	if err != nil {
+		if errors.Is(err, fuzzyfinder.ErrAbort) {
+			return errors.New("no note selected")
+		}
+		return err
+	}
+
+	entry := entries[idx]
+	if err := s.Handler.Untrash(entry.Path); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Restored %s\n", entry.Original)
+	return nil
+}
+
+func findEntry(entries []handler.TrashEntry, query string) (int, error) {
+	options := []fuzzyfinder.Option{
+		fuzzyfinder.WithHeader("Restore from trash"),
+		fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
+			if i == -1 {
+				return ""
+			}
+			content, err := os.ReadFile(entries[i].Path)
+			if err != nil {
+				return "Error reading file"
+			}
+			return string(content)
+		}),
+	}
+	if query != "" {
+		options = append(options, fuzzyfinder.WithQuery(query))
+	}
+
+	return fuzzyfinder.Find(entries, func(i int) string {
+		entry := entries[i]
+		label := fmt.Sprintf("%s  (%s)", entry.Original, entry.TrashedAt.Local().Format("2006-01-02"))
+		if entry.Reason != "" {
+			label += " " + entry.Reason
+		}
+		return label
+	}, options...)
+}
@@ -1,9 +1,15 @@
 package untrash
 
 import (
+	"bytes"
+	"errors"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Paintersrp/an/internal/handler"
 	"github.com/Paintersrp/an/internal/state"
 )
 
@@ -24,3 +30,55 @@ func TestUntrashCommandRequiresArgument(t *testing.T) {
 		t.Fatalf("expected error message %q, got %q", "path argument is required", err.Error())
 	}
 }
+
+func TestUntrashFindRestoresSelectedNote(t *testing.T) {
+	vaultDir := t.TempDir()
+	s := &state.State{Handler: handler.NewFileHandler(vaultDir)}
+	for _, name := range []string{"alpha.md", "beta.md"} {
+		path := filepath.Join(vaultDir, "atoms", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatalf("failed to write note: %v", err)
+		}
+		if err := s.Handler.Trash(path); err != nil {
+			t.Fatalf("Trash returned error: %v", err)
+		}
+	}
+
+	previous := selectEntry
+	t.Cleanup(func() { selectEntry = previous })
+	var gotQuery string
+	selectEntry = func(entries []handler.TrashEntry, query string) (int, error) {
+		gotQuery = query
+		for i, entry := range entries {
+			if entry.Original == "atoms/beta.md" {
+				return i, nil
+			}
+		}
+		return -1, errors.New("beta not listed")
+	}
+
+	cmd := NewCmdUntrash(s)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--find", "bet"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("untrash --find returned error: %v", err)
+	}
+
+	if gotQuery != "bet" {
+		t.Fatalf("expected finder to start from the query, got %q", gotQuery)
+	}
+	if strings.TrimSpace(out.String()) != "Restored atoms/beta.md" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "atoms", "beta.md")); err != nil {
+		t.Fatalf("expected beta restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "trash", "atoms", "alpha.md")); err != nil {
+		t.Fatalf("expected alpha to stay in the trash: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package arg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// StdinArg is the argument that asks a command to read its content from stdin.
+const StdinArg = "-"
+
+// Stdin is the reader used for piped content. Tests replace it.
+var Stdin io.Reader = os.Stdin
+
+var (
+	markdownPattern = regexp.MustCompile(
+		`^(#{1,6}\s|` + "```" + `|~~~|>\s|\|.*\|\s*$|- \[[ xX]\]\s)|\[\[[^\]]+\]\]|\[[^\]]+\]\([^)]+\)`,
+	)
+	listPattern     = regexp.MustCompile(`^\s{0,3}([-*+]|\d+[.)])\s+\S`)
+	timestampPrefix = regexp.MustCompile(`^\[?\d{4}[-/]\d{2}[-/]\d{2}[T ]\d{2}:\d{2}`)
+	logLevelPattern = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|WARN|WARNING|ERROR|FATAL|PANIC)\b`)
+	backtickRun     = regexp.MustCompile("`{3,}")
+)
+
+// StdinIsTerminal reports whether stdin is an interactive terminal rather
+// than piped input.
+func StdinIsTerminal() bool {
+	file, ok := Stdin.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// ReadStdin reads all piped input. It refuses to block on an interactive
+// terminal, where nothing has been piped in.
+func ReadStdin() (string, error) {
+	if StdinIsTerminal() {
+		return "", errors.New("no input piped to stdin")
+	}
+
+	data, err := io.ReadAll(Stdin)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	content := string(data)
+	if strings.TrimSpace(content) == "" {
+		return "", errors.New("stdin is empty")
+	}
+	return content, nil
+}
+
+// HandleStdinContent reads piped input and fences it with lang. Without a
+// language, input that does not look like Markdown is fenced automatically.
+func HandleStdinContent(lang string) (string, error) {
+	content, err := ReadStdin()
+	if err != nil {
+		return "", err
+	}
+	return FenceContent(content, lang), nil
+}
+
+// FenceContent wraps content in a fenced code block when lang is set or the
+// content does not look like Markdown. Diffs are tagged as such.
+func FenceContent(content, lang string) string {
+	lang = strings.TrimSpace(lang)
+	if lang == "" {
+		if LooksLikeMarkdown(content) {
+			return content
+		}
+		if looksLikeDiff(content) {
+			lang = "diff"
+		}
+	}
+	return Fence(content, lang)
+}
+
+// Fence wraps content in a fenced code block. The fence is longer than any
+// backtick run inside the content so embedded fences stay intact.
+func Fence(content, lang string) string {
+	fence := "```"
+	for _, run := range backtickRun.FindAllString(content, -1) {
+		if len(run) >= len(fence) {
+			fence = strings.Repeat("`", len(run)+1)
+		}
+	}
+
+	body := strings.TrimRight(content, "\n")
+	return fence + lang + "\n" + body + "\n" + fence + "\n"
+}
+
+// LooksLikeMarkdown guesses whether content is Markdown prose rather than
+// command output, logs, diffs, or code.
+func LooksLikeMarkdown(content string) bool {
+	if looksLikeDiff(content) {
+		return false
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(lines[0]) == "---" {
+		return true
+	}
+
+	var total, structural, codeLike int
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		total++
+		switch {
+		case markdownPattern.MatchString(line):
+			return true
+		case listPattern.MatchString(line):
+			structural++
+		case isCodeLike(line):
+			codeLike++
+		}
+	}
+	if total == 0 || structural*2 >= total {
+		return true
+	}
+	return codeLike*2 < total
+}
+
+func looksLikeDiff(content string) bool {
+	for _, line := range strings.SplitN(content, "\n", 20) {
+		if strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "@@ -") ||
+			strings.HasPrefix(line, "+++ ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isCodeLike(line string) bool {
+	if strings.Contains(line, "\t") || strings.HasPrefix(line, "    ") {
+		return true
+	}
+	trimmed := strings.TrimSpace(line)
+	if timestampPrefix.MatchString(trimmed) || logLevelPattern.MatchString(trimmed) {
+		return true
+	}
+	switch trimmed[len(trimmed)-1] {
+	case '{', '}', ';', '(', ')', ']', '\\':
+		return true
+	}
+	for _, marker := range []string{"   ", "=>", ":=", "::"} {
+		if strings.Contains(trimmed, marker) {
+			return true
+		}
+	}
+	return strings.HasPrefix(trimmed, "$ ")
+}
@@ -0,0 +1,64 @@
+package arg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLooksLikeMarkdown(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		expect  bool
+	}{
+		{name: "heading", content: "# Notes\nsome text\n", expect: true},
+		{name: "prose", content: "Remember to follow up with the team tomorrow.\n", expect: true},
+		{name: "list", content: "- milk\n- eggs\n", expect: true},
+		{name: "wiki link", content: "see [[apollo]] for details\n", expect: true},
+		{name: "diff", content: "diff --git a/x b/x\n--- a/x\n+++ b/x\n@@ -1 +1 @@\n-# old\n+# new\n", expect: false},
+		{name: "log", content: "2024-03-01 10:00:01 INFO starting\n2024-03-01 10:00:02 ERROR failed\n", expect: false},
+		{name: "code", content: "func main() {\n\tfmt.Println(\"hi\")\n}\n", expect: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := LooksLikeMarkdown(tc.content); got != tc.expect {
+				t.Fatalf("LooksLikeMarkdown(%q) = %v, want %v", tc.content, got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestFenceContent(t *testing.T) {
+	if got := FenceContent("# Title\n", ""); got != "# Title\n" {
+		t.Fatalf("expected Markdown to pass through, got %q", got)
+	}
+	if got := FenceContent("@@ -1 +1 @@\n-a\n+b\n", ""); !strings.HasPrefix(got, "```diff\n") {
+		t.Fatalf("expected diff fence, got %q", got)
+	}
+	if got := FenceContent("# Title\n", "md"); got != "```md\n# Title\n```\n" {
+		t.Fatalf("expected explicit fence, got %q", got)
+	}
+	if got := Fence("```go\nx\n```", ""); !strings.HasPrefix(got, "````\n") || !strings.HasSuffix(got, "\n````\n") {
+		t.Fatalf("expected a longer fence around embedded fences, got %q", got)
+	}
+}
+
+func TestHandleStdinContent(t *testing.T) {
+	prev := Stdin
+	t.Cleanup(func() { Stdin = prev })
+
+	Stdin = strings.NewReader("ok  \tgithub.com/x\t0.1s\nFAIL\tgithub.com/y\t0.2s\n")
+	got, err := HandleStdinContent("")
+	if err != nil {
+		t.Fatalf("HandleStdinContent returned error: %v", err)
+	}
+	if !strings.HasPrefix(got, "```\n") {
+		t.Fatalf("expected command output to be fenced, got %q", got)
+	}
+
+	Stdin = strings.NewReader("  \n")
+	if _, err := HandleStdinContent(""); err == nil {
+		t.Fatalf("expected error for empty stdin")
+	}
+}
@@ -14,7 +14,7 @@ func HandleTags(args []string) []string {
 	)
 
 	if len(args) > 1 {
-		tags, err = utils.ValidateInput(args[1])
+		tags, err = utils.ValidateTags(args[1])
 		if err != nil {
 			fmt.Printf("error processing tags argument: %s", err)
 			os.Exit(1)
@@ -0,0 +1,23 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func AddStdin(cmd *cobra.Command) {
+	cmd.Flags().
+		Bool("stdin", false, "Read note content from stdin, fencing it in a code block when it is not Markdown.")
+}
+
+func HandleStdin(cmd *cobra.Command) (bool, error) {
+	return cmd.Flags().GetBool("stdin")
+}
+
+func AddFence(cmd *cobra.Command) {
+	cmd.Flags().
+		String("fence", "", "Wrap the content in a fenced code block with the given language.")
+}
+
+func HandleFence(cmd *cobra.Command) (string, error) {
+	return cmd.Flags().GetString("fence")
+}
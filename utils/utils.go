@@ -11,15 +11,6 @@ import (
 	"github.com/muesli/termenv"
 )
 
-func AppendIfNotExists(slice []string, value string) []string {
-	for _, v := range slice {
-		if v == value {
-			return slice
-		}
-	}
-	return append(slice, value)
-}
-
 func ValidateInput(input string) ([]string, error) {
 	if input == "" {
 		return []string{}, nil
@@ -37,6 +28,27 @@ func ValidateInput(input string) ([]string, error) {
 	return items, nil
 }
 
+// ValidateTags is ValidateInput for tags, which may be nested with "/"
+// such as project/apollo.
+func ValidateTags(input string) ([]string, error) {
+	if input == "" {
+		return []string{}, nil
+	}
+
+	tags := strings.Split(input, " ")
+	for _, tag := range tags {
+		for _, segment := range strings.Split(tag, "/") {
+			if !isValidInput(segment) {
+				return nil, fmt.Errorf(
+					"invalid tag '%s': Tags must only contain alphanumeric characters, hyphens, and underscores, nested with '/'",
+					tag,
+				)
+			}
+		}
+	}
+	return tags, nil
+}
+
 func isValidInput(input string) bool {
 	return regexp.MustCompile(`^[a-zA-Z0-9-_]+$`).MatchString(input)
 }
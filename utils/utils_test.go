@@ -46,3 +46,15 @@ This is a sentence with enough words to require wrapping when rendered into a pr
 		}
 	}
 }
+
+func TestValidateTagsAllowsNesting(t *testing.T) {
+	tags, err := ValidateTags("project/apollo go")
+	if err != nil || len(tags) != 2 || tags[0] != "project/apollo" {
+		t.Fatalf("expected nested tags to be accepted, got %v, %v", tags, err)
+	}
+	for _, input := range []string{"project//apollo", "/project", "bad!tag"} {
+		if _, err := ValidateTags(input); err == nil {
+			t.Fatalf("expected %q to be rejected", input)
+		}
+	}
+}